@@ -12,13 +12,19 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"app/xonvera-core/internal/adapters/routes"
 	"app/xonvera-core/internal/dependencies"
 	"app/xonvera-core/internal/infrastructure/database"
 	"app/xonvera-core/internal/infrastructure/graceful"
 	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/infrastructure/scheduler"
 	"app/xonvera-core/internal/utils/validator"
 
 	"github.com/gofiber/fiber/v3"
@@ -47,34 +53,95 @@ func main() {
 		return
 	}
 
+	// Handle one-off commands if requested
+	if handled := handleCommands(app, migrationFlags); handled {
+		return
+	}
+
 	// Get Fiber app (middleware already configured in server package)
 	fiberApp := app.FiberApp
 
 	// Setup routes
 	routes.SetupRoutes(fiberApp, app)
 
+	// Background jobs stop on the same signals that shut the server down
+	jobCtx, stopJobs := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopJobs()
+	startJobs(jobCtx, app)
+
 	startServer(app, fiberApp)
 }
 
+func startJobs(ctx context.Context, app *dependencies.Application) {
+	if !app.Config.Scheduler.Enabled {
+		logger.Info("Scheduler disabled, background jobs are not started")
+		return
+	}
+
+	scheduler.Start(ctx, "recurring-invoices", app.Config.Scheduler.Interval, func(ctx context.Context) error {
+		_, err := app.RecurringInvoiceService.RunDue(ctx, time.Now())
+		return err
+	})
+
+	scheduler.Start(ctx, "invoice-reminders", app.Config.Scheduler.Interval, func(ctx context.Context) error {
+		_, err := app.ReminderService.RunDue(ctx, time.Now())
+		return err
+	})
+
+	scheduler.Start(ctx, "invoice-exports", app.Config.Scheduler.Interval, func(ctx context.Context) error {
+		_, err := app.InvoiceExportService.RunPending(ctx, time.Now())
+		return err
+	})
+
+	scheduler.Start(ctx, "document-cleanup", app.Config.Document.CleanupInterval, func(ctx context.Context) error {
+		_, err := app.DocumentService.Cleanup(ctx, time.Now())
+		return err
+	})
+}
+
 type migrationFlags struct {
-	run   bool
-	down  bool
-	reset bool
+	run             bool
+	down            bool
+	reset           bool
+	dedupeCustomers bool
+	dryRun          bool
 }
 
 func parseMigrationFlags() migrationFlags {
 	runMigrations := flag.Bool("migrate", false, "Run database migrations")
 	migrateDown := flag.Bool("migrate-down", false, "Rollback database migrations by one step")
 	migrateReset := flag.Bool("migrate-reset", false, "Reset database migrations")
+	dedupeCustomers := flag.Bool("dedupe-customers", false, "Create customers from free text invoice customers and link them")
+	dryRun := flag.Bool("dry-run", false, "Report what a one-off command would change without writing")
 	flag.Parse()
 
 	return migrationFlags{
-		run:   *runMigrations,
-		down:  *migrateDown,
-		reset: *migrateReset,
+		run:             *runMigrations,
+		down:            *migrateDown,
+		reset:           *migrateReset,
+		dedupeCustomers: *dedupeCustomers,
+		dryRun:          *dryRun,
 	}
 }
 
+func handleCommands(app *dependencies.Application, flags migrationFlags) bool {
+	if !flags.dedupeCustomers {
+		return false
+	}
+
+	res, err := app.CustomerService.DedupeFromInvoices(context.Background(), flags.dryRun)
+	if err != nil {
+		logger.Fatal("Failed to dedupe customers", zap.Error(err))
+	}
+
+	logger.Info("Customers deduplicated",
+		zap.Bool("dry_run", flags.dryRun),
+		zap.Int("customers_created", res.CustomersCreated),
+		zap.Int("invoices_linked", res.InvoicesLinked),
+	)
+	return true
+}
+
 func handleMigrations(app *dependencies.Application, flags migrationFlags) bool {
 	if !flags.run && !flags.down && !flags.reset {
 		return false
@@ -0,0 +1,259 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type BusinessProfileHandler struct {
+	service portService.BusinessProfileService
+	rto     time.Duration
+}
+
+func NewBusinessProfileHandler(service portService.BusinessProfileService, rto time.Duration) *BusinessProfileHandler {
+	return &BusinessProfileHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles listing business profiles
+// @Summary Get business profiles
+// @Description List the business profiles of the user with their bank accounts, default first
+// @Tags BusinessProfile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Resp
+// @Router /business-profiles [get]
+func (h *BusinessProfileHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	res, err := h.service.Get(ctx, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// GetByID handles getting a business profile
+// @Summary Get business profile
+// @Description Get a business profile by ID
+// @Tags BusinessProfile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Business Profile ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /business-profiles/{id} [get]
+func (h *BusinessProfileHandler) GetByID(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid business profile ID format"})
+	}
+
+	res, err := h.service.GetByID(ctx, uint(id), userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Create handles business profile creation
+// @Summary Create business profile
+// @Description Add a business profile, the first one becomes the default
+// @Tags BusinessProfile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.BusinessProfileRequest true "Business Profile Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /business-profiles [post]
+func (h *BusinessProfileHandler) Create(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.BusinessProfileRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in business profile service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Create(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Update handles business profile update
+// @Summary Update business profile
+// @Description Update a business profile, bank accounts are replaced by the given list
+// @Tags BusinessProfile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Business Profile ID"
+// @Param request body domain.BusinessProfileRequest true "Business Profile Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /business-profiles/{id} [put]
+func (h *BusinessProfileHandler) Update(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.BusinessProfileRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid business profile ID format"})
+	}
+	req.ID = uint(id)
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in business profile service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	if err := h.service.Update(ctx, &req); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
+
+// UploadLogo handles business profile logo upload
+// @Summary Upload business profile logo
+// @Description Upload a png or jpeg logo up to 1MB, printed on invoice PDFs
+// @Tags BusinessProfile
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Business Profile ID"
+// @Param logo formData file true "Logo image"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /business-profiles/{id}/logo [put]
+func (h *BusinessProfileHandler) UploadLogo(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.BusinessProfileLogoRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid business profile ID format"})
+	}
+	req.ID = uint(id)
+
+	file, err := c.FormFile("logo")
+	if err != nil {
+		return BadRequest(c, []string{"logo file is required"})
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		logger.Error("error when opening uploaded logo", zap.Error(err))
+		return BadRequest(c, []string{"invalid logo file"})
+	}
+	defer f.Close()
+
+	// Read one byte past the limit so oversized files are rejected by the service
+	req.Content, err = io.ReadAll(io.LimitReader(f, domain.MaxLogoSize+1))
+	if err != nil {
+		logger.Error("error when reading uploaded logo", zap.Error(err))
+		return BadRequest(c, []string{"invalid logo file"})
+	}
+
+	// The content type is sniffed from the file itself, the client header is not trusted
+	req.ContentType = http.DetectContentType(req.Content)
+
+	if err := h.service.UploadLogo(ctx, &req); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
+
+// Delete handles business profile deletion
+// @Summary Delete business profile
+// @Description Remove a business profile, issued invoices keep their issuer name
+// @Tags BusinessProfile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Business Profile ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /business-profiles/{id} [delete]
+func (h *BusinessProfileHandler) Delete(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid business profile ID format"})
+	}
+
+	if err := h.service.Delete(ctx, uint(id), userID); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
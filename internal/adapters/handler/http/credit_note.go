@@ -0,0 +1,263 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type CreditNoteHandler struct {
+	service portService.CreditNoteService
+	rto     time.Duration
+}
+
+func NewCreditNoteHandler(service portService.CreditNoteService, rto time.Duration) *CreditNoteHandler {
+	return &CreditNoteHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles listing credit notes
+// @Summary Get credit notes
+// @Description List credit notes with pagination, search matches credit note number, invoice number and customer
+// @Tags CreditNote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Limit" default(20)
+// @Param mode query string false "page or cursor" default(page)
+// @Param cursor query string false "next_cursor or prev_cursor of the previous page, implies cursor mode"
+// @Param with_total query bool false "Count total_data and total_page in cursor mode"
+// @Param search query string false "Search"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /credit-notes [get]
+func (h *CreditNoteHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.PaginationRequest
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
+		return BadRequest(c, []string{"invalid pagination parameters"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+	req.UserID = userID
+
+	res, err := h.service.Get(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return Page(c, res)
+}
+
+// GetByID handles getting a credit note
+// @Summary Get credit note
+// @Description Get a credit note with its items and the invoices its credit was applied to
+// @Tags CreditNote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Credit Note ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /credit-notes/{id} [get]
+func (h *CreditNoteHandler) GetByID(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid credit note ID format"})
+	}
+
+	res, err := h.service.GetByID(ctx, uint(id), userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Create handles issuing a credit note
+// @Summary Create credit note
+// @Description Credit items of a sent invoice, the credit is applied to the balance of that invoice first
+// @Tags CreditNote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.CreditNoteRequest true "Credit Note Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /credit-notes [post]
+func (h *CreditNoteHandler) Create(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.CreditNoteRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in credit note service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Create(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Apply handles applying credit to another invoice
+// @Summary Apply credit note
+// @Description Apply remaining credit to an open invoice of the same customer and currency
+// @Tags CreditNote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Credit Note ID"
+// @Param request body domain.ApplyCreditRequest true "Apply Credit Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /credit-notes/{id}/apply [post]
+func (h *CreditNoteHandler) Apply(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.ApplyCreditRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid credit note ID format"})
+	}
+	req.ID = uint(id)
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in credit note service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Apply(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Refund handles refunding credit to the customer
+// @Summary Refund credit note
+// @Description Record remaining credit paid back to the customer
+// @Tags CreditNote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Credit Note ID"
+// @Param request body domain.RefundCreditRequest true "Refund Credit Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /credit-notes/{id}/refund [post]
+func (h *CreditNoteHandler) Refund(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.RefundCreditRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid credit note ID format"})
+	}
+	req.ID = uint(id)
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in credit note service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Refund(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// GetPDF handles getting the PDF of a credit note
+// @Summary Get credit note PDF
+// @Description Get credit note PDF by ID
+// @Tags CreditNote
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path int true "Credit Note ID"
+// @Success 200 {file} application/pdf
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /credit-notes/{id}/pdf [get]
+func (h *CreditNoteHandler) GetPDF(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid credit note ID format"})
+	}
+
+	res, err := h.service.GetPDF(ctx, uint(id), userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("inline; filename=credit_note_%d.pdf", id))
+	return c.SendStream(bytes.NewReader(res))
+}
@@ -0,0 +1,210 @@
+package http
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type CustomerHandler struct {
+	service portService.CustomerService
+	rto     time.Duration
+}
+
+func NewCustomerHandler(service portService.CustomerService, rto time.Duration) *CustomerHandler {
+	return &CustomerHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles listing customers
+// @Summary Get customers
+// @Description List customers with pagination, search matches name, company, email and phone
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Limit" default(20)
+// @Param mode query string false "page or cursor" default(page)
+// @Param cursor query string false "next_cursor or prev_cursor of the previous page, implies cursor mode"
+// @Param with_total query bool false "Count total_data and total_page in cursor mode"
+// @Param search query string false "Search"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /customers [get]
+func (h *CustomerHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.PaginationRequest
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
+		return BadRequest(c, []string{"invalid pagination parameters"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+	req.UserID = userID
+
+	res, err := h.service.Get(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return Page(c, res)
+}
+
+// GetByID handles getting a customer
+// @Summary Get customer
+// @Description Get a customer by ID
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /customers/{id} [get]
+func (h *CustomerHandler) GetByID(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid customer ID format"})
+	}
+
+	res, err := h.service.GetByID(ctx, uint(id), userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Create handles customer creation
+// @Summary Create customer
+// @Description Add a customer to the address book
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.CustomerRequest true "Customer Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /customers [post]
+func (h *CustomerHandler) Create(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.CustomerRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in customer service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Create(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Update handles customer update
+// @Summary Update customer
+// @Description Update a customer, issued invoices keep their snapshot
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Param request body domain.CustomerRequest true "Customer Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /customers/{id} [put]
+func (h *CustomerHandler) Update(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.CustomerRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid customer ID format"})
+	}
+	req.ID = uint(id)
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in customer service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	if err := h.service.Update(ctx, &req); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
+
+// Delete handles customer deletion
+// @Summary Delete customer
+// @Description Remove a customer from the address book, invoices keep their snapshot
+// @Tags Customer
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Customer ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /customers/{id} [delete]
+func (h *CustomerHandler) Delete(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid customer ID format"})
+	}
+
+	if err := h.service.Delete(ctx, uint(id), userID); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type ExchangeRateHandler struct {
+	service portService.ExchangeRateService
+	rto     time.Duration
+}
+
+func NewExchangeRateHandler(service portService.ExchangeRateService, rto time.Duration) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// GetCurrencies handles listing supported currencies
+// @Summary Get currencies
+// @Description List the supported invoice currencies and their minor units
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Resp
+// @Router /currencies [get]
+func (h *ExchangeRateHandler) GetCurrencies(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	return OK(c, h.service.GetCurrencies(ctx))
+}
+
+// SetBaseCurrency handles changing the account base currency
+// @Summary Set base currency
+// @Description Set the currency invoice totals are converted to for reporting
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.BaseCurrencyRequest true "Base Currency Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /currencies/base [put]
+func (h *ExchangeRateHandler) SetBaseCurrency(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.BaseCurrencyRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in exchange rate service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	if err := h.service.SetBaseCurrency(ctx, &req); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
+
+// Get handles listing exchange rates
+// @Summary Get exchange rates
+// @Description List the manually entered exchange rates of the current user
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Resp
+// @Router /exchange-rates [get]
+func (h *ExchangeRateHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	res, err := h.service.Get(ctx, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Save handles storing a manual exchange rate
+// @Summary Save exchange rate
+// @Description Store the rate of one unit of a currency in the base currency, effective from a date
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.ExchangeRateRequest true "Exchange Rate Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /exchange-rates [post]
+func (h *ExchangeRateHandler) Save(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.ExchangeRateRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in exchange rate service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Save(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
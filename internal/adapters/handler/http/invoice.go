@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
 
 	"app/xonvera-core/internal/core/domain"
@@ -13,6 +13,7 @@ import (
 	"app/xonvera-core/internal/utils/validator"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -30,12 +31,26 @@ func NewInvoiceHandler(service portService.InvoiceService, rto time.Duration) *I
 
 // GetAllinvoice handles getting all invoice with pagination
 // @Summary Get all invoice
-// @Description Get all invoice with pagination
+// @Description Get all invoice with pagination and filters, search matches number, customer, issuer, note and item descriptions
 // @Tags Invoice
 // @Accept json
 // @Produce json
 // @Param page query int false "Page" default(1)
 // @Param limit query int false "Limit" default(20)
+// @Param mode query string false "page or cursor" default(page)
+// @Param cursor query string false "next_cursor or prev_cursor of the previous page, implies cursor mode"
+// @Param with_total query bool false "Count total_data and total_page in cursor mode"
+// @Param search query string false "Search"
+// @Param status query string false "Comma separated statuses, e.g. sent,overdue"
+// @Param customer_id query int false "Customer ID"
+// @Param issue_from query string false "Issue date from (YYYY-MM-DD)"
+// @Param issue_to query string false "Issue date to (YYYY-MM-DD)"
+// @Param due_from query string false "Due date from (YYYY-MM-DD)"
+// @Param due_to query string false "Due date to (YYYY-MM-DD)"
+// @Param min_total query int false "Minimum total"
+// @Param max_total query int false "Maximum total"
+// @Param overdue query bool false "Only invoices past their due date"
+// @Param sort query string false "created_at, issue_date, due_date, number, customer, status or total, prefix with - for descending" default(-created_at)
 // @Success 200 {object} Resp
 // @Failure 400 {object} Resp
 // @Router /invoice [get]
@@ -43,9 +58,10 @@ func (h *InvoiceHandler) Get(c fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
 	defer cancel()
 
-	var req domain.PaginationRequest
+	var req domain.InvoicePageReq
 	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
-		return BadRequest(c, []string{"invalid pagination parameters"})
+		logger.Error("error when binding request in invoice service", zap.Strings("error validation query", err))
+		return BadRequest(c, err)
 	}
 
 	userIDVal := c.Locals("userID")
@@ -63,6 +79,44 @@ func (h *InvoiceHandler) Get(c fiber.Ctx) error {
 	return Page(c, res)
 }
 
+// GetDeleted handles listing invoices in the trash
+// @Summary Get deleted invoices
+// @Description List soft deleted invoices that can still be restored or purged
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Limit" default(20)
+// @Param mode query string false "page or cursor" default(page)
+// @Param cursor query string false "next_cursor or prev_cursor of the previous page, implies cursor mode"
+// @Param with_total query bool false "Count total_data and total_page in cursor mode"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /invoice/trash [get]
+func (h *InvoiceHandler) GetDeleted(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.PaginationRequest
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
+		return BadRequest(c, []string{"invalid pagination parameters"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+	req.UserID = userID
+
+	res, err := h.service.GetDeleted(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return Page(c, res)
+}
+
 // Create handles invoice creation
 // @Summary Create a new invoice
 // @Description Create a new invoice with items
@@ -70,8 +124,11 @@ func (h *InvoiceHandler) Get(c fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param request body domain.InvoiceRequest true "Create Invoice Request"
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response instead of creating another invoice"
 // @Success 200 {object} Resp
 // @Failure 400 {object} Resp
+// @Failure 409 {object} Resp
+// @Failure 422 {object} Resp
 // @Router /invoice [post]
 func (h *InvoiceHandler) Create(c fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
@@ -96,26 +153,63 @@ func (h *InvoiceHandler) Create(c fiber.Ctx) error {
 		return BadRequest(c, []string{"at least one invoice item is required"})
 	}
 
-	err := h.service.Create(ctx, &req)
+	id, err := h.service.Create(ctx, &req)
 	if err != nil {
 		return HandlerErrorGlobal(c, err)
 	}
 
-	return OK(c, nil)
+	return OK(c, domain.InvoiceCreateResponse{ID: id})
+}
+
+// GetByID handles getting an invoice
+// @Summary Get invoice
+// @Description Get an invoice with its items and tax breakdown, the ETag header carries its version for If-Match on update
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Header 200 {string} ETag "Invoice version"
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id} [get]
+func (h *InvoiceHandler) GetByID(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	res, err := h.service.GetByID(ctx, invoiceID, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	c.Set("ETag", domain.InvoiceETag(res.Version))
+	return OK(c, res)
 }
 
 // Update handles invoice update
 // @Summary Update invoice
-// @Description Update invoice details with items
+// @Description Update invoice details with items. With an If-Match header the update is refused when the invoice has another version, the current invoice is returned then.
 // @Tags Invoice
 // @Accept json
 // @Produce json
-// @Param id path int true "Invoice ID"
+// @Param If-Match header string false "ETag of the invoice version being edited"
 // @Param request body domain.InvoiceRequest true "Update Invoice Request"
 // @Success 200 {object} Resp
+// @Header 200 {string} ETag "Invoice version after the update"
 // @Failure 400 {object} Resp
 // @Failure 404 {object} Resp
-// @Router /invoice/{id} [put]
+// @Failure 412 {object} Resp
+// @Router /invoice [put]
 func (h *InvoiceHandler) Update(c fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
 	defer cancel()
@@ -134,20 +228,46 @@ func (h *InvoiceHandler) Update(c fiber.Ctx) error {
 		return BadRequest(c, err)
 	}
 
-	if err := h.service.Update(ctx, &req); err != nil {
+	var err error
+	req.Version, err = domain.ParseIfMatch(c.Get("If-Match"))
+	if err != nil {
+		if err.Error() == domain.ErrInvoiceVersionMismatch {
+			return h.preconditionFailed(ctx, c, req.ID, req.UserID, err)
+		}
 		return HandlerErrorGlobal(c, err)
 	}
 
+	version, err := h.service.Update(ctx, &req)
+	if err != nil {
+		if err.Error() == domain.ErrInvoiceVersionMismatch {
+			return h.preconditionFailed(ctx, c, req.ID, req.UserID, err)
+		}
+		return HandlerErrorGlobal(c, err)
+	}
+
+	c.Set("ETag", domain.InvoiceETag(version))
 	return OK(c, nil)
 }
 
+// preconditionFailed answers a stale update with the current invoice, so the client can reapply its changes
+func (h *InvoiceHandler) preconditionFailed(ctx context.Context, c fiber.Ctx, invoiceID string, userID uint, err error) error {
+	current, getErr := h.service.GetByID(ctx, invoiceID, userID)
+	if getErr != nil {
+		return HandlerErrorGlobal(c, getErr)
+	}
+
+	_, msg, _ := strings.Cut(err.Error(), ":")
+	c.Set("ETag", domain.InvoiceETag(current.Version))
+	return JSON(c, fiber.StatusPreconditionFailed, []string{msg}, current, nil)
+}
+
 // GetInvoicePDF handles retrieving or generating invoice PDF
 // @Summary Get invoice PDF
 // @Description Retrieve existing invoice PDF or generate new one based on invoice data
 // @Tags Invoice
 // @Accept json
 // @Produce application/pdf
-// @Param id path int true "Invoice ID"
+// @Param id path string true "Invoice ID"
 // @Success 200 {file} application/pdf
 // @Failure 400 {object} Resp
 // @Failure 404 {object} Resp
@@ -156,8 +276,8 @@ func (h *InvoiceHandler) GetInvoicePDF(c fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
 	defer cancel()
 
-	invoiceID, err := strconv.ParseInt(c.Params("id"), 10, 64)
-	if err != nil || invoiceID <= 0 {
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
 		return BadRequest(c, []string{"invalid invoice ID format"})
 	}
 
@@ -174,6 +294,131 @@ func (h *InvoiceHandler) GetInvoicePDF(c fiber.Ctx) error {
 	}
 
 	c.Set("Content-Type", "application/pdf")
-	c.Set("Content-Disposition", fmt.Sprintf("inline; filename=invoice_%d.pdf", invoiceID))
+	c.Set("Content-Disposition", fmt.Sprintf("inline; filename=invoice_%s.pdf", invoiceID))
 	return c.SendStream(bytes.NewReader(res))
 }
+
+// Send handles issuing a draft invoice
+// @Summary Send invoice
+// @Description Move a draft invoice to sent
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /invoice/{id}/send [post]
+func (h *InvoiceHandler) Send(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Send)
+}
+
+// Void handles cancelling an invoice
+// @Summary Void invoice
+// @Description Move a draft, sent or overdue invoice to void
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /invoice/{id}/void [post]
+func (h *InvoiceHandler) Void(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Void)
+}
+
+// MarkPaid handles marking an invoice as fully paid
+// @Summary Mark invoice as paid
+// @Description Move a sent, partially paid or overdue invoice to paid
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /invoice/{id}/mark-paid [post]
+func (h *InvoiceHandler) MarkPaid(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.MarkPaid)
+}
+
+// Delete handles moving an invoice to the trash
+// @Summary Delete invoice
+// @Description Soft delete a draft or void invoice, it can be restored later
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /invoice/{id} [delete]
+func (h *InvoiceHandler) Delete(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Delete)
+}
+
+// Restore handles bringing an invoice back from the trash
+// @Summary Restore invoice
+// @Description Restore a soft deleted invoice
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/restore [post]
+func (h *InvoiceHandler) Restore(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Restore)
+}
+
+// Purge handles permanently removing an invoice from the trash
+// @Summary Purge invoice
+// @Description Permanently delete a soft deleted invoice with its items and payments
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/purge [delete]
+func (h *InvoiceHandler) Purge(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Purge)
+}
+
+// changeStatus parses the invoice ID and user, then runs a status transition
+func (h *InvoiceHandler) changeStatus(c fiber.Ctx, action func(ctx context.Context, invoiceID string, userID uint) error) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	if err := action(ctx, invoiceID, userID); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
+
+// invoiceIDParam returns the public invoice ID of the path in its canonical form
+func invoiceIDParam(c fiber.Ctx) (string, bool) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return "", false
+	}
+	return id.String(), true
+}
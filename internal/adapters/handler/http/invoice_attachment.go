@@ -0,0 +1,213 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type InvoiceAttachmentHandler struct {
+	service portService.InvoiceAttachmentService
+	rto     time.Duration
+}
+
+func NewInvoiceAttachmentHandler(service portService.InvoiceAttachmentService, rto time.Duration) *InvoiceAttachmentHandler {
+	return &InvoiceAttachmentHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Upload handles attaching a file to an invoice
+// @Summary Upload invoice attachment
+// @Description Attach a pdf, png, jpeg or webp file up to 10MB such as a delivery proof or contract, an invoice keeps up to 20 files.
+// @Description Pdf files uploaded with append_to_pdf are added after the pages of the invoice PDF.
+// @Tags InvoiceAttachment
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param file formData file true "Attachment file"
+// @Param append_to_pdf query bool false "Append a pdf file to the invoice PDF"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /invoice/{id}/attachments [post]
+func (h *InvoiceAttachmentHandler) Upload(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.InvoiceAttachmentRequest
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
+		logger.Error("error when binding request in invoice attachment service", zap.Strings("error validation query", err))
+		return BadRequest(c, err)
+	}
+
+	var ok bool
+	req.InvoiceID, ok = invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return BadRequest(c, []string{"attachment file is required"})
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		logger.Error("error when opening uploaded attachment", zap.Error(err))
+		return BadRequest(c, []string{"invalid attachment file"})
+	}
+	defer f.Close()
+
+	// Read one byte past the limit so oversized files are rejected by the service
+	req.Content, err = io.ReadAll(io.LimitReader(f, domain.MaxAttachmentSize+1))
+	if err != nil {
+		logger.Error("error when reading uploaded attachment", zap.Error(err))
+		return BadRequest(c, []string{"invalid attachment file"})
+	}
+
+	// The content type is sniffed from the file itself, the client header is not trusted
+	req.ContentType = http.DetectContentType(req.Content)
+	req.Name = file.Filename
+
+	res, err := h.service.Upload(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Get handles listing the attachments of an invoice
+// @Summary Get invoice attachments
+// @Description List the files attached to an invoice in the order they were uploaded
+// @Tags InvoiceAttachment
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/attachments [get]
+func (h *InvoiceAttachmentHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	res, err := h.service.Get(ctx, invoiceID, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Download handles downloading an invoice attachment
+// @Summary Download invoice attachment
+// @Description Download an attached file with the name it was uploaded with
+// @Tags InvoiceAttachment
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param attachmentId path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/attachments/{attachmentId} [get]
+func (h *InvoiceAttachmentHandler) Download(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("attachmentId"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid attachment ID format"})
+	}
+
+	attachment, content, err := h.service.Download(ctx, invoiceID, uint(id), userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	c.Set("Content-Type", attachment.ContentType)
+	c.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	c.Set("X-Content-Type-Options", "nosniff")
+	return c.SendStream(bytes.NewReader(content))
+}
+
+// Delete handles removing an invoice attachment
+// @Summary Delete invoice attachment
+// @Description Remove an attached file
+// @Tags InvoiceAttachment
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param attachmentId path int true "Attachment ID"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/attachments/{attachmentId} [delete]
+func (h *InvoiceAttachmentHandler) Delete(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("attachmentId"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid attachment ID format"})
+	}
+
+	if err := h.service.Delete(ctx, invoiceID, uint(id), userID); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
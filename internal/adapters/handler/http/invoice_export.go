@@ -0,0 +1,203 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type InvoiceExportHandler struct {
+	service portService.InvoiceExportService
+	rto     time.Duration
+}
+
+func NewInvoiceExportHandler(service portService.InvoiceExportService, rto time.Duration) *InvoiceExportHandler {
+	return &InvoiceExportHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Export handles streaming an invoice export
+// @Summary Export invoices
+// @Description Download the invoices matching the list filters as CSV or XLSX, with a row per invoice or per item. Numbers and dates follow the locale.
+// @Description An export of more invoices than EXPORT_STREAM_LIMIT is queued as a background export and answered with 202 and the export to poll.
+// @Tags InvoiceExport
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param format query string true "csv or xlsx"
+// @Param rows query string false "invoice or item" default(invoice)
+// @Param locale query string false "id-ID or en-US" default(id-ID)
+// @Param search query string false "Search"
+// @Param status query string false "Comma separated statuses, e.g. sent,overdue"
+// @Param customer_id query int false "Customer ID"
+// @Param issue_from query string false "Issue date from (YYYY-MM-DD)"
+// @Param issue_to query string false "Issue date to (YYYY-MM-DD)"
+// @Param due_from query string false "Due date from (YYYY-MM-DD)"
+// @Param due_to query string false "Due date to (YYYY-MM-DD)"
+// @Param min_total query int false "Minimum total"
+// @Param max_total query int false "Maximum total"
+// @Param overdue query bool false "Only invoices past their due date"
+// @Param sort query string false "Sort field, prefix with - for descending" default(-created_at)
+// @Success 200 {file} file
+// @Success 202 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /invoice/export [get]
+func (h *InvoiceExportHandler) Export(c fiber.Ctx) error {
+	var req domain.InvoiceExportRequest
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
+		logger.Error("error when binding request in invoice export service", zap.Strings("error validation query", err))
+		return BadRequest(c, err)
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+	req.UserID = userID
+
+	prepareCtx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+	queued, err := h.service.Prepare(prepareCtx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+	if queued != nil {
+		return JSON(c, fiber.StatusAccepted, []string{"export is too large to download directly, it was queued as a background export"}, queued, nil)
+	}
+
+	// The rows are written after the handler returns, the export has its own timeout instead of the request timeout
+	// and the server gives this route a write timeout as long as it
+	ctx := c.Context()
+	conn := c.RequestCtx().Conn()
+	c.Set("Content-Type", req.Format.ContentType())
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", domain.ExportFileName(req.Rows, req.Format, time.Now())))
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		if _, err := h.service.Write(ctx, &req, w); err != nil {
+			// The status is already sent, closing the connection before the last chunk tells the client
+			// the file is incomplete instead of serving a truncated one
+			_ = conn.Close()
+			return
+		}
+		_ = w.Flush()
+	})
+}
+
+// Create handles queueing a background invoice export
+// @Summary Create invoice export
+// @Description Queue an export of the invoices matching the list filters, poll it and download the file once done
+// @Tags InvoiceExport
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param format query string true "csv or xlsx"
+// @Param rows query string false "invoice or item" default(invoice)
+// @Param locale query string false "id-ID or en-US" default(id-ID)
+// @Param status query string false "Comma separated statuses, e.g. sent,overdue"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /invoice/exports [post]
+func (h *InvoiceExportHandler) Create(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.InvoiceExportRequest
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
+		logger.Error("error when binding request in invoice export service", zap.Strings("error validation query", err))
+		return BadRequest(c, err)
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+	req.UserID = userID
+
+	res, err := h.service.Create(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// GetByID handles getting a background invoice export
+// @Summary Get invoice export
+// @Description Get the status of a background invoice export
+// @Tags InvoiceExport
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param exportId path int true "Export ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/exports/{exportId} [get]
+func (h *InvoiceExportHandler) GetByID(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("exportId"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid export ID format"})
+	}
+
+	res, err := h.service.GetByID(ctx, uint(id), userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Download handles downloading a background invoice export
+// @Summary Download invoice export
+// @Description Download the file of a finished background invoice export
+// @Tags InvoiceExport
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param exportId path int true "Export ID"
+// @Success 200 {file} file
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Failure 410 {object} Resp
+// @Router /invoice/exports/{exportId}/download [get]
+func (h *InvoiceExportHandler) Download(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("exportId"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid export ID format"})
+	}
+
+	export, content, err := h.service.Download(ctx, uint(id), userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	c.Set("Content-Type", export.Format.ContentType())
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", export.FileName()))
+	return c.Send(content)
+}
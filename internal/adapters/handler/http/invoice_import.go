@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type InvoiceImportHandler struct {
+	service portService.InvoiceImportService
+	rto     time.Duration
+}
+
+func NewInvoiceImportHandler(service portService.InvoiceImportService, rto time.Duration) *InvoiceImportHandler {
+	return &InvoiceImportHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Import handles importing invoices from a CSV file
+// @Summary Import invoices
+// @Description Create invoices from a CSV file with one row per item, rows with the same reference make one invoice.
+// @Description Columns are reference, issue_date, description, qty and price, optionally profile_id, issuer, customer, customer_id, due_date, note, currency, exchange_rate, tax_mode, tax_rate_id, discount_type, discount, item_discount_type, item_discount and item_tax_rate_id, with the values of the invoice API.
+// @Description The invoices are created together, nothing is created while any row has errors or any invoice fails. References imported before are skipped.
+// @Tags Invoice
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV file up to 2MB"
+// @Param dry_run query bool false "Only check the file and report what would be created"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /invoice/import [post]
+func (h *InvoiceImportHandler) Import(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.InvoiceImportRequest
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
+		logger.Error("error when binding request in invoice import service", zap.Strings("error validation query", err))
+		return BadRequest(c, err)
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+	req.UserID = userID
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return BadRequest(c, []string{"import file is required"})
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		logger.Error("error when opening uploaded import file", zap.Error(err))
+		return BadRequest(c, []string{"invalid import file"})
+	}
+	defer f.Close()
+
+	// Read one byte past the limit so oversized files are rejected by the service
+	req.Content, err = io.ReadAll(io.LimitReader(f, domain.MaxImportSize+1))
+	if err != nil {
+		logger.Error("error when reading uploaded import file", zap.Error(err))
+		return BadRequest(c, []string{"invalid import file"})
+	}
+
+	res, err := h.service.Import(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	// The report still tells which rows to fix
+	if !res.DryRun && len(res.Errors) > 0 {
+		return JSON(c, fiber.StatusBadRequest, []string{"import file has invalid rows or invoices, nothing was imported"}, res, nil)
+	}
+
+	return OK(c, res)
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type InvoiceRevisionHandler struct {
+	service portService.InvoiceRevisionService
+	rto     time.Duration
+}
+
+func NewInvoiceRevisionHandler(service portService.InvoiceRevisionService, rto time.Duration) *InvoiceRevisionHandler {
+	return &InvoiceRevisionHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles listing the revisions of an invoice
+// @Summary Get invoice revisions
+// @Description List who changed an invoice and when, newest first. A revision is kept on create, update and every status change.
+// @Tags InvoiceRevision
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/revisions [get]
+func (h *InvoiceRevisionHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	res, err := h.service.Get(ctx, invoiceID, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// GetByRevision handles getting an invoice as it was in a revision
+// @Summary Get invoice revision
+// @Description Get the full invoice with its items as it was after the change of a revision
+// @Tags InvoiceRevision
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param revision path int true "Revision"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/revisions/{revision} [get]
+func (h *InvoiceRevisionHandler) GetByRevision(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	revision, err := strconv.Atoi(c.Params("revision"))
+	if err != nil || revision < 1 {
+		return BadRequest(c, []string{"invalid revision format"})
+	}
+
+	res, err := h.service.GetByRevision(ctx, invoiceID, revision, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Diff handles comparing two revisions of an invoice
+// @Summary Diff invoice revisions
+// @Description List the fields that changed between two revisions by path, e.g. items[1].qty, with their old and new values
+// @Tags InvoiceRevision
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param from query int true "Revision to compare from"
+// @Param to query int false "Revision to compare to, the latest when not given"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/revisions/diff [get]
+func (h *InvoiceRevisionHandler) Diff(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	var req domain.InvoiceRevisionDiffRequest
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
+		logger.Error("error when binding request in invoice revision service", zap.Strings("error validation query", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Diff(ctx, invoiceID, &req, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
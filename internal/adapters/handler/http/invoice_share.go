@@ -0,0 +1,194 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type InvoiceShareHandler struct {
+	service portService.InvoiceShareService
+	rto     time.Duration
+}
+
+func NewInvoiceShareHandler(service portService.InvoiceShareService, rto time.Duration) *InvoiceShareHandler {
+	return &InvoiceShareHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles listing the share links of an invoice
+// @Summary Get invoice share links
+// @Description List the share links of an invoice with when they were first viewed
+// @Tags InvoiceShare
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/shares [get]
+func (h *InvoiceShareHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	res, err := h.service.Get(ctx, invoiceID, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Create handles creating a share link of an invoice
+// @Summary Create invoice share link
+// @Description Create a signed link to a sent invoice that opens without an account, the token is only returned once
+// @Tags InvoiceShare
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param request body domain.InvoiceShareRequest false "Invoice Share Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /invoice/{id}/shares [post]
+func (h *InvoiceShareHandler) Create(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.InvoiceShareRequest
+	var ok bool
+
+	req.InvoiceID, ok = invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	// The body is optional, links expire after the configured period by default
+	if len(c.Body()) > 0 {
+		if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+			logger.Error("error when binding request in invoice share service", zap.Strings("error validation body", err))
+			return BadRequest(c, err)
+		}
+	}
+
+	res, err := h.service.Create(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Revoke handles revoking a share link of an invoice
+// @Summary Revoke invoice share link
+// @Description Disable a share link before it expires
+// @Tags InvoiceShare
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param shareId path int true "Share ID"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/shares/{shareId} [delete]
+func (h *InvoiceShareHandler) Revoke(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	shareID, err := strconv.ParseUint(c.Params("shareId"), 10, 32)
+	if err != nil || shareID == 0 {
+		return BadRequest(c, []string{"invalid share ID format"})
+	}
+
+	if err := h.service.Revoke(ctx, invoiceID, uint(shareID), userID); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
+
+// View handles opening a shared invoice
+// @Summary View shared invoice
+// @Description Get a shared invoice by its share token, no account needed
+// @Tags InvoiceShare
+// @Accept json
+// @Produce json
+// @Param token path string true "Share Token"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 410 {object} Resp
+// @Router /public/invoices/{token} [get]
+func (h *InvoiceShareHandler) View(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	res, err := h.service.View(ctx, c.Params("token"))
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// ViewPDF handles downloading a shared invoice
+// @Summary Get shared invoice PDF
+// @Description Get the PDF of a shared invoice by its share token, no account needed
+// @Tags InvoiceShare
+// @Produce application/pdf
+// @Param token path string true "Share Token"
+// @Success 200 {file} application/pdf
+// @Failure 404 {object} Resp
+// @Failure 410 {object} Resp
+// @Router /public/invoices/{token}/pdf [get]
+func (h *InvoiceShareHandler) ViewPDF(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	res, err := h.service.ViewPDF(ctx, c.Params("token"))
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", "inline; filename=invoice.pdf")
+	return c.SendStream(bytes.NewReader(res))
+}
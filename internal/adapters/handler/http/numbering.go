@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type NumberingHandler struct {
+	service portService.NumberingService
+	rto     time.Duration
+}
+
+func NewNumberingHandler(service portService.NumberingService, rto time.Duration) *NumberingHandler {
+	return &NumberingHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles listing numbering schemes
+// @Summary Get numbering schemes
+// @Description List the numbering scheme of every document type with the next number for today
+// @Tags Numbering
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Resp
+// @Router /numbering [get]
+func (h *NumberingHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	res, err := h.service.Get(ctx, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Save handles numbering scheme update
+// @Summary Save numbering scheme
+// @Description Set the number template of a document type, e.g. INV/{YYYY}/{MM}/{SEQ:5}. Tokens are {YYYY}, {YY}, {MM}, {DD} and {SEQ} or {SEQ:n} padded to n digits
+// @Tags Numbering
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param document path string true "Document type" Enums(invoice, credit_note, quote)
+// @Param request body domain.NumberingSchemeRequest true "Numbering Scheme Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /numbering/{document} [put]
+func (h *NumberingHandler) Save(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.NumberingSchemeRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+	req.Document = domain.DocumentType(c.Params("document"))
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in numbering service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Save(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
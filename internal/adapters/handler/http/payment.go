@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type PaymentHandler struct {
+	service portService.PaymentService
+	rto     time.Duration
+}
+
+func NewPaymentHandler(service portService.PaymentService, rto time.Duration) *PaymentHandler {
+	return &PaymentHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles listing payments of an invoice
+// @Summary Get invoice payments
+// @Description List every payment recorded against an invoice, including reversed ones
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/payments [get]
+func (h *PaymentHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	res, err := h.service.Get(ctx, invoiceID, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Create handles recording a payment
+// @Summary Record invoice payment
+// @Description Record money received against an invoice, rolling it to partially paid or paid
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param request body domain.PaymentRequest true "Payment Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /invoice/{id}/payments [post]
+func (h *PaymentHandler) Create(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.PaymentRequest
+	var ok bool
+	var err error
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	req.InvoiceID, ok = invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in payment service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Create(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Reverse handles reversing a recorded payment
+// @Summary Reverse invoice payment
+// @Description Reverse a mistaken payment, rolling the invoice status back
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Param paymentId path int true "Payment ID"
+// @Param request body domain.ReversePaymentRequest true "Reverse Payment Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /invoice/{id}/payments/{paymentId}/reverse [post]
+func (h *PaymentHandler) Reverse(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.ReversePaymentRequest
+	var ok bool
+	var err error
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	req.InvoiceID, ok = invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	req.PaymentID, err = strconv.ParseInt(c.Params("paymentId"), 10, 64)
+	if err != nil || req.PaymentID <= 0 {
+		return BadRequest(c, []string{"invalid payment ID format"})
+	}
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in payment service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	if err := h.service.Reverse(ctx, &req); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
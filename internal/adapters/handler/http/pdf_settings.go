@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type PDFSettingsHandler struct {
+	service portService.PDFSettingsService
+	rto     time.Duration
+}
+
+func NewPDFSettingsHandler(service portService.PDFSettingsService, rto time.Duration) *PDFSettingsHandler {
+	return &PDFSettingsHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles getting the document settings of the account
+// @Summary Get PDF settings
+// @Description Get the template, language and brand color invoices, quotes and credit notes are rendered with
+// @Tags PDF Settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Resp
+// @Router /pdf-settings [get]
+func (h *PDFSettingsHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	res, err := h.service.Get(ctx, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Update handles changing the document settings of the account
+// @Summary Update PDF settings
+// @Description Pick the template (classic, modern or compact), the language of labels, dates and numbers (id-ID or en-US) and the brand color of documents.
+// @Description An empty brand color uses the default one.
+// @Tags PDF Settings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.PDFSettingsRequest true "PDF Settings Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /pdf-settings [put]
+func (h *PDFSettingsHandler) Update(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.PDFSettingsRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in pdf settings service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Update(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
@@ -0,0 +1,350 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type QuoteHandler struct {
+	service portService.QuoteService
+	rto     time.Duration
+}
+
+func NewQuoteHandler(service portService.QuoteService, rto time.Duration) *QuoteHandler {
+	return &QuoteHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles listing quotes
+// @Summary Get quotes
+// @Description List quotes with pagination, search matches number and customer
+// @Tags Quote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Limit" default(20)
+// @Param mode query string false "page or cursor" default(page)
+// @Param cursor query string false "next_cursor or prev_cursor of the previous page, implies cursor mode"
+// @Param with_total query bool false "Count total_data and total_page in cursor mode"
+// @Param search query string false "Search"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /quotes [get]
+func (h *QuoteHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.PaginationRequest
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
+		return BadRequest(c, []string{"invalid pagination parameters"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+	req.UserID = userID
+
+	res, err := h.service.Get(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return Page(c, res)
+}
+
+// GetByID handles getting a quote
+// @Summary Get quote
+// @Description Get a quote with its items by ID, converted items show the invoice they were billed on
+// @Tags Quote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Quote ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /quotes/{id} [get]
+func (h *QuoteHandler) GetByID(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid quote ID format"})
+	}
+
+	res, err := h.service.GetByID(ctx, uint(id), userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Create handles quote creation
+// @Summary Create quote
+// @Description Add a draft quote, its number is taken from the quote numbering scheme
+// @Tags Quote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.QuoteRequest true "Quote Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /quotes [post]
+func (h *QuoteHandler) Create(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.QuoteRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in quote service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Create(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Update handles quote update
+// @Summary Update quote
+// @Description Update a draft or sent quote, items replace the existing ones
+// @Tags Quote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Quote ID"
+// @Param request body domain.QuoteRequest true "Quote Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /quotes/{id} [put]
+func (h *QuoteHandler) Update(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.QuoteRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid quote ID format"})
+	}
+	req.ID = uint(id)
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in quote service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	if err := h.service.Update(ctx, &req); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
+
+// Delete handles quote deletion
+// @Summary Delete quote
+// @Description Remove a quote, quotes with converted items are kept
+// @Tags Quote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Quote ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /quotes/{id} [delete]
+func (h *QuoteHandler) Delete(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Delete)
+}
+
+// Send handles sending a quote
+// @Summary Send quote
+// @Description Mark a draft quote as sent to the customer
+// @Tags Quote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Quote ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /quotes/{id}/send [post]
+func (h *QuoteHandler) Send(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Send)
+}
+
+// Accept handles accepting a quote
+// @Summary Accept quote
+// @Description Record that the customer accepted a sent quote, expired quotes can not be accepted
+// @Tags Quote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Quote ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /quotes/{id}/accept [post]
+func (h *QuoteHandler) Accept(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Accept)
+}
+
+// Decline handles declining a quote
+// @Summary Decline quote
+// @Description Record that the customer declined a sent quote
+// @Tags Quote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Quote ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /quotes/{id}/decline [post]
+func (h *QuoteHandler) Decline(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Decline)
+}
+
+// changeStatus runs an action that only needs the quote ID and the user
+func (h *QuoteHandler) changeStatus(c fiber.Ctx, action func(ctx context.Context, id, userID uint) error) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid quote ID format"})
+	}
+
+	if err := action(ctx, uint(id), userID); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
+
+// Convert handles converting a quote into an invoice
+// @Summary Convert quote
+// @Description Create a draft invoice from items of an accepted quote, all items not invoiced yet when none are selected
+// @Tags Quote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Quote ID"
+// @Param request body domain.QuoteConvertRequest false "Quote Convert Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /quotes/{id}/convert [post]
+func (h *QuoteHandler) Convert(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.QuoteConvertRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid quote ID format"})
+	}
+	req.ID = uint(id)
+
+	// The body is optional, converting everything left needs no selection
+	if len(c.Body()) > 0 {
+		if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+			logger.Error("error when binding request in quote service", zap.Strings("error validation body", err))
+			return BadRequest(c, err)
+		}
+	}
+
+	res, err := h.service.Convert(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// GetPDF handles getting the PDF of a quote
+// @Summary Get quote PDF
+// @Description Get quote PDF by ID
+// @Tags Quote
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path int true "Quote ID"
+// @Success 200 {file} application/pdf
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /quotes/{id}/pdf [get]
+func (h *QuoteHandler) GetPDF(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid quote ID format"})
+	}
+
+	res, err := h.service.GetPDF(ctx, uint(id), userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", fmt.Sprintf("inline; filename=quote_%d.pdf", id))
+	return c.SendStream(bytes.NewReader(res))
+}
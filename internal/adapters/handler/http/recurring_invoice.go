@@ -0,0 +1,288 @@
+package http
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type RecurringInvoiceHandler struct {
+	service portService.RecurringInvoiceService
+	rto     time.Duration
+}
+
+func NewRecurringInvoiceHandler(service portService.RecurringInvoiceService, rto time.Duration) *RecurringInvoiceHandler {
+	return &RecurringInvoiceHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles listing recurring invoices
+// @Summary Get recurring invoices
+// @Description List recurring invoices with pagination, search matches name and customer
+// @Tags RecurringInvoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Limit" default(20)
+// @Param mode query string false "page or cursor" default(page)
+// @Param cursor query string false "next_cursor or prev_cursor of the previous page, implies cursor mode"
+// @Param with_total query bool false "Count total_data and total_page in cursor mode"
+// @Param search query string false "Search"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /recurring-invoices [get]
+func (h *RecurringInvoiceHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.PaginationRequest
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
+		return BadRequest(c, []string{"invalid pagination parameters"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+	req.UserID = userID
+
+	res, err := h.service.Get(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return Page(c, res)
+}
+
+// GetByID handles getting a recurring invoice
+// @Summary Get recurring invoice
+// @Description Get a recurring invoice with its items by ID
+// @Tags RecurringInvoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /recurring-invoices/{id} [get]
+func (h *RecurringInvoiceHandler) GetByID(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid recurring invoice ID format"})
+	}
+
+	res, err := h.service.GetByID(ctx, uint(id), userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Create handles recurring invoice creation
+// @Summary Create recurring invoice
+// @Description Add a recurring invoice, the scheduler creates an invoice on every run
+// @Tags RecurringInvoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.RecurringInvoiceRequest true "Recurring Invoice Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /recurring-invoices [post]
+func (h *RecurringInvoiceHandler) Create(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.RecurringInvoiceRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in recurring invoice service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Create(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Update handles recurring invoice update
+// @Summary Update recurring invoice
+// @Description Update a recurring invoice, invoices already created are not changed
+// @Tags RecurringInvoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Param request body domain.RecurringInvoiceRequest true "Recurring Invoice Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /recurring-invoices/{id} [put]
+func (h *RecurringInvoiceHandler) Update(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.RecurringInvoiceRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid recurring invoice ID format"})
+	}
+	req.ID = uint(id)
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in recurring invoice service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	if err := h.service.Update(ctx, &req); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
+
+// Delete handles recurring invoice deletion
+// @Summary Delete recurring invoice
+// @Description Remove a recurring invoice, invoices already created are kept
+// @Tags RecurringInvoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /recurring-invoices/{id} [delete]
+func (h *RecurringInvoiceHandler) Delete(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Delete)
+}
+
+// Pause handles pausing a recurring invoice
+// @Summary Pause recurring invoice
+// @Description Stop an active recurring invoice from creating invoices
+// @Tags RecurringInvoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /recurring-invoices/{id}/pause [post]
+func (h *RecurringInvoiceHandler) Pause(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Pause)
+}
+
+// Resume handles resuming a recurring invoice
+// @Summary Resume recurring invoice
+// @Description Restart a paused recurring invoice, runs missed while paused are skipped
+// @Tags RecurringInvoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Failure 409 {object} Resp
+// @Router /recurring-invoices/{id}/resume [post]
+func (h *RecurringInvoiceHandler) Resume(c fiber.Ctx) error {
+	return h.changeStatus(c, h.service.Resume)
+}
+
+// changeStatus runs an action that only needs the recurring invoice ID and the user
+func (h *RecurringInvoiceHandler) changeStatus(c fiber.Ctx, action func(ctx context.Context, id, userID uint) error) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid recurring invoice ID format"})
+	}
+
+	if err := action(ctx, uint(id), userID); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
+
+// Preview handles previewing the next runs of a recurring invoice
+// @Summary Preview recurring invoice runs
+// @Description List the dates of the next runs, a paused schedule is shown as if resumed today
+// @Tags RecurringInvoice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recurring Invoice ID"
+// @Param count query int false "Number of runs" default(12)
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /recurring-invoices/{id}/preview [get]
+func (h *RecurringInvoiceHandler) Preview(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid recurring invoice ID format"})
+	}
+
+	var req domain.RecurringPreviewRequest
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerQuery); err != nil {
+		logger.Error("error when binding request in recurring invoice service", zap.Strings("error validation query", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Preview(ctx, uint(id), userID, req.Count)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
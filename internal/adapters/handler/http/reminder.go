@@ -0,0 +1,55 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	portService "app/xonvera-core/internal/core/ports/service"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type ReminderHandler struct {
+	service portService.ReminderService
+	rto     time.Duration
+}
+
+func NewReminderHandler(service portService.ReminderService, rto time.Duration) *ReminderHandler {
+	return &ReminderHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles listing the reminders of an invoice
+// @Summary Get invoice reminders
+// @Description List the payment reminders sent for an invoice and how each one ended
+// @Tags Reminder
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /invoice/{id}/reminders [get]
+func (h *ReminderHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	invoiceID, ok := invoiceIDParam(c)
+	if !ok {
+		return BadRequest(c, []string{"invalid invoice ID format"})
+	}
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	res, err := h.service.Get(ctx, invoiceID, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type TaxRateHandler struct {
+	service portService.TaxRateService
+	rto     time.Duration
+}
+
+func NewTaxRateHandler(service portService.TaxRateService, rto time.Duration) *TaxRateHandler {
+	return &TaxRateHandler{
+		service: service,
+		rto:     rto,
+	}
+}
+
+// Get handles listing tax rates
+// @Summary Get tax rates
+// @Description List the tax rates of the current user
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Resp
+// @Router /tax-rates [get]
+func (h *TaxRateHandler) Get(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	userID, ok := c.Locals("userID").(uint)
+	if !ok || userID == 0 {
+		return NoAuth(c)
+	}
+
+	res, err := h.service.Get(ctx, userID)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Create handles tax rate creation
+// @Summary Create tax rate
+// @Description Create a tax rate, rate is in basis points (1100 = 11%)
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body domain.TaxRateRequest true "Tax Rate Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Router /tax-rates [post]
+func (h *TaxRateHandler) Create(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.TaxRateRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in tax rate service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	res, err := h.service.Create(ctx, &req)
+	if err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, res)
+}
+
+// Update handles tax rate update
+// @Summary Update tax rate
+// @Description Update a tax rate, existing invoices keep the rate they were issued with
+// @Tags Tax
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Tax Rate ID"
+// @Param request body domain.TaxRateRequest true "Tax Rate Request"
+// @Success 200 {object} Resp
+// @Failure 400 {object} Resp
+// @Failure 404 {object} Resp
+// @Router /tax-rates/{id} [put]
+func (h *TaxRateHandler) Update(c fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.rto)
+	defer cancel()
+
+	var req domain.TaxRateRequest
+	var ok bool
+
+	req.UserID, ok = c.Locals("userID").(uint)
+	if !ok || req.UserID == 0 {
+		return NoAuth(c)
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return BadRequest(c, []string{"invalid tax rate ID format"})
+	}
+	req.ID = uint(id)
+
+	if err := validator.HandlerBindingError(c, &req, validator.HandlerBody); err != nil {
+		logger.Error("error when binding request in tax rate service", zap.Strings("error validation body", err))
+		return BadRequest(c, err)
+	}
+
+	if err := h.service.Update(ctx, &req); err != nil {
+		return HandlerErrorGlobal(c, err)
+	}
+
+	return OK(c, nil)
+}
@@ -0,0 +1,159 @@
+package middleware
+
+import (
+	"app/xonvera-core/internal/adapters/handler/http"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	idempotencyHeader         = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	// IdempotencyKeyMaxLength is the longest Idempotency-Key accepted
+	IdempotencyKeyMaxLength = 255
+	// IdempotencyLockDuration bounds how long a request holds its key before it has a response,
+	// a request that never finishes frees the key for a retry after it
+	IdempotencyLockDuration = 1 * time.Minute
+	// idempotencyMaxBody is the largest response kept for replay
+	idempotencyMaxBody = 1024 * 1024
+)
+
+// idempotencyRecord is what is kept under a key, Done is false while the first request runs
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Done        bool   `json:"done"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	ETag        string `json:"etag,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Idempotency replays the response of the first request sent with an Idempotency-Key for its retries.
+// Keys are kept per user for ttl, a key sent again with another request is refused.
+// Requests without the header, safe methods and server errors are not recorded.
+func Idempotency(redisClient *redis.Client, ttl time.Duration) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		idempotencyKey := c.Get(idempotencyHeader)
+		if idempotencyKey == "" || !isMutating(c.Method()) {
+			return c.Next()
+		}
+		if len(idempotencyKey) > IdempotencyKeyMaxLength {
+			return http.BadRequest(c, []string{fmt.Sprintf("Idempotency-Key must be at most %d characters", IdempotencyKeyMaxLength)})
+		}
+
+		key := fmt.Sprintf("idempotency:%s:%s", resolveRateLimitKey(c), idempotencyKey)
+		fingerprint := requestFingerprint(c)
+
+		lock, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+		ctx, cancel := context.WithTimeout(context.Background(), RedisContextTimeout)
+		acquired, err := redisClient.SetNX(ctx, key, lock, IdempotencyLockDuration).Result()
+		cancel()
+		if err != nil {
+			// If Redis fails, allow the request to proceed (fail-open)
+			logger.ContextWarn(c, "Idempotency Redis error, allowing request", zap.String("key", key), zap.Error(err))
+			return c.Next()
+		}
+
+		if !acquired {
+			return replayIdempotent(c, redisClient, key, fingerprint)
+		}
+
+		if err = c.Next(); err != nil {
+			releaseIdempotencyKey(c, redisClient, key)
+			return err
+		}
+
+		res := c.Response()
+		if res.StatusCode() >= fiber.StatusInternalServerError || res.IsBodyStream() || len(res.Body()) > idempotencyMaxBody {
+			releaseIdempotencyKey(c, redisClient, key)
+			return nil
+		}
+
+		record, _ := json.Marshal(idempotencyRecord{
+			Fingerprint: fingerprint,
+			Done:        true,
+			Status:      res.StatusCode(),
+			ContentType: string(res.Header.ContentType()),
+			ETag:        string(res.Header.Peek(fiber.HeaderETag)),
+			Body:        res.Body(),
+		})
+		ctx, cancel = context.WithTimeout(context.Background(), RedisContextTimeout)
+		defer cancel()
+		if err = redisClient.Set(ctx, key, record, ttl).Err(); err != nil {
+			logger.ContextWarn(c, "Failed to store idempotent response", zap.String("key", key), zap.Error(err))
+		}
+		return nil
+	}
+}
+
+// replayIdempotent answers a retry with the stored response of its key
+func replayIdempotent(c fiber.Ctx, redisClient *redis.Client, key, fingerprint string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), RedisContextTimeout)
+	defer cancel()
+
+	raw, err := redisClient.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		// The first request failed just now and freed its key, the next retry runs it again
+		return http.Conflict(c, []string{"request with this Idempotency-Key is still being processed"})
+	}
+	if err != nil {
+		logger.ContextWarn(c, "Idempotency Redis error, allowing request", zap.String("key", key), zap.Error(err))
+		return c.Next()
+	}
+
+	var record idempotencyRecord
+	if err = json.Unmarshal(raw, &record); err != nil {
+		logger.ContextWarn(c, "Invalid idempotency record", zap.String("key", key), zap.Error(err))
+		return c.Next()
+	}
+
+	switch {
+	case record.Fingerprint != fingerprint:
+		return http.JSON(c, fiber.StatusUnprocessableEntity, []string{"Idempotency-Key was already used for another request"}, nil, nil)
+	case !record.Done:
+		return http.Conflict(c, []string{"request with this Idempotency-Key is still being processed"})
+	}
+
+	c.Set(idempotencyReplayedHeader, "true")
+	if record.ETag != "" {
+		c.Set(fiber.HeaderETag, record.ETag)
+	}
+	if record.ContentType != "" {
+		c.Set(fiber.HeaderContentType, record.ContentType)
+	}
+	return c.Status(record.Status).Send(record.Body)
+}
+
+// releaseIdempotencyKey frees a key whose request has no response worth replaying
+func releaseIdempotencyKey(c fiber.Ctx, redisClient *redis.Client, key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), RedisContextTimeout)
+	defer cancel()
+	if err := redisClient.Del(ctx, key).Err(); err != nil {
+		logger.ContextWarn(c, "Failed to release idempotency key", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// requestFingerprint identifies a request by method, URL and body, a retry must repeat all three
+func requestFingerprint(c fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(c.Method() + " " + c.OriginalURL() + "\n"))
+	h.Write(c.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+	return false
+}
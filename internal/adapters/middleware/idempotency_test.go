@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	nethttp "net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v3"
+	"github.com/redis/go-redis/v9"
+)
+
+// newIdempotencyApp serves handler on POST /items behind the idempotency middleware for user 7
+func newIdempotencyApp(t *testing.T, handler fiber.Handler) (*fiber.App, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("userID", uint(7))
+		return c.Next()
+	})
+	app.Use(Idempotency(client, time.Hour))
+	app.Post("/items", handler)
+	return app, mr
+}
+
+func sendIdempotent(t *testing.T, app *fiber.App, key, body string) (*nethttp.Response, string) {
+	t.Helper()
+	req, err := nethttp.NewRequest(fiber.MethodPost, "/items", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(idempotencyHeader, key)
+
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return res, string(raw)
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	var calls int
+	app, _ := newIdempotencyApp(t, func(c fiber.Ctx) error {
+		calls++
+		c.Set(fiber.HeaderETag, `"1"`)
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls})
+	})
+
+	first, firstBody := sendIdempotent(t, app, "create-1", `{"name":"a"}`)
+	second, secondBody := sendIdempotent(t, app, "create-1", `{"name":"a"}`)
+
+	if calls != 1 {
+		t.Fatalf("expected the handler to run once, ran %d times", calls)
+	}
+	if second.StatusCode != first.StatusCode || secondBody != firstBody {
+		t.Errorf("expected replay of %d %s, got %d %s", first.StatusCode, firstBody, second.StatusCode, secondBody)
+	}
+	if second.Header.Get(idempotencyReplayedHeader) != "true" || second.Header.Get(fiber.HeaderETag) != `"1"` {
+		t.Errorf("expected replayed headers, got %v", second.Header)
+	}
+	if first.Header.Get(idempotencyReplayedHeader) != "" {
+		t.Errorf("first response must not be marked replayed")
+	}
+}
+
+func TestIdempotencyRefusesReusedKey(t *testing.T) {
+	var calls int
+	app, _ := newIdempotencyApp(t, func(c fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls})
+	})
+
+	sendIdempotent(t, app, "create-1", `{"name":"a"}`)
+	res, _ := sendIdempotent(t, app, "create-1", `{"name":"b"}`)
+
+	if res.StatusCode != fiber.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for another body, got %d", res.StatusCode)
+	}
+	if calls != 1 {
+		t.Errorf("expected the handler to run once, ran %d times", calls)
+	}
+}
+
+func TestIdempotencyConflictWhileInFlight(t *testing.T) {
+	var inFlight int
+	var app *fiber.App
+	app, _ = newIdempotencyApp(t, func(c fiber.Ctx) error {
+		// A retry arriving while the first request still runs
+		res, _ := sendIdempotent(t, app, "create-1", `{"name":"a"}`)
+		inFlight = res.StatusCode
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	res, _ := sendIdempotent(t, app, "create-1", `{"name":"a"}`)
+	if res.StatusCode != fiber.StatusCreated {
+		t.Fatalf("expected 201 for the first request, got %d", res.StatusCode)
+	}
+	if inFlight != fiber.StatusConflict {
+		t.Errorf("expected 409 for a retry in flight, got %d", inFlight)
+	}
+}
+
+func TestIdempotencyReleasesKeyAfterFailure(t *testing.T) {
+	cases := []struct {
+		name string
+		fail fiber.Handler
+	}{
+		{"server error", func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusInternalServerError) }},
+		{"handler error", func(c fiber.Ctx) error { return errors.New("database down") }},
+	}
+	for _, v := range cases {
+		t.Run(v.name, func(t *testing.T) {
+			var calls int
+			app, mr := newIdempotencyApp(t, func(c fiber.Ctx) error {
+				calls++
+				if calls == 1 {
+					return v.fail(c)
+				}
+				return c.SendStatus(fiber.StatusCreated)
+			})
+
+			sendIdempotent(t, app, "create-1", `{"name":"a"}`)
+			if keys := mr.Keys(); len(keys) != 0 {
+				t.Fatalf("expected the key to be released, still stored: %v", keys)
+			}
+
+			res, _ := sendIdempotent(t, app, "create-1", `{"name":"a"}`)
+			if res.StatusCode != fiber.StatusCreated || calls != 2 {
+				t.Errorf("expected the retry to run again, got %d after %d calls", res.StatusCode, calls)
+			}
+			if res.Header.Get(idempotencyReplayedHeader) != "" {
+				t.Errorf("retry after a failure must not be a replay")
+			}
+		})
+	}
+}
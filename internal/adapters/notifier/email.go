@@ -0,0 +1,61 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	"app/xonvera-core/internal/infrastructure/config"
+)
+
+// emailNotifier sends plain text emails through an SMTP server
+type emailNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewEmailNotifier(cfg *config.NotifierConfig) portRepository.Notifier {
+	var auth smtp.Auth
+	if cfg.SMTPUser != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUser, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return &emailNotifier{
+		addr: net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+		auth: auth,
+		from: cfg.SMTPFrom,
+	}
+}
+
+func (n *emailNotifier) Supports(channel domain.NotificationChannel) bool {
+	return channel == domain.NotificationEmail
+}
+
+func (n *emailNotifier) Send(ctx context.Context, msg *domain.Notification) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// Recipients come from customer records, a line break would inject headers
+	if strings.ContainsAny(msg.Recipient, "\r\n") {
+		return fmt.Errorf("invalid email recipient")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.Recipient)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{msg.Recipient}, []byte(b.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+// logNotifier stands in for real delivery in local setups, every message is appended to a
+// JSON lines file, or written to the application log when no file is set
+type logNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewLogNotifier(path string) portRepository.Notifier {
+	return &logNotifier{path: path}
+}
+
+func (n *logNotifier) Supports(domain.NotificationChannel) bool {
+	return true
+}
+
+func (n *logNotifier) Send(ctx context.Context, msg *domain.Notification) error {
+	if n.path == "" {
+		logger.StdContextInfo(ctx, "notification",
+			zap.String("channel", string(msg.Channel)),
+			zap.String("recipient", msg.Recipient),
+			zap.String("subject", msg.Subject),
+			zap.String("body", msg.Body),
+		)
+		return nil
+	}
+
+	line, err := json.Marshal(map[string]any{
+		"time":      time.Now(),
+		"channel":   msg.Channel,
+		"recipient": msg.Recipient,
+		"subject":   msg.Subject,
+		"body":      msg.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,46 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	"app/xonvera-core/internal/infrastructure/config"
+)
+
+// NewNotifier returns the notifier selected by NOTIFIER_DRIVER.
+// The "log" driver writes every message to a file or the application log, any other driver
+// sends them over the channels that are configured.
+func NewNotifier(cfg *config.NotifierConfig) portRepository.Notifier {
+	if cfg.Driver == "log" {
+		return NewLogNotifier(cfg.LogFile)
+	}
+
+	channels := make(map[domain.NotificationChannel]portRepository.Notifier)
+	if cfg.SMTPHost != "" {
+		channels[domain.NotificationEmail] = NewEmailNotifier(cfg)
+	}
+	if cfg.WhatsAppToken != "" && cfg.WhatsAppPhoneID != "" {
+		channels[domain.NotificationWhatsApp] = NewWhatsAppNotifier(cfg)
+	}
+	return &multiNotifier{channels: channels}
+}
+
+// multiNotifier hands every message to the notifier of its channel
+type multiNotifier struct {
+	channels map[domain.NotificationChannel]portRepository.Notifier
+}
+
+func (n *multiNotifier) Supports(channel domain.NotificationChannel) bool {
+	_, ok := n.channels[channel]
+	return ok
+}
+
+func (n *multiNotifier) Send(ctx context.Context, msg *domain.Notification) error {
+	notifier, ok := n.channels[msg.Channel]
+	if !ok {
+		return fmt.Errorf("notification channel %s is not configured", msg.Channel)
+	}
+	return notifier.Send(ctx, msg)
+}
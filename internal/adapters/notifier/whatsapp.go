@@ -0,0 +1,85 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	"app/xonvera-core/internal/infrastructure/config"
+)
+
+// whatsAppTimeout bounds a call to the WhatsApp API
+const whatsAppTimeout = 15 * time.Second
+
+// whatsAppNotifier sends text messages through the WhatsApp Business Cloud API
+type whatsAppNotifier struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewWhatsAppNotifier(cfg *config.NotifierConfig) portRepository.Notifier {
+	return &whatsAppNotifier{
+		url:    strings.TrimRight(cfg.WhatsAppURL, "/") + "/" + cfg.WhatsAppPhoneID + "/messages",
+		token:  cfg.WhatsAppToken,
+		client: &http.Client{Timeout: whatsAppTimeout},
+	}
+}
+
+func (n *whatsAppNotifier) Supports(channel domain.NotificationChannel) bool {
+	return channel == domain.NotificationWhatsApp
+}
+
+func (n *whatsAppNotifier) Send(ctx context.Context, msg *domain.Notification) error {
+	to := phoneDigits(msg.Recipient)
+	if to == "" {
+		return fmt.Errorf("invalid whatsapp recipient")
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"messaging_product": "whatsapp",
+		"to":                to,
+		"type":              "text",
+		"text":              map[string]string{"body": msg.Body},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+n.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send whatsapp message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("whatsapp api responded %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// phoneDigits keeps the digits of a phone number, the API expects it in international format without "+"
+func phoneDigits(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+)
+
+type businessProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewBusinessProfileRepository(db *gorm.DB) portRepository.BusinessProfileRepository {
+	return &businessProfileRepository{db: db}
+}
+
+func (r *businessProfileRepository) GetByAuthorID(ctx context.Context, authorID uint) ([]domain.BusinessProfile, error) {
+	var profiles []domain.BusinessProfile
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND deleted_at IS NULL", authorID).
+		Order("is_default DESC, name ASC").
+		Find(&profiles).Error
+	if err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func (r *businessProfileRepository) GetByID(ctx context.Context, authorID, id uint) (*domain.BusinessProfile, error) {
+	var profile domain.BusinessProfile
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", id, authorID).
+		First(&profile).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundBusinessProfile)
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetDefault returns the default profile of an author, or nil when none is set
+func (r *businessProfileRepository) GetDefault(ctx context.Context, authorID uint) (*domain.BusinessProfile, error) {
+	var profile domain.BusinessProfile
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND is_default AND deleted_at IS NULL", authorID).
+		First(&profile).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *businessProfileRepository) GetBankAccounts(ctx context.Context, profileIDs []uint) ([]domain.BankAccount, error) {
+	var accounts []domain.BankAccount
+	if len(profileIDs) == 0 {
+		return accounts, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("profile_id IN ?", profileIDs).
+		Order("profile_id ASC, id ASC").
+		Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *businessProfileRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.BusinessProfile) error {
+	return txDb(tx, r.db).WithContext(ctx).Create(data).Error
+}
+
+func (r *businessProfileRepository) Update(ctx context.Context, tx portRepository.Transaction, data *domain.BusinessProfile) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.BusinessProfile{}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"name":              data.Name,
+			"legal_name":        data.LegalName,
+			"address":           data.Address,
+			"tax_id":            data.TaxID,
+			"email":             data.Email,
+			"phone":             data.Phone,
+			"payment_term_days": data.PaymentTermDays,
+			"footer_text":       data.FooterText,
+			"is_default":        data.IsDefault,
+			"updated_at":        data.UpdatedAt,
+		}).
+		Error
+}
+
+func (r *businessProfileRepository) UpdateLogo(ctx context.Context, data *domain.BusinessProfile) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.BusinessProfile{}).
+		Where("id = ? AND author_id = ?", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"logo_path":  data.LogoPath,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
+
+// ClearDefault unsets the default flag on every other profile of an author
+func (r *businessProfileRepository) ClearDefault(ctx context.Context, tx portRepository.Transaction, authorID, exceptID uint) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.BusinessProfile{}).
+		Where("author_id = ? AND id <> ? AND is_default", authorID, exceptID).
+		Update("is_default", false).
+		Error
+}
+
+func (r *businessProfileRepository) ReplaceBankAccounts(ctx context.Context, tx portRepository.Transaction, profileID uint, data []domain.BankAccount) error {
+	db := txDb(tx, r.db).WithContext(ctx)
+	if err := db.Where("profile_id = ?", profileID).Delete(&domain.BankAccount{}).Error; err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return db.CreateInBatches(data, 100).Error
+}
+
+func (r *businessProfileRepository) SoftDelete(ctx context.Context, data *domain.BusinessProfile) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.BusinessProfile{}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"is_default": false,
+			"deleted_at": data.DeletedAt,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
@@ -0,0 +1,180 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type creditNoteRepository struct {
+	db *gorm.DB
+}
+
+func NewCreditNoteRepository(db *gorm.DB) portRepository.CreditNoteRepository {
+	return &creditNoteRepository{db: db}
+}
+
+// Get lists the credit notes of a user with the invoice each one credits, newest first
+func (r *creditNoteRepository) Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
+	query := r.db.WithContext(ctx).Table("app.credit_notes AS cn").
+		Select("cn.*, i.public_id AS invoice_public_id, i.number AS invoice_number").
+		Joins("JOIN app.invoices i ON i.author_id = cn.author_id AND i.id = cn.invoice_id").
+		Where("cn.author_id = ?", req.UserID)
+
+	if req.Search != "" {
+		search := "%" + req.Search + "%"
+		query = query.Where("cn.number ILIKE ? OR cn.customer ILIKE ? OR i.number ILIKE ?", search, search, search)
+	}
+
+	type CreditNoteWithInvoice struct {
+		domain.CreditNote
+		InvoicePublicID string `gorm:"column:invoice_public_id"`
+		InvoiceNumber   string `gorm:"column:invoice_number"`
+	}
+
+	order := listOrder{Name: "created_at", Keys: []listKey{{"cn.created_at", listKeyTime}, {"cn.id", listKeyInt}}, Desc: true}
+	data, meta, err := paginate(query, req, order, func(v *CreditNoteWithInvoice) []any { return []any{v.CreatedAt, v.ID} })
+	if err != nil {
+		return nil, err
+	}
+
+	resp := domain.PaginationResponse{Meta: meta}
+	resp.Data = make([]any, len(data))
+	for i, v := range data {
+		invoices := map[int64]domain.InvoiceRef{
+			v.InvoiceID: {ID: v.InvoicePublicID, Number: v.InvoiceNumber},
+		}
+		resp.Data[i] = v.Response(invoices, nil, nil)
+	}
+
+	return &resp, nil
+}
+
+func (r *creditNoteRepository) GetByID(ctx context.Context, authorID, id uint) (*domain.CreditNote, error) {
+	var note domain.CreditNote
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND author_id = ?", id, authorID).
+		First(&note).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundCreditNote)
+		}
+		return nil, err
+	}
+	return &note, nil
+}
+
+// LockByID retrieves a credit note and locks its row until the transaction ends
+func (r *creditNoteRepository) LockByID(ctx context.Context, tx portRepository.Transaction, authorID, id uint) (*domain.CreditNote, error) {
+	var note domain.CreditNote
+	err := txDb(tx, r.db).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND author_id = ?", id, authorID).
+		First(&note).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundCreditNote)
+		}
+		return nil, err
+	}
+	return &note, nil
+}
+
+func (r *creditNoteRepository) GetItems(ctx context.Context, creditNoteID uint) ([]domain.CreditNoteItem, error) {
+	var items []domain.CreditNoteItem
+	err := r.db.WithContext(ctx).
+		Where("credit_note_id = ?", creditNoteID).
+		Order("id ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetAllocations lists where the credit of a credit note went, oldest first
+func (r *creditNoteRepository) GetAllocations(ctx context.Context, creditNoteID uint) ([]domain.CreditAllocation, error) {
+	var allocations []domain.CreditAllocation
+	err := r.db.WithContext(ctx).
+		Where("credit_note_id = ?", creditNoteID).
+		Order("created_at ASC, id ASC").
+		Find(&allocations).Error
+	if err != nil {
+		return nil, err
+	}
+	return allocations, nil
+}
+
+// GetCreditedItems sums what every credit note of an invoice credited per invoice item
+func (r *creditNoteRepository) GetCreditedItems(ctx context.Context, tx portRepository.Transaction, authorID uint, invoiceID int64) ([]domain.CreditedItem, error) {
+	var data []domain.CreditedItem
+	err := txDb(tx, r.db).WithContext(ctx).
+		Table("app.credit_note_items AS ci").
+		Select("ci.invoice_item_id, SUM(ci.qty) AS qty, SUM(ci.subtotal) AS subtotal, SUM(ci.tax_amount) AS tax_amount").
+		Joins("JOIN app.credit_notes cn ON cn.id = ci.credit_note_id").
+		Where("cn.author_id = ? AND cn.invoice_id = ?", authorID, invoiceID).
+		Group("ci.invoice_item_id").
+		Scan(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// SumAllocations returns the total credit applied to an invoice from any credit note
+func (r *creditNoteRepository) SumAllocations(ctx context.Context, tx portRepository.Transaction, authorID uint, invoiceID int64) (int, error) {
+	var total int
+	err := txDb(tx, r.db).WithContext(ctx).
+		Model(&domain.CreditAllocation{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// NumberExists reports whether an author already used a credit note number
+func (r *creditNoteRepository) NumberExists(ctx context.Context, tx portRepository.Transaction, authorID uint, number string) (bool, error) {
+	var count int64
+	err := txDb(tx, r.db).WithContext(ctx).
+		Model(&domain.CreditNote{}).
+		Where("author_id = ? AND number = ?", authorID, number).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *creditNoteRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.CreditNote) error {
+	return txDb(tx, r.db).WithContext(ctx).Create(data).Error
+}
+
+func (r *creditNoteRepository) CreateItems(ctx context.Context, tx portRepository.Transaction, data []domain.CreditNoteItem) error {
+	return txDb(tx, r.db).WithContext(ctx).CreateInBatches(data, 100).Error
+}
+
+func (r *creditNoteRepository) CreateAllocation(ctx context.Context, tx portRepository.Transaction, data *domain.CreditAllocation) error {
+	return txDb(tx, r.db).WithContext(ctx).Create(data).Error
+}
+
+// UpdateBalance stores how much of a credit note was applied and refunded
+func (r *creditNoteRepository) UpdateBalance(ctx context.Context, tx portRepository.Transaction, data *domain.CreditNote) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.CreditNote{}).
+		Where("id = ? AND author_id = ?", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"amount_applied":  data.AmountApplied,
+			"amount_refunded": data.AmountRefunded,
+			"updated_at":      data.UpdatedAt,
+		}).
+		Error
+}
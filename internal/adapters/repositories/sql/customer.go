@@ -0,0 +1,101 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+)
+
+type customerRepository struct {
+	db *gorm.DB
+}
+
+func NewCustomerRepository(db *gorm.DB) portRepository.CustomerRepository {
+	return &customerRepository{db: db}
+}
+
+func (r *customerRepository) Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Customer{}).
+		Where("author_id = ? AND deleted_at IS NULL", req.UserID)
+
+	if req.Search != "" {
+		search := "%" + req.Search + "%"
+		query = query.Where("name ILIKE ? OR company ILIKE ? OR email ILIKE ? OR phone ILIKE ?", search, search, search, search)
+	}
+
+	order := listOrder{Name: "name", Keys: []listKey{{"name", listKeyText}, {"id", listKeyInt}}}
+	data, meta, err := paginate(query, req, order, func(v *domain.Customer) []any { return []any{v.Name, v.ID} })
+	if err != nil {
+		return nil, err
+	}
+
+	resp := domain.PaginationResponse{Meta: meta}
+	resp.Data = make([]any, len(data))
+	for i, v := range data {
+		resp.Data[i] = v.Response()
+	}
+
+	return &resp, nil
+}
+
+func (r *customerRepository) GetByID(ctx context.Context, authorID, id uint) (*domain.Customer, error) {
+	var customer domain.Customer
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", id, authorID).
+		First(&customer).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundCustomer)
+		}
+		return nil, err
+	}
+	return &customer, nil
+}
+
+func (r *customerRepository) GetByAuthorID(ctx context.Context, authorID uint) ([]domain.Customer, error) {
+	var customers []domain.Customer
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND deleted_at IS NULL", authorID).
+		Order("id ASC").
+		Find(&customers).Error
+	if err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+func (r *customerRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.Customer) error {
+	return txDb(tx, r.db).WithContext(ctx).Create(data).Error
+}
+
+func (r *customerRepository) Update(ctx context.Context, data *domain.Customer) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Customer{}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"name":       data.Name,
+			"company":    data.Company,
+			"email":      data.Email,
+			"phone":      data.Phone,
+			"address":    data.Address,
+			"tax_id":     data.TaxID,
+			"notes":      data.Notes,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
+
+func (r *customerRepository) SoftDelete(ctx context.Context, data *domain.Customer) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Customer{}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"deleted_at": data.DeletedAt,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
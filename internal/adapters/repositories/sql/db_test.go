@@ -0,0 +1,133 @@
+package repositoriesSql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeTable holds the rows of one table in column order
+type fakeTable struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+var (
+	fakeTableRe  = regexp.MustCompile(`FROM "app"\."(\w+)"`)
+	fakeEqualRe  = regexp.MustCompile(`\b(\w+) = \$(\d+)`)
+	fakeIsNullRe = regexp.MustCompile(`\b(\w+) IS (NOT )?NULL`)
+)
+
+// newFakeDB opens gorm on tables kept in memory. A select returns the rows of its table that match
+// every "column = $n" and "column IS [NOT] NULL" condition of the query, so a condition the
+// repository leaves out lets rows of other authors through as it would on Postgres.
+func newFakeDB(t *testing.T, tables map[string]*fakeTable) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(fakeConnector{tables})}), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return db
+}
+
+type fakeConnector struct {
+	tables map[string]*fakeTable
+}
+
+func (c fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return fakeConn(c), nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	tables map[string]*fakeTable
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	m := fakeTableRe.FindStringSubmatch(query)
+	if m == nil {
+		return nil, fmt.Errorf("unexpected query %q", query)
+	}
+	table, ok := c.tables[m[1]]
+	if !ok {
+		return nil, fmt.Errorf("unknown table %q", m[1])
+	}
+
+	index := make(map[string]int, len(table.columns))
+	for i, v := range table.columns {
+		index[v] = i
+	}
+
+	res := &fakeRows{columns: table.columns}
+	for _, row := range table.rows {
+		match := true
+		for _, v := range fakeEqualRe.FindAllStringSubmatch(query, -1) {
+			var n int
+			fmt.Sscan(v[2], &n)
+			col, ok := index[v[1]]
+			if !ok || n < 1 || n > len(args) {
+				return nil, fmt.Errorf("unexpected condition %q", v[0])
+			}
+			match = match && fmt.Sprint(row[col]) == fmt.Sprint(args[n-1].Value)
+		}
+		for _, v := range fakeIsNullRe.FindAllStringSubmatch(query, -1) {
+			col, ok := index[v[1]]
+			if !ok {
+				return nil, fmt.Errorf("unexpected condition %q", v[0])
+			}
+			match = match && (row[col] == nil) == (v[2] == "")
+		}
+		if match {
+			res.rows = append(res.rows, row)
+		}
+	}
+	return res, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
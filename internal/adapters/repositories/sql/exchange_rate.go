@@ -0,0 +1,78 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) portRepository.ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+// NewTableExchangeRateProvider returns a provider reading the manually entered rates,
+// it needs no network access
+func NewTableExchangeRateProvider(db *gorm.DB) portRepository.ExchangeRateProvider {
+	return &exchangeRateRepository{db: db}
+}
+
+func (r *exchangeRateRepository) GetByAuthorID(ctx context.Context, authorID uint) ([]domain.ExchangeRate, error) {
+	var rates []domain.ExchangeRate
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND deleted_at IS NULL", authorID).
+		Order("effective_date DESC, currency ASC").
+		Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// Save stores a rate, a rate for the same pair and date is replaced
+func (r *exchangeRateRepository) Save(ctx context.Context, data *domain.ExchangeRate) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "author_id"}, {Name: "currency"}, {Name: "base_currency"}, {Name: "effective_date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+		}).
+		Create(data).Error
+}
+
+// GetRate returns the latest rate effective on date, falling back to the inverse of the opposite pair
+func (r *exchangeRateRepository) GetRate(ctx context.Context, authorID uint, currency, base string, date time.Time) (*domain.ExchangeRate, error) {
+	var rate domain.ExchangeRate
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND currency = ? AND base_currency = ? AND effective_date <= ?", authorID, currency, base, date.Format(time.DateOnly)).
+		Order("effective_date DESC").
+		First(&rate).Error
+	if err == nil {
+		return &rate, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Where("author_id = ? AND currency = ? AND base_currency = ? AND effective_date <= ?", authorID, base, currency, date.Format(time.DateOnly)).
+		Order("effective_date DESC").
+		First(&rate).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundExchangeRate)
+		}
+		return nil, err
+	}
+
+	inverse := rate.Inverse()
+	return &inverse, nil
+}
@@ -9,8 +9,12 @@ import (
 	portRepository "app/xonvera-core/internal/core/ports/repository"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// maxInvoiceSuffix keeps the widest invoice ID within int64
+const maxInvoiceSuffix = 9999999999
+
 type invoiceRepository struct {
 	db *gorm.DB
 }
@@ -19,67 +23,157 @@ func NewInvoiceRepository(db *gorm.DB) portRepository.InvoiceRepository {
 	return &invoiceRepository{db: db}
 }
 
-func (r *invoiceRepository) Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
-	query := r.db.WithContext(ctx).Model(&domain.Invoice{}).
-		Select("invoice.*, COUNT(*) OVER() as total_count").
-		Where("author_id = ?", req.UserID).Order("created_at DESC")
+func (r *invoiceRepository) Get(ctx context.Context, req *domain.InvoicePageReq) (*domain.PaginationResponse, error) {
+	return r.page(ctx, req, false)
+}
 
-	// apply pagination
-	if req.Limit > 0 {
-		query = query.Limit(int(req.Limit))
-	}
-	if req.Offset > 0 {
-		query = query.Offset(int(req.Offset))
-	}
+// GetDeleted lists the soft deleted invoices of a user, most recently deleted first
+func (r *invoiceRepository) GetDeleted(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
+	return r.page(ctx, &domain.InvoicePageReq{PaginationRequest: *req}, true)
+}
 
-	type InvoiceWithCount struct {
-		domain.Invoice
-		TotalCount uint64 `gorm:"column:total_count"`
-	}
+// invoiceSortKeys maps the sort fields of domain.InvoicePageReq to their sort key and value
+var invoiceSortKeys = map[string]struct {
+	key   listKey
+	value func(*domain.Invoice) any
+}{
+	"created_at": {listKey{"created_at", listKeyTime}, func(v *domain.Invoice) any { return v.CreatedAt }},
+	"issue_date": {listKey{"issue_date", listKeyText}, func(v *domain.Invoice) any { return v.IssueDate }},
+	// Invoices without a due date read as the zero time, which sorts them first
+	"due_date": {listKey{"COALESCE(due_date, '0001-01-01 00:00:00+00')", listKeyTime}, func(v *domain.Invoice) any { return v.DueDate }},
+	"number":   {listKey{"number", listKeyText}, func(v *domain.Invoice) any { return v.Number }},
+	"customer": {listKey{"customer", listKeyText}, func(v *domain.Invoice) any { return v.Customer }},
+	"status":   {listKey{"status", listKeyText}, func(v *domain.Invoice) any { return v.Status }},
+	"total":    {listKey{"total", listKeyInt}, func(v *domain.Invoice) any { return v.Total }},
+}
 
-	var data []InvoiceWithCount
-	err := query.Scan(&data).Error
+func (r *invoiceRepository) page(ctx context.Context, req *domain.InvoicePageReq, deleted bool) (*domain.PaginationResponse, error) {
+	query, order, keys := r.list(ctx, req, deleted)
+	data, meta, err := paginate(query, &req.PaginationRequest, order, keys)
 	if err != nil {
 		return nil, err
 	}
 
-	var count uint64
-	if len(data) > 0 {
-		count = data[0].TotalCount
+	resp := domain.PaginationResponse{Meta: meta, Data: make([]any, len(data))}
+	for i, v := range data {
+		resp.Data[i] = v.Response(nil, nil)
 	}
 
-	var resp domain.PaginationResponse
-	resp.Meta = domain.PaginationMetaResponse{
-		Page:      req.Page,
-		Limit:     req.Limit,
-		TotalData: uint64(count),
-		TotalPage: GetTotalPage(count, req.Limit),
+	return &resp, nil
+}
+
+// Export walks the invoices matching a list request in its sort order, size at a time,
+// so an export never holds every invoice in memory
+func (r *invoiceRepository) Export(ctx context.Context, req *domain.InvoicePageReq, size uint32, fn func([]domain.Invoice) error) error {
+	page := *req
+	page.Mode, page.Cursor, page.WithTotal, page.Limit = domain.PaginationCursor, "", false, size
+	for {
+		query, order, keys := r.list(ctx, &page, false)
+		data, meta, err := paginate(query, &page.PaginationRequest, order, keys)
+		if err != nil {
+			return err
+		}
+		if len(data) > 0 {
+			if err = fn(data); err != nil {
+				return err
+			}
+		}
+		if meta.NextCursor == "" {
+			return nil
+		}
+		page.Cursor = meta.NextCursor
 	}
+}
 
-	resp.Data = make([]any, len(data))
-	for i, v := range data {
-		resp.Data[i] = v.Response(nil)
+// Count returns how many invoices match the list filters
+func (r *invoiceRepository) Count(ctx context.Context, req *domain.InvoicePageReq) (int64, error) {
+	query, _, _ := r.list(ctx, req, false)
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// list builds the filtered query of an invoice list with its sort order
+func (r *invoiceRepository) list(ctx context.Context, req *domain.InvoicePageReq, deleted bool) (*gorm.DB, listOrder, func(*domain.Invoice) []any) {
+	query := r.db.WithContext(ctx).Model(&domain.Invoice{}).
+		Where("author_id = ?", req.UserID)
+
+	var order listOrder
+	var keys func(*domain.Invoice) []any
+	if deleted {
+		query = query.Where("deleted_at IS NOT NULL")
+		order = listOrder{Name: "deleted", Keys: []listKey{{"deleted_at", listKeyTime}, {"id", listKeyInt}}, Desc: true}
+		keys = func(v *domain.Invoice) []any { return []any{*v.DeletedAt, v.ID} }
+	} else {
+		query = query.Where("deleted_at IS NULL")
+		// The sort field is checked against a whitelist by InvoicePageReq.Normalize
+		field, desc := req.SortField, req.SortDesc
+		if _, ok := invoiceSortKeys[field]; !ok {
+			field, desc = "created_at", true
+		}
+		sort := invoiceSortKeys[field]
+		order = listOrder{Name: field, Keys: []listKey{sort.key, {"id", listKeyInt}}, Desc: desc}
+		keys = func(v *domain.Invoice) []any { return []any{sort.value(v), v.ID} }
 	}
 
-	return &resp, nil
+	if req.Search != "" {
+		search := containsPattern(req.Search)
+		query = query.Where(
+			`number ILIKE ? ESCAPE '\' OR customer ILIKE ? ESCAPE '\' OR customer_company ILIKE ? ESCAPE '\'
+			OR issuer ILIKE ? ESCAPE '\' OR note ILIKE ? ESCAPE '\' OR EXISTS (
+				SELECT 1 FROM app.invoice_items it
+				WHERE it.author_id = app.invoices.author_id AND it.invoice_id = app.invoices.id
+				AND it.deleted_at IS NULL AND it.description ILIKE ? ESCAPE '\')`,
+			search, search, search, search, search, search)
+	}
+
+	// apply filters
+	if len(req.Statuses) > 0 {
+		query = query.Where("status IN ?", req.Statuses)
+	}
+	if req.CustomerID > 0 {
+		query = query.Where("customer_id = ?", req.CustomerID)
+	}
+	if req.IssueFrom != "" {
+		query = query.Where("issue_date >= ?", req.IssueFrom)
+	}
+	if req.IssueTo != "" {
+		query = query.Where("issue_date <= ?", req.IssueTo)
+	}
+	if req.DueFrom != "" {
+		query = query.Where("due_date::date >= ?", req.DueFrom)
+	}
+	if req.DueTo != "" {
+		query = query.Where("due_date::date <= ?", req.DueTo)
+	}
+	if req.MinTotal != nil {
+		query = query.Where("total >= ?", *req.MinTotal)
+	}
+	if req.MaxTotal != nil {
+		query = query.Where("total <= ?", *req.MaxTotal)
+	}
+	if req.Overdue {
+		// Invoices past due are included before the overdue job marks them
+		query = query.Where("(status = ? OR (status IN ? AND due_date < ?))", domain.InvoiceStatusOverdue,
+			[]domain.InvoiceStatus{domain.InvoiceStatusSent, domain.InvoiceStatusPartiallyPaid}, time.Now())
+	}
+
+	return query, order, keys
 }
 
-// GenerateInvoiceID generates an invoice ID with format: 2YYYYMMDDUUUUUSSSS
+// GenerateInvoiceID generates the internal invoice ID with format: 2YYYYMMDDSSSS
 // 2 => invoice id prefix
 // YYYYMMDD => year, month, date
-// UUUUU => user id (5 digits, zero-padded)
-// SSSS => per-user daily suffix (1-9999)
+// SSSS => per-user daily suffix, zero-padded to at least 4 digits and widened past 9999
+// The human facing number comes from the numbering scheme, see Invoice.Number.
 func (r *invoiceRepository) GenerateInvoiceID(ctx context.Context, tx portRepository.Transaction, userID uint, date time.Time) (int64, error) {
 
-	// Format: 2 + YYYYMMDD + UUUUU + SSSS
+	// Format: 2 + YYYYMMDD + SSSS
 	prefix := "2"
 	dateStr := date.Format("20060102") // YYYYMMDD
 	dateOnly := date.Format("2006-01-02")
 
-	// lock the row for this user and date to prevent race condition
-	txDb(tx, r.db).Raw("select id from app.invoice_user_daily_seq where user_id = ? and day = ? for update", userID, dateOnly).Scan(nil) // dummy query to ensure tx is used
-
-	// Get per-user daily sequence value atomically with row lock
+	// The upsert takes the row lock of this user and day, concurrent calls wait for it and get the next counter
 	var suffix int64
 	err := txDb(tx, r.db).WithContext(ctx).Raw(
 		`INSERT INTO app.invoice_user_daily_seq (user_id, day, counter)
@@ -92,7 +186,7 @@ func (r *invoiceRepository) GenerateInvoiceID(ctx context.Context, tx portReposi
 	if err != nil {
 		return 0, err
 	}
-	if suffix > 9999 {
+	if suffix > maxInvoiceSuffix {
 		return 0, fmt.Errorf("invoice suffix overflow for user %d on %s", userID, dateStr)
 	}
 
@@ -107,9 +201,44 @@ func (r *invoiceRepository) GenerateInvoiceID(ctx context.Context, tx portReposi
 	return invoiceID, nil
 }
 
-func (r *invoiceRepository) GetByID(ctx context.Context, id int64) (*domain.Invoice, error) {
+// GetByPublicID retrieves an invoice of an author by the identifier used in URLs
+func (r *invoiceRepository) GetByPublicID(ctx context.Context, authorID uint, publicID string) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	err := r.db.WithContext(ctx).
+		Where("public_id = ? AND author_id = ? AND deleted_at IS NULL", publicID, authorID).
+		First(&invoice).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundInvoice)
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// LockByPublicID retrieves an invoice of an author and locks its row until the transaction ends
+func (r *invoiceRepository) LockByPublicID(ctx context.Context, tx portRepository.Transaction, authorID uint, publicID string) (*domain.Invoice, error) {
+	return r.lock(ctx, tx, authorID, publicID, false)
+}
+
+// LockDeletedByPublicID retrieves a soft deleted invoice of an author and locks its row until the transaction ends
+func (r *invoiceRepository) LockDeletedByPublicID(ctx context.Context, tx portRepository.Transaction, authorID uint, publicID string) (*domain.Invoice, error) {
+	return r.lock(ctx, tx, authorID, publicID, true)
+}
+
+func (r *invoiceRepository) lock(ctx context.Context, tx portRepository.Transaction, authorID uint, publicID string, deleted bool) (*domain.Invoice, error) {
+	query := txDb(tx, r.db).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("public_id = ? AND author_id = ?", publicID, authorID)
+
+	if deleted {
+		query = query.Where("deleted_at IS NOT NULL")
+	} else {
+		query = query.Where("deleted_at IS NULL")
+	}
+
 	var invoice domain.Invoice
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&invoice).Error
+	err := query.First(&invoice).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf(domain.ErrNotFoundInvoice)
@@ -119,9 +248,22 @@ func (r *invoiceRepository) GetByID(ctx context.Context, id int64) (*domain.Invo
 	return &invoice, nil
 }
 
-func (r *invoiceRepository) GetItems(ctx context.Context, invoiceID []int64) ([]domain.InvoiceItem, error) {
+// GetByIDs retrieves invoices of an author by internal ID, deleted invoices included
+func (r *invoiceRepository) GetByIDs(ctx context.Context, authorID uint, ids []int64) ([]domain.Invoice, error) {
+	var invoices []domain.Invoice
+	if len(ids) == 0 {
+		return invoices, nil
+	}
+	err := r.db.WithContext(ctx).Where("author_id = ? AND id IN ?", authorID, ids).Find(&invoices).Error
+	if err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (r *invoiceRepository) GetItems(ctx context.Context, authorID uint, invoiceID []int64) ([]domain.InvoiceItem, error) {
 	var items []domain.InvoiceItem
-	err := r.db.WithContext(ctx).Where("invoice_id IN ?", invoiceID).Find(&items).Error
+	err := r.db.WithContext(ctx).Where("author_id = ? AND invoice_id IN ?", authorID, invoiceID).Find(&items).Error
 	if err != nil {
 		return nil, err
 	}
@@ -129,15 +271,77 @@ func (r *invoiceRepository) GetItems(ctx context.Context, invoiceID []int64) ([]
 }
 
 // GetItemsByInvoiceID retrieves all items for a specific invoice
-func (r *invoiceRepository) GetItemsByInvoiceID(ctx context.Context, invoiceID int64) ([]domain.InvoiceItem, error) {
+func (r *invoiceRepository) GetItemsByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceItem, error) {
 	var items []domain.InvoiceItem
-	err := r.db.WithContext(ctx).Where("invoice_id = ?", invoiceID).Find(&items).Error
+	err := r.db.WithContext(ctx).Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).Order("id ASC").Find(&items).Error
 	if err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
+// GetTaxesByInvoiceID retrieves the persisted tax breakdown of an invoice
+func (r *invoiceRepository) GetTaxesByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceTax, error) {
+	var taxes []domain.InvoiceTax
+	err := r.db.WithContext(ctx).Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).Order("id ASC").Find(&taxes).Error
+	if err != nil {
+		return nil, err
+	}
+	return taxes, nil
+}
+
+// HasCreditNotes reports whether credit notes were issued against an invoice
+func (r *invoiceRepository) HasCreditNotes(ctx context.Context, tx portRepository.Transaction, authorID uint, invoiceID int64) (bool, error) {
+	var count int64
+	err := txDb(tx, r.db).WithContext(ctx).
+		Model(&domain.CreditNote{}).
+		Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// HasRecurringRun reports whether the invoice of a recurring run exists, deleted invoices included
+func (r *invoiceRepository) HasRecurringRun(ctx context.Context, tx portRepository.Transaction, recurringID uint, runDate string) (bool, error) {
+	var count int64
+	err := txDb(tx, r.db).WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("recurring_id = ? AND recurring_run_date = ?", recurringID, runDate).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// HasImportRef reports whether an invoice was imported for a reference, deleted invoices included
+func (r *invoiceRepository) HasImportRef(ctx context.Context, tx portRepository.Transaction, authorID uint, ref string) (bool, error) {
+	var count int64
+	err := txDb(tx, r.db).WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("author_id = ? AND import_ref = ?", authorID, ref).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetImportRefs returns which of refs already have an imported invoice, deleted invoices included
+func (r *invoiceRepository) GetImportRefs(ctx context.Context, authorID uint, refs []string) ([]string, error) {
+	var res []string
+	if len(refs) == 0 {
+		return res, nil
+	}
+	err := r.db.WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("author_id = ? AND import_ref IN ?", authorID, refs).
+		Pluck("import_ref", &res).Error
+	return res, err
+}
+
 func (r *invoiceRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
 	return txDb(tx, r.db).WithContext(ctx).Create(data).Error
 }
@@ -146,14 +350,43 @@ func (r *invoiceRepository) CreateItem(ctx context.Context, tx portRepository.Tr
 	return txDb(tx, r.db).WithContext(ctx).CreateInBatches(data, 100).Error
 }
 
+func (r *invoiceRepository) CreateTaxes(ctx context.Context, tx portRepository.Transaction, data []domain.InvoiceTax) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return txDb(tx, r.db).WithContext(ctx).CreateInBatches(data, 100).Error
+}
+
 func (r *invoiceRepository) Update(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
 	updates := map[string]interface{}{
-		"issuer":     data.Issuer,
-		"customer":   data.Customer,
-		"issue_date": data.IssueDate,
-		"due_date":   data.DueDate,
-		"note":       data.Note,
-		"updated_at": data.UpdatedAt,
+		"profile_id":       data.ProfileID,
+		"issuer":           data.Issuer,
+		"customer":         data.Customer,
+		"customer_id":      data.CustomerID,
+		"customer_company": data.CustomerCompany,
+		"customer_email":   data.CustomerEmail,
+		"customer_phone":   data.CustomerPhone,
+		"customer_address": data.CustomerAddress,
+		"customer_tax_id":  data.CustomerTaxID,
+		"issue_date":       data.IssueDate,
+		"due_date":         data.DueDate,
+		"note":             data.Note,
+		"status":           data.Status,
+		"currency":         data.Currency,
+		"exchange_rate":    data.ExchangeRate,
+		"base_currency":    data.BaseCurrency,
+		"base_total":       data.BaseTotal,
+		"tax_mode":         data.TaxMode,
+		"tax_rate_id":      data.TaxRateID,
+		"discount_type":    data.DiscountType,
+		"discount":         data.Discount,
+		"subtotal":         data.Subtotal,
+		"discount_amount":  data.DiscountAmount,
+		"discount_total":   data.DiscountTotal,
+		"tax_total":        data.TaxTotal,
+		"total":            data.Total,
+		"version":          data.Version,
+		"updated_at":       data.UpdatedAt,
 	}
 
 	return txDb(tx, r.db).
@@ -164,6 +397,175 @@ func (r *invoiceRepository) Update(ctx context.Context, tx portRepository.Transa
 		Error
 }
 
-func (r *invoiceRepository) DeleteItemsByInvoiceID(ctx context.Context, tx portRepository.Transaction, invoiceID int64) error {
-	return txDb(tx, r.db).WithContext(ctx).Where("invoice_id = ?", invoiceID).Delete(&domain.InvoiceItem{}).Error
+func (r *invoiceRepository) UpdateStatus(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("id = ? AND author_id = ?", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"status":     data.Status,
+			"version":    data.Version,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
+
+// MarkOverdue moves sent and partially paid invoices due before the day of now to overdue and returns how many moved.
+// Due dates are compared by day as in GetDue, an invoice due today is not overdue yet.
+func (r *invoiceRepository) MarkOverdue(ctx context.Context, now time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("status IN ? AND due_date::date < ? AND deleted_at IS NULL",
+			[]domain.InvoiceStatus{domain.InvoiceStatusSent, domain.InvoiceStatusPartiallyPaid}, now.Format(time.DateOnly)).
+		Updates(map[string]interface{}{
+			"status":     domain.InvoiceStatusOverdue,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": now,
+		})
+	return res.RowsAffected, res.Error
+}
+
+// AssignNumber stores the number an invoice got when it was issued
+func (r *invoiceRepository) AssignNumber(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("id = ? AND author_id = ? AND number = ''", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"number":     data.Number,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
+
+// NumberExists reports whether an author already used a number, deleted invoices included
+func (r *invoiceRepository) NumberExists(ctx context.Context, tx portRepository.Transaction, authorID uint, number string) (bool, error) {
+	var count int64
+	err := txDb(tx, r.db).WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("author_id = ? AND number = ?", authorID, number).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateSettlement stores the amounts paid and credited and the status derived from them
+func (r *invoiceRepository) UpdateSettlement(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("id = ? AND author_id = ?", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"amount_paid":     data.AmountPaid,
+			"amount_credited": data.AmountCredited,
+			"status":          data.Status,
+			"version":         data.Version,
+			"updated_at":      data.UpdatedAt,
+		}).
+		Error
+}
+
+func (r *invoiceRepository) DeleteItemsByInvoiceID(ctx context.Context, tx portRepository.Transaction, authorID uint, invoiceID int64) error {
+	return txDb(tx, r.db).WithContext(ctx).Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).Delete(&domain.InvoiceItem{}).Error
+}
+
+func (r *invoiceRepository) DeleteTaxesByInvoiceID(ctx context.Context, tx portRepository.Transaction, authorID uint, invoiceID int64) error {
+	return txDb(tx, r.db).WithContext(ctx).Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).Delete(&domain.InvoiceTax{}).Error
+}
+
+// UpdateCustomer stores a fresh snapshot of the linked customer
+func (r *invoiceRepository) UpdateCustomer(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("id = ? AND author_id = ?", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"customer_id":      data.CustomerID,
+			"customer":         data.Customer,
+			"customer_company": data.CustomerCompany,
+			"customer_email":   data.CustomerEmail,
+			"customer_phone":   data.CustomerPhone,
+			"customer_address": data.CustomerAddress,
+			"customer_tax_id":  data.CustomerTaxID,
+		}).
+		Error
+}
+
+// GetUnlinkedCustomers lists the free text customer spellings of invoices not linked to a customer
+func (r *invoiceRepository) GetUnlinkedCustomers(ctx context.Context) ([]domain.UnlinkedCustomer, error) {
+	var data []domain.UnlinkedCustomer
+	err := r.db.WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Select("author_id, customer, COUNT(*) AS total").
+		Where("customer_id IS NULL AND customer <> ''").
+		Group("author_id, customer").
+		Order("author_id ASC, total DESC").
+		Scan(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// LinkCustomer links the unlinked invoices of an author spelled as one of names to a customer
+func (r *invoiceRepository) LinkCustomer(ctx context.Context, tx portRepository.Transaction, authorID uint, names []string, customerID uint) (int64, error) {
+	res := txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("author_id = ? AND customer_id IS NULL AND customer IN ?", authorID, names).
+		Update("customer_id", customerID)
+	return res.RowsAffected, res.Error
+}
+
+// SoftDelete hides an invoice from every read until it is restored or purged
+func (r *invoiceRepository) SoftDelete(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"deleted_at": data.DeletedAt,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
+
+func (r *invoiceRepository) Restore(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.Invoice{}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NOT NULL", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
+
+// Purge permanently removes a soft deleted invoice with its items, taxes, payments, revisions and attachments, the attachment files are left to the caller
+func (r *invoiceRepository) Purge(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	db := txDb(tx, r.db).WithContext(ctx)
+
+	if err := db.Where("invoice_id = ? AND author_id = ?", data.ID, data.AuthorID).Delete(&domain.InvoiceItem{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("invoice_id = ? AND author_id = ?", data.ID, data.AuthorID).Delete(&domain.InvoiceTax{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("invoice_id = ? AND author_id = ?", data.ID, data.AuthorID).Delete(&domain.Payment{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("invoice_id = ? AND author_id = ?", data.ID, data.AuthorID).Delete(&domain.InvoiceRevision{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("invoice_id = ? AND author_id = ?", data.ID, data.AuthorID).Delete(&domain.InvoiceAttachment{}).Error; err != nil {
+		return err
+	}
+
+	return db.
+		Where("id = ? AND author_id = ? AND deleted_at IS NOT NULL", data.ID, data.AuthorID).
+		Delete(&domain.Invoice{}).
+		Error
 }
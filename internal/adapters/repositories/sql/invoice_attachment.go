@@ -0,0 +1,65 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+)
+
+type invoiceAttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceAttachmentRepository(db *gorm.DB) portRepository.InvoiceAttachmentRepository {
+	return &invoiceAttachmentRepository{db: db}
+}
+
+func (r *invoiceAttachmentRepository) GetByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceAttachment, error) {
+	var attachments []domain.InvoiceAttachment
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).
+		Order("id ASC").
+		Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *invoiceAttachmentRepository) GetByID(ctx context.Context, authorID uint, invoiceID int64, id uint) (*domain.InvoiceAttachment, error) {
+	var data domain.InvoiceAttachment
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND author_id = ? AND invoice_id = ?", id, authorID, invoiceID).
+		First(&data).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundAttachment)
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *invoiceAttachmentRepository) Count(ctx context.Context, tx portRepository.Transaction, authorID uint, invoiceID int64) (int64, error) {
+	var count int64
+	err := txDb(tx, r.db).WithContext(ctx).
+		Model(&domain.InvoiceAttachment{}).
+		Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *invoiceAttachmentRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.InvoiceAttachment) error {
+	return txDb(tx, r.db).WithContext(ctx).Create(data).Error
+}
+
+func (r *invoiceAttachmentRepository) Delete(ctx context.Context, data *domain.InvoiceAttachment) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND author_id = ?", data.ID, data.AuthorID).
+		Delete(&domain.InvoiceAttachment{}).
+		Error
+}
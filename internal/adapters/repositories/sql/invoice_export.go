@@ -0,0 +1,100 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+)
+
+type invoiceExportRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceExportRepository(db *gorm.DB) portRepository.InvoiceExportRepository {
+	return &invoiceExportRepository{db: db}
+}
+
+func (r *invoiceExportRepository) GetByID(ctx context.Context, authorID, id uint) (*domain.InvoiceExport, error) {
+	var export domain.InvoiceExport
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", id, authorID).
+		First(&export).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundExport)
+		}
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *invoiceExportRepository) Create(ctx context.Context, data *domain.InvoiceExport) error {
+	return r.db.WithContext(ctx).Create(data).Error
+}
+
+// Claim skips rows locked by other replicas, so every export is run by one process
+func (r *invoiceExportRepository) Claim(ctx context.Context, now, staleBefore time.Time) (*domain.InvoiceExport, error) {
+	var exports []domain.InvoiceExport
+	err := r.db.WithContext(ctx).Raw(
+		`UPDATE app.invoice_exports SET status = ?, started_at = ?, updated_at = ?
+		 WHERE id = (
+			SELECT id FROM app.invoice_exports
+			WHERE deleted_at IS NULL AND (status = ? OR (status = ? AND started_at < ?))
+			ORDER BY id ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING *`,
+		domain.ExportStatusRunning, now, now,
+		domain.ExportStatusPending, domain.ExportStatusRunning, staleBefore,
+	).Scan(&exports).Error
+	if err != nil || len(exports) == 0 {
+		return nil, err
+	}
+	return &exports[0], nil
+}
+
+func (r *invoiceExportRepository) Finish(ctx context.Context, data *domain.InvoiceExport) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.InvoiceExport{}).
+		Where("id = ?", data.ID).
+		Updates(map[string]interface{}{
+			"status":      data.Status,
+			"row_count":   data.RowCount,
+			"file_path":   data.FilePath,
+			"error":       data.Error,
+			"finished_at": data.FinishedAt,
+			"expires_at":  data.ExpiresAt,
+			"updated_at":  data.UpdatedAt,
+		}).
+		Error
+}
+
+func (r *invoiceExportRepository) GetExpired(ctx context.Context, now time.Time, limit int) ([]domain.InvoiceExport, error) {
+	var exports []domain.InvoiceExport
+	err := r.db.WithContext(ctx).
+		Where("file_path <> '' AND expires_at < ?", now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *invoiceExportRepository) ClearFile(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.InvoiceExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"file_path":  "",
+			"updated_at": time.Now(),
+		}).
+		Error
+}
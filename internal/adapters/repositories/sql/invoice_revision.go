@@ -0,0 +1,77 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+)
+
+type invoiceRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRevisionRepository(db *gorm.DB) portRepository.InvoiceRevisionRepository {
+	return &invoiceRevisionRepository{db: db}
+}
+
+func (r *invoiceRevisionRepository) GetByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceRevision, error) {
+	var revisions []domain.InvoiceRevision
+	err := r.db.WithContext(ctx).
+		Omit("snapshot").
+		Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).
+		Order("revision DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *invoiceRevisionRepository) GetByRevision(ctx context.Context, authorID uint, invoiceID int64, revision int) (*domain.InvoiceRevision, error) {
+	var data domain.InvoiceRevision
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND invoice_id = ? AND revision = ?", authorID, invoiceID, revision).
+		First(&data).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundRevision)
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *invoiceRevisionRepository) GetLatest(ctx context.Context, authorID uint, invoiceID int64) (*domain.InvoiceRevision, error) {
+	var data domain.InvoiceRevision
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).
+		Order("revision DESC").
+		First(&data).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundRevision)
+		}
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *invoiceRevisionRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.InvoiceRevision) error {
+	db := txDb(tx, r.db).WithContext(ctx)
+
+	var last int
+	err := db.Model(&domain.InvoiceRevision{}).
+		Select("COALESCE(MAX(revision), 0)").
+		Where("author_id = ? AND invoice_id = ?", data.AuthorID, data.InvoiceID).
+		Scan(&last).Error
+	if err != nil {
+		return err
+	}
+
+	data.Revision = last + 1
+	return db.Create(data).Error
+}
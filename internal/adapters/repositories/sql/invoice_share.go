@@ -0,0 +1,89 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+)
+
+type invoiceShareRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceShareRepository(db *gorm.DB) portRepository.InvoiceShareRepository {
+	return &invoiceShareRepository{db: db}
+}
+
+func (r *invoiceShareRepository) GetByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceShare, error) {
+	var shares []domain.InvoiceShare
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).
+		Order("created_at DESC, id DESC").
+		Find(&shares).Error
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// GetByID retrieves a share of an author's invoice
+func (r *invoiceShareRepository) GetByID(ctx context.Context, authorID uint, invoiceID int64, id uint) (*domain.InvoiceShare, error) {
+	var share domain.InvoiceShare
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND author_id = ? AND invoice_id = ?", id, authorID, invoiceID).
+		First(&share).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundShareLink)
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+// GetByTokenID retrieves a share by the ID carried in its token, it is not scoped to an author
+// and only serves the public views whose token was verified
+func (r *invoiceShareRepository) GetByTokenID(ctx context.Context, id uint) (*domain.InvoiceShare, error) {
+	var share domain.InvoiceShare
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&share).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundShareLink)
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (r *invoiceShareRepository) Create(ctx context.Context, data *domain.InvoiceShare) error {
+	return r.db.WithContext(ctx).Create(data).Error
+}
+
+func (r *invoiceShareRepository) Revoke(ctx context.Context, data *domain.InvoiceShare) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.InvoiceShare{}).
+		Where("id = ? AND author_id = ? AND revoked_at IS NULL", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"revoked_at": data.RevokedAt,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
+
+// RecordView counts a view of a share, the first view time is only set once
+func (r *invoiceShareRepository) RecordView(ctx context.Context, id uint, viewedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.InvoiceShare{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"first_viewed_at": gorm.Expr("COALESCE(first_viewed_at, ?)", viewedAt),
+			"last_viewed_at":  viewedAt,
+			"view_count":      gorm.Expr("view_count + 1"),
+		}).
+		Error
+}
@@ -0,0 +1,127 @@
+package repositoriesSql
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+)
+
+const (
+	publicIDFirstAuthor  = "0f5b8a1e-3c4d-4e6f-8a9b-1c2d3e4f5a6b"
+	publicIDSecondAuthor = "7a1b2c3d-4e5f-4a6b-9c8d-7e6f5a4b3c2d"
+	publicIDDeleted      = "c3d4e5f6-a7b8-4c9d-8e0f-1a2b3c4d5e6f"
+)
+
+// newScopeTestRepository serves invoice 1 of author 1 and invoice 1 of author 2 with one item and tax each,
+// author 1 also has deleted invoice 2
+func newScopeTestRepository(t *testing.T) portRepository.InvoiceRepository {
+	deletedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	return NewInvoiceRepository(newFakeDB(t, map[string]*fakeTable{
+		"invoices": {
+			columns: []string{"id", "public_id", "author_id", "status", "deleted_at"},
+			rows: [][]driver.Value{
+				{int64(1), publicIDFirstAuthor, int64(1), "sent", nil},
+				{int64(1), publicIDSecondAuthor, int64(2), "draft", nil},
+				{int64(2), publicIDDeleted, int64(1), "void", deletedAt},
+			},
+		},
+		"invoice_items": {
+			columns: []string{"id", "invoice_id", "author_id", "description"},
+			rows: [][]driver.Value{
+				{int64(1), int64(1), int64(1), "Design"},
+				{int64(1), int64(1), int64(2), "Hosting"},
+			},
+		},
+		"invoice_taxes": {
+			columns: []string{"id", "invoice_id", "author_id", "name"},
+			rows: [][]driver.Value{
+				{int64(1), int64(1), int64(1), "PPN"},
+				{int64(1), int64(1), int64(2), "VAT"},
+			},
+		},
+	}))
+}
+
+func TestInvoicePublicIDScopedToAuthor(t *testing.T) {
+	repo := newScopeTestRepository(t)
+	ctx := context.Background()
+
+	lookups := map[string]func(authorID uint, publicID string) (*domain.Invoice, error){
+		"get": func(authorID uint, publicID string) (*domain.Invoice, error) {
+			return repo.GetByPublicID(ctx, authorID, publicID)
+		},
+		"lock": func(authorID uint, publicID string) (*domain.Invoice, error) {
+			return repo.LockByPublicID(ctx, nil, authorID, publicID)
+		},
+	}
+	for name, lookup := range lookups {
+		inv, err := lookup(1, publicIDFirstAuthor)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if inv.ID != 1 || inv.AuthorID != 1 || inv.Status != domain.InvoiceStatusSent {
+			t.Errorf("%s: expected invoice 1 of author 1, got %+v", name, inv)
+		}
+
+		for _, v := range []struct {
+			authorID uint
+			publicID string
+		}{
+			{2, publicIDFirstAuthor},
+			{1, publicIDSecondAuthor},
+			{1, publicIDDeleted},
+		} {
+			if _, err = lookup(v.authorID, v.publicID); err == nil || err.Error() != domain.ErrNotFoundInvoice {
+				t.Errorf("%s: expected %q for %s of author %d, got %v", name, domain.ErrNotFoundInvoice, v.publicID, v.authorID, err)
+			}
+		}
+	}
+
+	inv, err := repo.LockDeletedByPublicID(ctx, nil, 1, publicIDDeleted)
+	if err != nil || inv.ID != 2 {
+		t.Errorf("expected deleted invoice 2, got %+v, %v", inv, err)
+	}
+	if _, err = repo.LockDeletedByPublicID(ctx, nil, 2, publicIDDeleted); err == nil || err.Error() != domain.ErrNotFoundInvoice {
+		t.Errorf("expected %q for a deleted invoice of another author, got %v", domain.ErrNotFoundInvoice, err)
+	}
+}
+
+func TestInvoiceItemsScopedToAuthor(t *testing.T) {
+	repo := newScopeTestRepository(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		authorID    uint
+		description string
+		tax         string
+	}{
+		{1, "Design", "PPN"},
+		{2, "Hosting", "VAT"},
+	}
+	for _, c := range cases {
+		items, err := repo.GetItemsByInvoiceID(ctx, c.authorID, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(items) != 1 || items[0].Description != c.description || items[0].AuthorID != c.authorID {
+			t.Errorf("expected only %q for invoice 1 of author %d, got %+v", c.description, c.authorID, items)
+		}
+
+		taxes, err := repo.GetTaxesByInvoiceID(ctx, c.authorID, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(taxes) != 1 || taxes[0].Name != c.tax || taxes[0].AuthorID != c.authorID {
+			t.Errorf("expected only %q for invoice 1 of author %d, got %+v", c.tax, c.authorID, taxes)
+		}
+	}
+
+	items, err := repo.GetItemsByInvoiceID(ctx, 3, 1)
+	if err != nil || len(items) != 0 {
+		t.Errorf("expected no items for an author without invoice 1, got %+v, %v", items, err)
+	}
+}
@@ -0,0 +1,77 @@
+package repositoriesSql
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type numberingRepository struct {
+	db *gorm.DB
+}
+
+func NewNumberingRepository(db *gorm.DB) portRepository.NumberingRepository {
+	return &numberingRepository{db: db}
+}
+
+// GetScheme returns the numbering scheme an author saved for a document type, or nil when none is saved
+func (r *numberingRepository) GetScheme(ctx context.Context, authorID uint, document domain.DocumentType) (*domain.NumberingScheme, error) {
+	var scheme domain.NumberingScheme
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND document = ?", authorID, document).
+		First(&scheme).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &scheme, nil
+}
+
+// SaveScheme creates or replaces the numbering scheme of a document type
+func (r *numberingRepository) SaveScheme(ctx context.Context, data *domain.NumberingScheme) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "author_id"}, {Name: "document"}},
+			DoUpdates: clause.AssignmentColumns([]string{"template", "reset", "updated_at"}),
+		}).
+		Create(data).Error
+}
+
+// NextSequence increments the counter of a period and returns it.
+// The counter row stays locked until the transaction ends, a rollback gives the number back
+// so issued numbers have no gaps.
+func (r *numberingRepository) NextSequence(ctx context.Context, tx portRepository.Transaction, authorID uint, document domain.DocumentType, period string) (int64, error) {
+	var counter int64
+	err := txDb(tx, r.db).WithContext(ctx).Raw(
+		`INSERT INTO app.document_sequences (author_id, document, period, counter)
+		 VALUES (?, ?, ?, 1)
+		 ON CONFLICT (author_id, document, period)
+		 DO UPDATE SET counter = app.document_sequences.counter + 1, updated_at = CURRENT_TIMESTAMP
+		 RETURNING counter`,
+		authorID, document, period,
+	).Scan(&counter).Error
+	if err != nil {
+		return 0, err
+	}
+	return counter, nil
+}
+
+// CurrentSequence returns the last counter handed out in a period, zero when none was
+func (r *numberingRepository) CurrentSequence(ctx context.Context, authorID uint, document domain.DocumentType, period string) (int64, error) {
+	var counter int64
+	err := r.db.WithContext(ctx).
+		Table("app.document_sequences").
+		Where("author_id = ? AND document = ? AND period = ?", authorID, document, period).
+		Select("counter").
+		Scan(&counter).Error
+	if err != nil {
+		return 0, err
+	}
+	return counter, nil
+}
@@ -0,0 +1,186 @@
+package repositoriesSql
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+
+	"gorm.io/gorm"
+)
+
+// listKeyKind tells how the cursor value of a sort key is read back
+type listKeyKind int
+
+const (
+	listKeyText listKeyKind = iota
+	listKeyInt
+	listKeyTime
+)
+
+// listKey is a column or expression a list is sorted by, it must not be NULL
+type listKey struct {
+	Expr string
+	Kind listKeyKind
+}
+
+// listOrder sorts a list by its keys, all in one direction. The last key must be unique,
+// so a cursor points at exactly one row. Name ties cursors to the order they were made for.
+type listOrder struct {
+	Name string
+	Keys []listKey
+	Desc bool
+}
+
+func (o listOrder) clause(reverse bool) string {
+	dir := "ASC"
+	if o.Desc != reverse {
+		dir = "DESC"
+	}
+	exprs := make([]string, len(o.Keys))
+	for i, k := range o.Keys {
+		exprs[i] = k.Expr + " " + dir
+	}
+	return strings.Join(exprs, ", ")
+}
+
+// seek returns the condition that skips the rows up to and including the row of a cursor
+func (o listOrder) seek(c *domain.Cursor) (string, []any, error) {
+	if c.Order != o.Name || len(c.Values) != len(o.Keys) {
+		return "", nil, fmt.Errorf(domain.ErrInvalidCursor)
+	}
+
+	exprs := make([]string, len(o.Keys))
+	args := make([]any, len(o.Keys))
+	for i, k := range o.Keys {
+		exprs[i] = k.Expr
+		switch k.Kind {
+		case listKeyInt:
+			v, err := strconv.ParseInt(c.Values[i], 10, 64)
+			if err != nil {
+				return "", nil, fmt.Errorf(domain.ErrInvalidCursor)
+			}
+			args[i] = v
+		case listKeyTime:
+			v, err := time.Parse(time.RFC3339Nano, c.Values[i])
+			if err != nil {
+				return "", nil, fmt.Errorf(domain.ErrInvalidCursor)
+			}
+			args[i] = v
+		default:
+			args[i] = c.Values[i]
+		}
+	}
+
+	op := ">"
+	if o.Desc != c.Before {
+		op = "<"
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(exprs, ", "), op, placeholders), args, nil
+}
+
+// cursor returns the cursor of a row from the values of its sort keys
+func (o listOrder) cursor(values []any, before bool) string {
+	c := domain.Cursor{Order: o.Name, Values: make([]string, len(values)), Before: before}
+	for i, v := range values {
+		if t, ok := v.(time.Time); ok {
+			c.Values[i] = t.Format(time.RFC3339Nano)
+		} else {
+			c.Values[i] = fmt.Sprint(v)
+		}
+	}
+	return c.Encode()
+}
+
+// paginate runs a list query sorted by order. Page mode counts the matching rows and skips to
+// the offset of the page; cursor mode seeks past the row of the cursor, which stays fast on
+// large tables, and only counts when asked to. keys returns the sort key values of a row.
+func paginate[T any](query *gorm.DB, req *domain.PaginationRequest, order listOrder, keys func(*T) []any) ([]T, domain.PaginationMetaResponse, error) {
+	meta := domain.PaginationMetaResponse{Limit: req.Limit}
+	cursorMode := req.CursorMode()
+
+	if !cursorMode || req.WithTotal {
+		var total uint64
+		err := query.Session(&gorm.Session{NewDB: true}).
+			Raw("SELECT COUNT(*) FROM (?) AS list", query.Session(&gorm.Session{})).
+			Scan(&total).Error
+		if err != nil {
+			return nil, meta, err
+		}
+		totalPage := GetTotalPage(total, req.Limit)
+		meta.TotalData, meta.TotalPage = &total, &totalPage
+	}
+
+	page := query.Session(&gorm.Session{})
+	if !cursorMode {
+		meta.Page = req.Page
+		page = page.Order(order.clause(false))
+		if req.Limit > 0 {
+			page = page.Limit(int(req.Limit))
+		}
+		if req.Offset > 0 {
+			page = page.Offset(int(req.Offset))
+		}
+
+		var data []T
+		err := page.Scan(&data).Error
+		return data, meta, err
+	}
+
+	var from *domain.Cursor
+	if req.Cursor != "" {
+		c, err := domain.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, meta, err
+		}
+		cond, args, err := order.seek(c)
+		if err != nil {
+			return nil, meta, err
+		}
+		page = page.Where(cond, args...)
+		from = c
+	}
+
+	before := from != nil && from.Before
+	page = page.Order(order.clause(before))
+	// One extra row tells whether there is another page
+	if req.Limit > 0 {
+		page = page.Limit(int(req.Limit) + 1)
+	}
+
+	var data []T
+	if err := page.Scan(&data).Error; err != nil {
+		return nil, meta, err
+	}
+
+	more := req.Limit > 0 && len(data) > int(req.Limit)
+	if more {
+		data = data[:req.Limit]
+	}
+	if before {
+		slices.Reverse(data)
+	}
+	if len(data) == 0 {
+		return data, meta, nil
+	}
+
+	first, last := keys(&data[0]), keys(&data[len(data)-1])
+	if before {
+		if more {
+			meta.PrevCursor = order.cursor(first, true)
+		}
+		meta.NextCursor = order.cursor(last, false)
+	} else {
+		if more {
+			meta.NextCursor = order.cursor(last, false)
+		}
+		if from != nil {
+			meta.PrevCursor = order.cursor(first, true)
+		}
+	}
+	return data, meta, nil
+}
@@ -0,0 +1,80 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) portRepository.PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+// GetByInvoiceID retrieves every payment of an invoice, including reversed ones
+func (r *paymentRepository) GetByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.Payment, error) {
+	var payments []domain.Payment
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).
+		Order("paid_at ASC, id ASC").
+		Find(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// LockByID retrieves a payment and locks its row until the transaction ends
+func (r *paymentRepository) LockByID(ctx context.Context, tx portRepository.Transaction, authorID uint, id int64) (*domain.Payment, error) {
+	var payment domain.Payment
+	err := txDb(tx, r.db).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND author_id = ?", id, authorID).
+		First(&payment).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundPayment)
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// SumByInvoiceID returns the total of all non-reversed payments of an invoice
+func (r *paymentRepository) SumByInvoiceID(ctx context.Context, tx portRepository.Transaction, authorID uint, invoiceID int64) (int, error) {
+	var total int
+	err := txDb(tx, r.db).WithContext(ctx).
+		Model(&domain.Payment{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("author_id = ? AND invoice_id = ? AND reversed_at IS NULL", authorID, invoiceID).
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *paymentRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.Payment) error {
+	return txDb(tx, r.db).WithContext(ctx).Create(data).Error
+}
+
+func (r *paymentRepository) Reverse(ctx context.Context, tx portRepository.Transaction, data *domain.Payment) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.Payment{}).
+		Where("id = ? AND reversed_at IS NULL", data.ID).
+		Updates(map[string]interface{}{
+			"reversed_at":     data.ReversedAt,
+			"reversal_reason": data.ReversalReason,
+			"updated_at":      data.UpdatedAt,
+		}).
+		Error
+}
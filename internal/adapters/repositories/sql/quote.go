@@ -0,0 +1,187 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type quoteRepository struct {
+	db *gorm.DB
+}
+
+func NewQuoteRepository(db *gorm.DB) portRepository.QuoteRepository {
+	return &quoteRepository{db: db}
+}
+
+func (r *quoteRepository) Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Quote{}).
+		Where("author_id = ? AND deleted_at IS NULL", req.UserID)
+
+	if req.Search != "" {
+		search := "%" + req.Search + "%"
+		query = query.Where("number ILIKE ? OR customer ILIKE ?", search, search)
+	}
+
+	order := listOrder{Name: "created_at", Keys: []listKey{{"created_at", listKeyTime}, {"id", listKeyInt}}, Desc: true}
+	data, meta, err := paginate(query, req, order, func(v *domain.Quote) []any { return []any{v.CreatedAt, v.ID} })
+	if err != nil {
+		return nil, err
+	}
+
+	resp := domain.PaginationResponse{Meta: meta}
+	today := domain.CalendarDate(time.Now())
+	resp.Data = make([]any, len(data))
+	for i, v := range data {
+		resp.Data[i] = v.Response(nil, today)
+	}
+
+	return &resp, nil
+}
+
+func (r *quoteRepository) GetByID(ctx context.Context, authorID, id uint) (*domain.Quote, error) {
+	var quote domain.Quote
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", id, authorID).
+		First(&quote).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundQuote)
+		}
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// LockByID retrieves a quote and locks its row until the transaction ends
+func (r *quoteRepository) LockByID(ctx context.Context, tx portRepository.Transaction, authorID, id uint) (*domain.Quote, error) {
+	var quote domain.Quote
+	err := txDb(tx, r.db).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", id, authorID).
+		First(&quote).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundQuote)
+		}
+		return nil, err
+	}
+	return &quote, nil
+}
+
+func (r *quoteRepository) GetItems(ctx context.Context, quoteID uint) ([]domain.QuoteItem, error) {
+	var items []domain.QuoteItem
+	err := r.db.WithContext(ctx).
+		Where("quote_id = ?", quoteID).
+		Order("id ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// NumberExists reports whether an author already used a quote number, deleted quotes included
+func (r *quoteRepository) NumberExists(ctx context.Context, tx portRepository.Transaction, authorID uint, number string) (bool, error) {
+	var count int64
+	err := txDb(tx, r.db).WithContext(ctx).
+		Model(&domain.Quote{}).
+		Where("author_id = ? AND number = ?", authorID, number).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *quoteRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.Quote) error {
+	return txDb(tx, r.db).WithContext(ctx).Create(data).Error
+}
+
+func (r *quoteRepository) CreateItems(ctx context.Context, tx portRepository.Transaction, data []domain.QuoteItem) error {
+	return txDb(tx, r.db).WithContext(ctx).CreateInBatches(data, 100).Error
+}
+
+func (r *quoteRepository) Update(ctx context.Context, tx portRepository.Transaction, data *domain.Quote) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.Quote{}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"profile_id":       data.ProfileID,
+			"issuer":           data.Issuer,
+			"customer_id":      data.CustomerID,
+			"customer":         data.Customer,
+			"customer_company": data.CustomerCompany,
+			"customer_email":   data.CustomerEmail,
+			"customer_phone":   data.CustomerPhone,
+			"customer_address": data.CustomerAddress,
+			"customer_tax_id":  data.CustomerTaxID,
+			"issue_date":       data.IssueDate,
+			"valid_until":      data.ValidUntil,
+			"note":             data.Note,
+			"currency":         data.Currency,
+			"tax_mode":         data.TaxMode,
+			"tax_rate_id":      data.TaxRateID,
+			"discount_type":    data.DiscountType,
+			"discount":         data.Discount,
+			"subtotal":         data.Subtotal,
+			"discount_amount":  data.DiscountAmount,
+			"discount_total":   data.DiscountTotal,
+			"tax_total":        data.TaxTotal,
+			"total":            data.Total,
+			"updated_at":       data.UpdatedAt,
+		}).
+		Error
+}
+
+func (r *quoteRepository) UpdateStatus(ctx context.Context, tx portRepository.Transaction, data *domain.Quote) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.Quote{}).
+		Where("id = ? AND author_id = ?", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"status":     data.Status,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
+
+// MarkConverted links quote lines to the invoice they were converted into
+func (r *quoteRepository) MarkConverted(ctx context.Context, tx portRepository.Transaction, quoteID uint, itemIDs []uint, invoiceID string) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.QuoteItem{}).
+		Where("quote_id = ? AND id IN ? AND invoice_id = ''", quoteID, itemIDs).
+		Updates(map[string]interface{}{
+			"invoice_id": invoiceID,
+			"updated_at": time.Now(),
+		}).
+		Error
+}
+
+func (r *quoteRepository) DeleteItems(ctx context.Context, tx portRepository.Transaction, quoteID uint) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Where("quote_id = ?", quoteID).
+		Delete(&domain.QuoteItem{}).
+		Error
+}
+
+func (r *quoteRepository) SoftDelete(ctx context.Context, tx portRepository.Transaction, data *domain.Quote) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.Quote{}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"deleted_at": data.DeletedAt,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
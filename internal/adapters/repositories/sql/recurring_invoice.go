@@ -0,0 +1,194 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type recurringInvoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewRecurringInvoiceRepository(db *gorm.DB) portRepository.RecurringInvoiceRepository {
+	return &recurringInvoiceRepository{db: db}
+}
+
+func (r *recurringInvoiceRepository) Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
+	query := r.db.WithContext(ctx).Model(&domain.RecurringInvoice{}).
+		Where("author_id = ? AND deleted_at IS NULL", req.UserID)
+
+	if req.Search != "" {
+		search := "%" + req.Search + "%"
+		query = query.Where("name ILIKE ? OR customer ILIKE ?", search, search)
+	}
+
+	order := listOrder{Name: "name", Keys: []listKey{{"name", listKeyText}, {"id", listKeyInt}}}
+	data, meta, err := paginate(query, req, order, func(v *domain.RecurringInvoice) []any { return []any{v.Name, v.ID} })
+	if err != nil {
+		return nil, err
+	}
+
+	resp := domain.PaginationResponse{Meta: meta}
+	resp.Data = make([]any, len(data))
+	for i, v := range data {
+		resp.Data[i] = v.Response(nil)
+	}
+
+	return &resp, nil
+}
+
+func (r *recurringInvoiceRepository) GetByID(ctx context.Context, authorID, id uint) (*domain.RecurringInvoice, error) {
+	var recurring domain.RecurringInvoice
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", id, authorID).
+		First(&recurring).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundRecurringInvoice)
+		}
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+// LockByID retrieves a recurring invoice of an author and locks its row until the transaction ends.
+// It waits for a scheduler run holding the row, so an edit never overwrites the progress of a run.
+func (r *recurringInvoiceRepository) LockByID(ctx context.Context, tx portRepository.Transaction, authorID, id uint) (*domain.RecurringInvoice, error) {
+	var recurring domain.RecurringInvoice
+	err := txDb(tx, r.db).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", id, authorID).
+		First(&recurring).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundRecurringInvoice)
+		}
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+func (r *recurringInvoiceRepository) GetItems(ctx context.Context, recurringID uint) ([]domain.RecurringInvoiceItem, error) {
+	var items []domain.RecurringInvoiceItem
+	err := r.db.WithContext(ctx).
+		Where("recurring_id = ?", recurringID).
+		Order("id ASC").
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetDueIDs lists active schedules with a run on or before date, oldest run first
+func (r *recurringInvoiceRepository) GetDueIDs(ctx context.Context, date string, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&domain.RecurringInvoice{}).
+		Where("status = ? AND next_run_date <> '' AND next_run_date <= ? AND deleted_at IS NULL", domain.RecurringStatusActive, date).
+		Order("next_run_date ASC, id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// LockDue locks a schedule that is still due until the transaction ends.
+// It returns nil when the schedule is no longer due or another worker holds it.
+func (r *recurringInvoiceRepository) LockDue(ctx context.Context, tx portRepository.Transaction, id uint, date string) (*domain.RecurringInvoice, error) {
+	var recurring domain.RecurringInvoice
+	err := txDb(tx, r.db).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("id = ? AND status = ? AND next_run_date <> '' AND next_run_date <= ? AND deleted_at IS NULL", id, domain.RecurringStatusActive, date).
+		First(&recurring).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+func (r *recurringInvoiceRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.RecurringInvoice) error {
+	return txDb(tx, r.db).WithContext(ctx).Create(data).Error
+}
+
+func (r *recurringInvoiceRepository) CreateItems(ctx context.Context, tx portRepository.Transaction, data []domain.RecurringInvoiceItem) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return txDb(tx, r.db).WithContext(ctx).CreateInBatches(data, 100).Error
+}
+
+func (r *recurringInvoiceRepository) Update(ctx context.Context, tx portRepository.Transaction, data *domain.RecurringInvoice) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.RecurringInvoice{}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"name":          data.Name,
+			"profile_id":    data.ProfileID,
+			"issuer":        data.Issuer,
+			"customer_id":   data.CustomerID,
+			"customer":      data.Customer,
+			"note":          data.Note,
+			"currency":      data.Currency,
+			"tax_mode":      data.TaxMode,
+			"tax_rate_id":   data.TaxRateID,
+			"discount_type": data.DiscountType,
+			"discount":      data.Discount,
+			"interval":      data.Interval,
+			"day_of_month":  data.DayOfMonth,
+			"start_date":    data.StartDate,
+			"end_date":      data.EndDate,
+			"due_days":      data.DueDays,
+			"auto_send":     data.AutoSend,
+			"status":        data.Status,
+			"next_run_date": data.NextRunDate,
+			"updated_at":    data.UpdatedAt,
+		}).
+		Error
+}
+
+// UpdateSchedule stores the status and run dates of a schedule
+func (r *recurringInvoiceRepository) UpdateSchedule(ctx context.Context, tx portRepository.Transaction, data *domain.RecurringInvoice) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Model(&domain.RecurringInvoice{}).
+		Where("id = ? AND author_id = ?", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"status":        data.Status,
+			"next_run_date": data.NextRunDate,
+			"last_run_date": data.LastRunDate,
+			"updated_at":    data.UpdatedAt,
+		}).
+		Error
+}
+
+func (r *recurringInvoiceRepository) DeleteItems(ctx context.Context, tx portRepository.Transaction, recurringID uint) error {
+	return txDb(tx, r.db).
+		WithContext(ctx).
+		Where("recurring_id = ?", recurringID).
+		Delete(&domain.RecurringInvoiceItem{}).
+		Error
+}
+
+func (r *recurringInvoiceRepository) SoftDelete(ctx context.Context, data *domain.RecurringInvoice) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.RecurringInvoice{}).
+		Where("id = ? AND author_id = ? AND deleted_at IS NULL", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"deleted_at": data.DeletedAt,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
@@ -0,0 +1,72 @@
+package repositoriesSql
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) portRepository.ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+func (r *reminderRepository) GetDue(ctx context.Context, offset int, from, to time.Time, limit int) ([]domain.Invoice, error) {
+	var invoices []domain.Invoice
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", []domain.InvoiceStatus{domain.InvoiceStatusSent, domain.InvoiceStatusPartiallyPaid, domain.InvoiceStatusOverdue}).
+		Where("deleted_at IS NULL AND total > amount_paid + amount_credited").
+		Where("due_date::date BETWEEN ? AND ?", from.Format(time.DateOnly), to.Format(time.DateOnly)).
+		Where("NOT EXISTS (SELECT 1 FROM app.invoice_reminders r WHERE r.author_id = invoices.author_id AND r.invoice_id = invoices.id AND r.offset_days = ?)", offset).
+		Order("due_date ASC, author_id ASC, id ASC").
+		Limit(limit).
+		Find(&invoices).Error
+	if err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (r *reminderRepository) GetByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceReminder, error) {
+	var reminders []domain.InvoiceReminder
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND invoice_id = ?", authorID, invoiceID).
+		Order("created_at ASC, id ASC").
+		Find(&reminders).Error
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// Claim relies on the unique index of invoice, offset and channel, so replicas never claim the same reminder
+func (r *reminderRepository) Claim(ctx context.Context, data *domain.InvoiceReminder) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(data)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *reminderRepository) Finish(ctx context.Context, data *domain.InvoiceReminder) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.InvoiceReminder{}).
+		Where("id = ?", data.ID).
+		Updates(map[string]interface{}{
+			"status":     data.Status,
+			"error":      data.Error,
+			"sent_at":    data.SentAt,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
@@ -0,0 +1,75 @@
+package repositoriesSql
+
+import (
+	"context"
+	"fmt"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+
+	"gorm.io/gorm"
+)
+
+type taxRateRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxRateRepository(db *gorm.DB) portRepository.TaxRateRepository {
+	return &taxRateRepository{db: db}
+}
+
+func (r *taxRateRepository) GetByAuthorID(ctx context.Context, authorID uint) ([]domain.TaxRate, error) {
+	var rates []domain.TaxRate
+	err := r.db.WithContext(ctx).
+		Where("author_id = ?", authorID).
+		Order("is_active DESC, name ASC, rate ASC").
+		Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (r *taxRateRepository) GetByID(ctx context.Context, authorID, id uint) (*domain.TaxRate, error) {
+	var rate domain.TaxRate
+	err := r.db.WithContext(ctx).Where("id = ? AND author_id = ?", id, authorID).First(&rate).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf(domain.ErrNotFoundTaxRate)
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// GetByIDs retrieves the active tax rates of an author among the given IDs
+func (r *taxRateRepository) GetByIDs(ctx context.Context, authorID uint, ids []uint) ([]domain.TaxRate, error) {
+	var rates []domain.TaxRate
+	if len(ids) == 0 {
+		return rates, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("author_id = ? AND id IN ? AND is_active", authorID, ids).
+		Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (r *taxRateRepository) Create(ctx context.Context, data *domain.TaxRate) error {
+	return r.db.WithContext(ctx).Create(data).Error
+}
+
+func (r *taxRateRepository) Update(ctx context.Context, data *domain.TaxRate) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.TaxRate{}).
+		Where("id = ? AND author_id = ?", data.ID, data.AuthorID).
+		Updates(map[string]interface{}{
+			"name":       data.Name,
+			"rate":       data.Rate,
+			"is_active":  data.IsActive,
+			"updated_at": data.UpdatedAt,
+		}).
+		Error
+}
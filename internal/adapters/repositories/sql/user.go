@@ -2,6 +2,7 @@ package repositoriesSql
 
 import (
 	"context"
+	"time"
 
 	"app/xonvera-core/internal/core/domain"
 	"app/xonvera-core/internal/core/ports/repository"
@@ -66,6 +67,30 @@ func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool,
 	return count > 0, nil
 }
 
+func (r *userRepository) UpdateBaseCurrency(ctx context.Context, id uint, currency string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"base_currency": currency,
+			"updated_at":    time.Now(),
+		}).
+		Error
+}
+
+func (r *userRepository) UpdatePDFSettings(ctx context.Context, id uint, template, locale, brandColor string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"pdf_template": template,
+			"pdf_locale":   locale,
+			"brand_color":  brandColor,
+			"updated_at":   time.Now(),
+		}).
+		Error
+}
+
 func (r *userRepository) ExistsByPhone(ctx context.Context, phone string) (bool, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&domain.User{}).Where("phone = ?", phone).Count(&count).Error
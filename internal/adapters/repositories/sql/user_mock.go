@@ -15,6 +15,8 @@ type MockUserRepository struct {
 	FindByIDFunc           func(ctx context.Context, id uint) (*domain.User, error)
 	ExistsByEmailFunc      func(ctx context.Context, email string) (bool, error)
 	ExistsByPhoneFunc      func(ctx context.Context, phone string) (bool, error)
+	UpdateBaseCurrencyFunc func(ctx context.Context, id uint, currency string) error
+	UpdatePDFSettingsFunc  func(ctx context.Context, id uint, template, locale, brandColor string) error
 }
 
 func (m *MockUserRepository) Create(ctx context.Context, user *domain.User) error {
@@ -65,3 +67,17 @@ func (m *MockUserRepository) ExistsByPhone(ctx context.Context, phone string) (b
 	}
 	return false, nil
 }
+
+func (m *MockUserRepository) UpdateBaseCurrency(ctx context.Context, id uint, currency string) error {
+	if m.UpdateBaseCurrencyFunc != nil {
+		return m.UpdateBaseCurrencyFunc(ctx, id, currency)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) UpdatePDFSettings(ctx context.Context, id uint, template, locale, brandColor string) error {
+	if m.UpdatePDFSettingsFunc != nil {
+		return m.UpdatePDFSettingsFunc(ctx, id, template, locale, brandColor)
+	}
+	return nil
+}
@@ -1,9 +1,22 @@
 package repositoriesSql
 
-func GetTotalPage(totalData uint64, limit uint8) uint8 {
+import "strings"
+
+func GetTotalPage(totalData uint64, limit uint32) uint32 {
+	if limit == 0 {
+		return 0
+	}
 	totalPages := totalData / uint64(limit)
 	if totalData%uint64(limit) != 0 {
 		totalPages++
 	}
-	return uint8(totalPages)
+	return uint32(totalPages)
+}
+
+// likeEscaper escapes the ILIKE wildcards so a search matches them literally, used with ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern returns the ILIKE pattern matching values that contain search
+func containsPattern(search string) string {
+	return "%" + likeEscaper.Replace(search) + "%"
 }
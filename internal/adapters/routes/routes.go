@@ -25,8 +25,15 @@ func SetupRoutes(
 	app.Get("/packages", r.PackageHandler.GetPackages)
 	app.Get("/packages/:id", r.PackageHandler.GetPackageByID)
 
-	// Protected routes example
-	appLogged := app.Use("/", r.AuthMiddleware.Authenticate())
+	// Shared invoices (public) opened with a share token
+	public := app.Group("/public", middleware.APIRateLimiter(r.Redis))
+	{
+		public.Get("/invoices/:token", r.InvoiceShareHandler.View)
+		public.Get("/invoices/:token/pdf", r.InvoiceShareHandler.ViewPDF)
+	}
+
+	// Protected routes example, mutating requests with an Idempotency-Key are replayed for their retries
+	appLogged := app.Use("/", r.AuthMiddleware.Authenticate(), middleware.Idempotency(r.Redis, r.Config.Idempotency.TTL))
 
 	// invoice
 	invoice := appLogged.Group("/invoice")
@@ -34,6 +41,129 @@ func SetupRoutes(
 		invoice.Post("", r.InvoiceHandler.Create)
 		invoice.Get("", r.InvoiceHandler.Get)
 		invoice.Put("", r.InvoiceHandler.Update)
+		invoice.Get("/trash", r.InvoiceHandler.GetDeleted)
+		invoice.Get("/export", r.InvoiceExportHandler.Export)
+		invoice.Post("/exports", r.InvoiceExportHandler.Create)
+		invoice.Get("/exports/:exportId", r.InvoiceExportHandler.GetByID)
+		invoice.Get("/exports/:exportId/download", r.InvoiceExportHandler.Download)
+		invoice.Post("/import", r.InvoiceImportHandler.Import)
+		invoice.Get("/:id", r.InvoiceHandler.GetByID)
+		invoice.Delete("/:id", r.InvoiceHandler.Delete)
+		invoice.Post("/:id/restore", r.InvoiceHandler.Restore)
+		invoice.Delete("/:id/purge", r.InvoiceHandler.Purge)
 		invoice.Get("/:id/pdf", r.InvoiceHandler.GetInvoicePDF)
+		invoice.Post("/:id/send", r.InvoiceHandler.Send)
+		invoice.Post("/:id/void", r.InvoiceHandler.Void)
+		invoice.Post("/:id/mark-paid", r.InvoiceHandler.MarkPaid)
+		invoice.Get("/:id/payments", r.PaymentHandler.Get)
+		invoice.Post("/:id/payments", r.PaymentHandler.Create)
+		invoice.Post("/:id/payments/:paymentId/reverse", r.PaymentHandler.Reverse)
+		invoice.Get("/:id/shares", r.InvoiceShareHandler.Get)
+		invoice.Post("/:id/shares", r.InvoiceShareHandler.Create)
+		invoice.Delete("/:id/shares/:shareId", r.InvoiceShareHandler.Revoke)
+		invoice.Get("/:id/reminders", r.ReminderHandler.Get)
+		invoice.Get("/:id/revisions", r.InvoiceRevisionHandler.Get)
+		invoice.Get("/:id/revisions/diff", r.InvoiceRevisionHandler.Diff)
+		invoice.Get("/:id/revisions/:revision", r.InvoiceRevisionHandler.GetByRevision)
+		invoice.Get("/:id/attachments", r.InvoiceAttachmentHandler.Get)
+		invoice.Post("/:id/attachments", r.InvoiceAttachmentHandler.Upload)
+		invoice.Get("/:id/attachments/:attachmentId", r.InvoiceAttachmentHandler.Download)
+		invoice.Delete("/:id/attachments/:attachmentId", r.InvoiceAttachmentHandler.Delete)
+	}
+
+	// credit note
+	creditNote := appLogged.Group("/credit-notes")
+	{
+		creditNote.Get("", r.CreditNoteHandler.Get)
+		creditNote.Post("", r.CreditNoteHandler.Create)
+		creditNote.Get("/:id", r.CreditNoteHandler.GetByID)
+		creditNote.Get("/:id/pdf", r.CreditNoteHandler.GetPDF)
+		creditNote.Post("/:id/apply", r.CreditNoteHandler.Apply)
+		creditNote.Post("/:id/refund", r.CreditNoteHandler.Refund)
+	}
+
+	// quote
+	quote := appLogged.Group("/quotes")
+	{
+		quote.Get("", r.QuoteHandler.Get)
+		quote.Post("", r.QuoteHandler.Create)
+		quote.Get("/:id", r.QuoteHandler.GetByID)
+		quote.Put("/:id", r.QuoteHandler.Update)
+		quote.Delete("/:id", r.QuoteHandler.Delete)
+		quote.Get("/:id/pdf", r.QuoteHandler.GetPDF)
+		quote.Post("/:id/send", r.QuoteHandler.Send)
+		quote.Post("/:id/accept", r.QuoteHandler.Accept)
+		quote.Post("/:id/decline", r.QuoteHandler.Decline)
+		quote.Post("/:id/convert", r.QuoteHandler.Convert)
+	}
+
+	// customer
+	customer := appLogged.Group("/customers")
+	{
+		customer.Get("", r.CustomerHandler.Get)
+		customer.Post("", r.CustomerHandler.Create)
+		customer.Get("/:id", r.CustomerHandler.GetByID)
+		customer.Put("/:id", r.CustomerHandler.Update)
+		customer.Delete("/:id", r.CustomerHandler.Delete)
+	}
+
+	// recurring invoice
+	recurringInvoice := appLogged.Group("/recurring-invoices")
+	{
+		recurringInvoice.Get("", r.RecurringInvoiceHandler.Get)
+		recurringInvoice.Post("", r.RecurringInvoiceHandler.Create)
+		recurringInvoice.Get("/:id", r.RecurringInvoiceHandler.GetByID)
+		recurringInvoice.Put("/:id", r.RecurringInvoiceHandler.Update)
+		recurringInvoice.Delete("/:id", r.RecurringInvoiceHandler.Delete)
+		recurringInvoice.Post("/:id/pause", r.RecurringInvoiceHandler.Pause)
+		recurringInvoice.Post("/:id/resume", r.RecurringInvoiceHandler.Resume)
+		recurringInvoice.Get("/:id/preview", r.RecurringInvoiceHandler.Preview)
+	}
+
+	// numbering
+	numbering := appLogged.Group("/numbering")
+	{
+		numbering.Get("", r.NumberingHandler.Get)
+		numbering.Put("/:document", r.NumberingHandler.Save)
+	}
+
+	// business profile
+	businessProfile := appLogged.Group("/business-profiles")
+	{
+		businessProfile.Get("", r.BusinessProfileHandler.Get)
+		businessProfile.Post("", r.BusinessProfileHandler.Create)
+		businessProfile.Get("/:id", r.BusinessProfileHandler.GetByID)
+		businessProfile.Put("/:id", r.BusinessProfileHandler.Update)
+		businessProfile.Put("/:id/logo", r.BusinessProfileHandler.UploadLogo)
+		businessProfile.Delete("/:id", r.BusinessProfileHandler.Delete)
+	}
+
+	// tax rate
+	taxRate := appLogged.Group("/tax-rates")
+	{
+		taxRate.Get("", r.TaxRateHandler.Get)
+		taxRate.Post("", r.TaxRateHandler.Create)
+		taxRate.Put("/:id", r.TaxRateHandler.Update)
+	}
+
+	// pdf settings
+	pdfSettings := appLogged.Group("/pdf-settings")
+	{
+		pdfSettings.Get("", r.PDFSettingsHandler.Get)
+		pdfSettings.Put("", r.PDFSettingsHandler.Update)
+	}
+
+	// currency
+	currency := appLogged.Group("/currencies")
+	{
+		currency.Get("", r.ExchangeRateHandler.GetCurrencies)
+		currency.Put("/base", r.ExchangeRateHandler.SetBaseCurrency)
+	}
+
+	// exchange rate
+	exchangeRate := appLogged.Group("/exchange-rates")
+	{
+		exchangeRate.Get("", r.ExchangeRateHandler.Get)
+		exchangeRate.Post("", r.ExchangeRateHandler.Save)
 	}
 }
@@ -0,0 +1,284 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	"app/xonvera-core/internal/infrastructure/config"
+)
+
+// s3Timeout bounds a call to the storage service
+const s3Timeout = 30 * time.Second
+
+// s3BlobStore keeps files as objects of a bucket, requests are signed with AWS Signature Version 4
+// so it works with AWS S3 as well as compatible services such as MinIO or Cloudflare R2.
+// Keys are stored below prefix, it serves as a blob store as well as a document store.
+type s3BlobStore struct {
+	endpoint  *url.URL
+	bucket    string
+	prefix    string
+	region    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	client    *http.Client
+}
+
+// NewS3BlobStore uses S3_ENDPOINT when set, otherwise the AWS endpoint of S3_REGION
+func NewS3BlobStore(cfg *config.StorageConfig) (portRepository.BlobStore, error) {
+	return newS3Store(cfg, "")
+}
+
+func newS3Store(cfg *config.StorageConfig, prefix string) (*s3BlobStore, error) {
+	endpoint := cfg.S3Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.S3Region)
+	}
+	u, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3_ENDPOINT %q", cfg.S3Endpoint)
+	}
+
+	return &s3BlobStore{
+		endpoint:  u,
+		bucket:    cfg.S3Bucket,
+		prefix:    prefix,
+		region:    cfg.S3Region,
+		accessKey: cfg.S3AccessKey,
+		secretKey: cfg.S3SecretKey,
+		pathStyle: cfg.S3PathStyle,
+		client:    &http.Client{Timeout: s3Timeout},
+	}, nil
+}
+
+func (s *s3BlobStore) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, s.prefix+key, "", content, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+func (s *s3BlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.prefix+key, "", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("s3 object %s: %w", key, fs.ErrNotExist)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, s3Error(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (s *s3BlobStore) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.prefix+key, "", nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode != http.StatusNotFound {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+// s3ListResult is the part of a ListObjectsV2 response the store reads
+type s3ListResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+// List pages through ListObjectsV2, keys are returned without the prefix of the store
+func (s *s3BlobStore) List(ctx context.Context, prefix string) ([]domain.StoredDocument, error) {
+	var docs []domain.StoredDocument
+	query := map[string]string{"list-type": "2", "prefix": s.prefix + prefix}
+	for {
+		resp, err := s.do(ctx, http.MethodGet, "", s3Query(query), nil, "")
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode >= http.StatusMultipleChoices {
+			err = s3Error(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var res s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&res)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid s3 list response: %w", err)
+		}
+
+		for _, v := range res.Contents {
+			docs = append(docs, domain.StoredDocument{
+				Key:        strings.TrimPrefix(v.Key, s.prefix),
+				Size:       v.Size,
+				ModifiedAt: v.LastModified,
+			})
+		}
+		if !res.IsTruncated || res.NextContinuationToken == "" {
+			return docs, nil
+		}
+		query["continuation-token"] = res.NextContinuationToken
+	}
+}
+
+// do sends a signed request for an object, an empty key addresses the bucket itself
+func (s *s3BlobStore) do(ctx context.Context, method, key, query string, body []byte, contentType string) (*http.Response, error) {
+	u := *s.endpoint
+	path := "/" + strings.TrimLeft(key, "/")
+	if s.pathStyle {
+		path = "/" + s.bucket + path
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	u.Path = u.Path + path
+	u.RawPath = s3Escape(u.Path)
+	u.RawQuery = query
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call s3: %w", err)
+	}
+	return resp, nil
+}
+
+// sign adds the Signature Version 4 authorization of a request, it signs the host,
+// the content type and range and every x-amz header
+func (s *s3BlobStore) sign(req *http.Request, body []byte, t time.Time) {
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if k == "content-type" || k == "range" || strings.HasPrefix(k, "x-amz-") {
+			headers[k] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// s3Escape encodes a path the way S3 expects it to be signed, every byte but unreserved characters and "/"
+func s3Escape(path string) string {
+	return s3Encode(path, "-._~/")
+}
+
+// s3Query encodes query parameters in the canonical form they are signed in, sorted by name
+func s3Query(values map[string]string) string {
+	names := make([]string, 0, len(values))
+	for k := range values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, k := range names {
+		parts[i] = s3Encode(k, "-._~") + "=" + s3Encode(values[k], "-._~")
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Encode percent encodes every byte of s but letters, digits and the bytes of keep
+func s3Encode(s, keep string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte(keep, c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 responded %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"fmt"
+
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	"app/xonvera-core/internal/infrastructure/config"
+)
+
+// NewBlobStore returns the blob store selected by STORAGE_DRIVER.
+// The "s3" driver needs a bucket and its credentials, any other driver keeps files on the local disk.
+func NewBlobStore(cfg *config.StorageConfig) (portRepository.BlobStore, error) {
+	if cfg.Driver != "s3" {
+		return NewLocalBlobStore(cfg.LocalDir), nil
+	}
+
+	if cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+		return nil, fmt.Errorf("S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 storage driver")
+	}
+	return NewS3BlobStore(cfg)
+}
+
+// NewDocumentStore returns the store of rendered documents on the driver of STORAGE_DRIVER,
+// files are kept under DOCUMENT_LOCAL_DIR or below DOCUMENT_S3_PREFIX of the S3 bucket
+func NewDocumentStore(cfg *config.StorageConfig, docCfg *config.DocumentConfig) (portRepository.DocumentStore, error) {
+	if cfg.Driver != "s3" {
+		return &localBlobStore{dir: docCfg.LocalDir}, nil
+	}
+
+	if cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+		return nil, fmt.Errorf("S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 storage driver")
+	}
+	return newS3Store(cfg, docCfg.S3Prefix)
+}
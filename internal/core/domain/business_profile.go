@@ -0,0 +1,84 @@
+package domain
+
+import "time"
+
+// MaxLogoSize is the largest logo image accepted for a business profile
+const MaxLogoSize = 1 << 20
+
+// LogoExtensions maps the accepted logo content types to their file extension
+var LogoExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+}
+
+// BusinessProfile is an issuer identity printed on invoices, a user may keep one per brand
+type BusinessProfile struct {
+	ID              uint
+	AuthorID        uint
+	Name            string
+	LegalName       string
+	Address         string
+	TaxID           string
+	Email           string
+	Phone           string
+	LogoPath        string
+	PaymentTermDays int
+	FooterText      string
+	IsDefault       bool
+	Timestamp
+}
+
+// BankAccount is a payment instruction of a business profile
+type BankAccount struct {
+	ID            uint
+	ProfileID     uint
+	BankName      string
+	AccountName   string
+	AccountNumber string
+	Timestamp
+}
+
+func (BusinessProfile) TableName() string {
+	return "app.business_profiles"
+}
+
+func (BankAccount) TableName() string {
+	return "app.business_bank_accounts"
+}
+
+// DueDate returns the due date implied by the default payment terms, at the end of that day
+func (p *BusinessProfile) DueDate(issueDate time.Time) time.Time {
+	y, m, d := issueDate.AddDate(0, 0, p.PaymentTermDays).Date()
+	return time.Date(y, m, d, 23, 59, 59, 0, issueDate.Location())
+}
+
+func (p *BusinessProfile) Response(accounts []BankAccount) BusinessProfileResponse {
+	accountResponses := make([]BankAccountResponse, len(accounts))
+	for i, v := range accounts {
+		accountResponses[i] = v.Response()
+	}
+	return BusinessProfileResponse{
+		ID:              p.ID,
+		Name:            p.Name,
+		LegalName:       p.LegalName,
+		Address:         p.Address,
+		TaxID:           p.TaxID,
+		Email:           p.Email,
+		Phone:           p.Phone,
+		HasLogo:         p.LogoPath != "",
+		PaymentTermDays: p.PaymentTermDays,
+		FooterText:      p.FooterText,
+		IsDefault:       p.IsDefault,
+		BankAccounts:    accountResponses,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
+
+func (b *BankAccount) Response() BankAccountResponse {
+	return BankAccountResponse{
+		BankName:      b.BankName,
+		AccountName:   b.AccountName,
+		AccountNumber: b.AccountNumber,
+	}
+}
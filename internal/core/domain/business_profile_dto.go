@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// BankAccountRequest represents a bank account of a business profile
+type BankAccountRequest struct {
+	BankName      string `json:"bank_name" validate:"required,min=1,max=100"`
+	AccountName   string `json:"account_name" validate:"required,min=1,max=200"`
+	AccountNumber string `json:"account_number" validate:"required,min=1,max=50"`
+}
+
+// BusinessProfileRequest represents business profile input, bank accounts replace the existing ones
+type BusinessProfileRequest struct {
+	ID              uint                 `json:"-"`
+	Name            string               `json:"name" validate:"required,min=1,max=200"`
+	LegalName       string               `json:"legal_name" validate:"required,min=1,max=200"`
+	Address         string               `json:"address" validate:"max=1000"`
+	TaxID           string               `json:"tax_id" validate:"max=30"`
+	Email           string               `json:"email" validate:"omitempty,email,max=255"`
+	Phone           string               `json:"phone" validate:"max=50"`
+	PaymentTermDays int                  `json:"payment_term_days" validate:"min=0,max=365"`
+	FooterText      string               `json:"footer_text" validate:"max=1000"`
+	IsDefault       bool                 `json:"is_default"`
+	BankAccounts    []BankAccountRequest `json:"bank_accounts" validate:"max=10,dive"`
+	UserID          uint                 `json:"-"`
+}
+
+// BusinessProfileLogoRequest represents an uploaded logo image
+type BusinessProfileLogoRequest struct {
+	ID          uint
+	ContentType string
+	Content     []byte
+	UserID      uint
+}
+
+// BankAccountResponse represents bank account output
+type BankAccountResponse struct {
+	BankName      string `json:"bank_name"`
+	AccountName   string `json:"account_name"`
+	AccountNumber string `json:"account_number"`
+}
+
+// BusinessProfileResponse represents business profile output
+type BusinessProfileResponse struct {
+	ID              uint                  `json:"id"`
+	Name            string                `json:"name"`
+	LegalName       string                `json:"legal_name"`
+	Address         string                `json:"address"`
+	TaxID           string                `json:"tax_id"`
+	Email           string                `json:"email"`
+	Phone           string                `json:"phone"`
+	HasLogo         bool                  `json:"has_logo"`
+	PaymentTermDays int                   `json:"payment_term_days"`
+	FooterText      string                `json:"footer_text"`
+	IsDefault       bool                  `json:"is_default"`
+	BankAccounts    []BankAccountResponse `json:"bank_accounts"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBusinessProfileDueDate(t *testing.T) {
+	issueDate := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+	cases := map[int]time.Time{
+		0:  time.Date(2026, 1, 20, 23, 59, 59, 0, time.UTC),
+		14: time.Date(2026, 2, 3, 23, 59, 59, 0, time.UTC),
+		30: time.Date(2026, 2, 19, 23, 59, 59, 0, time.UTC),
+	}
+	for days, want := range cases {
+		p := BusinessProfile{PaymentTermDays: days}
+		if got := p.DueDate(issueDate); !got.Equal(want) {
+			t.Fatalf("DueDate with %d days expected %s, got %s", days, want, got)
+		}
+	}
+}
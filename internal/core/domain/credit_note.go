@@ -0,0 +1,240 @@
+package domain
+
+import "strings"
+
+// CreditNote reduces what a customer owes on an issued invoice, e.g. for returned goods.
+// Its total is applied to the original invoice first, the remaining credit can be applied
+// to other open invoices of the same customer or refunded.
+type CreditNote struct {
+	ID              uint
+	AuthorID        uint
+	Number          string
+	InvoiceID       int64
+	ProfileID       *uint
+	Issuer          string
+	CustomerID      *uint
+	Customer        string
+	CustomerCompany string
+	CustomerEmail   string
+	CustomerPhone   string
+	CustomerAddress string
+	CustomerTaxID   string
+	IssueDate       string
+	Reason          string
+	Currency        string
+	TaxMode         TaxMode
+	Subtotal        int
+	TaxTotal        int
+	Total           int
+	AmountApplied   int
+	AmountRefunded  int
+	Timestamp
+}
+
+// CreditNoteItem credits part of an invoice item, amounts are a share of what the item was billed
+type CreditNoteItem struct {
+	ID            uint
+	CreditNoteID  uint
+	InvoiceItemID uint
+	Description   string
+	Qty           int
+	Price         int
+	TaxRateID     *uint
+	TaxName       string
+	TaxRate       int
+	Subtotal      int
+	TaxAmount     int
+	Total         int
+	Timestamp
+}
+
+// CreditAllocation moves credit of a credit note onto an invoice, or back to the customer when InvoiceID is nil
+type CreditAllocation struct {
+	ID           uint
+	AuthorID     uint
+	CreditNoteID uint
+	InvoiceID    *int64
+	Amount       int
+	Note         string
+	Timestamp
+}
+
+// CreditedItem sums what was already credited of an invoice item
+type CreditedItem struct {
+	InvoiceItemID uint
+	Qty           int
+	Subtotal      int
+	TaxAmount     int
+}
+
+func (CreditNote) TableName() string {
+	return "app.credit_notes"
+}
+
+func (CreditNoteItem) TableName() string {
+	return "app.credit_note_items"
+}
+
+func (CreditAllocation) TableName() string {
+	return "app.credit_allocations"
+}
+
+// NewCreditNote copies the parties and currency of the original invoice
+func NewCreditNote(inv *Invoice) CreditNote {
+	return CreditNote{
+		AuthorID:        inv.AuthorID,
+		InvoiceID:       inv.ID,
+		ProfileID:       inv.ProfileID,
+		Issuer:          inv.Issuer,
+		CustomerID:      inv.CustomerID,
+		Customer:        inv.Customer,
+		CustomerCompany: inv.CustomerCompany,
+		CustomerEmail:   inv.CustomerEmail,
+		CustomerPhone:   inv.CustomerPhone,
+		CustomerAddress: inv.CustomerAddress,
+		CustomerTaxID:   inv.CustomerTaxID,
+		Currency:        inv.Currency,
+		TaxMode:         inv.TaxMode,
+	}
+}
+
+// CreditItem credits qty units of an invoice item given what was credited of it before.
+// Amounts are the billed amounts of the item in proportion to qty, the last units take
+// whatever is left so crediting a whole item in parts always adds up to what was billed.
+func CreditItem(item InvoiceItem, credited CreditedItem, qty int) (CreditNoteItem, bool) {
+	left := item.Qty - credited.Qty
+	if qty <= 0 || qty > left {
+		return CreditNoteItem{}, false
+	}
+
+	line := CreditNoteItem{
+		InvoiceItemID: item.ID,
+		Description:   item.Description,
+		Qty:           qty,
+		Price:         item.Price,
+		TaxRateID:     item.TaxRateID,
+		TaxName:       item.TaxName,
+		TaxRate:       item.TaxRate,
+	}
+	if qty == left {
+		line.Subtotal = item.Subtotal - credited.Subtotal
+		line.TaxAmount = item.TaxAmount - credited.TaxAmount
+	} else {
+		line.Subtotal = divRound(item.Subtotal*qty, item.Qty)
+		line.TaxAmount = divRound(item.TaxAmount*qty, item.Qty)
+	}
+	line.Total = line.Subtotal + line.TaxAmount
+	return line, true
+}
+
+// SetTotals sums the lines of the credit note
+func (c *CreditNote) SetTotals(items []CreditNoteItem) {
+	c.Subtotal, c.TaxTotal, c.Total = 0, 0, 0
+	for _, v := range items {
+		c.Subtotal += v.Subtotal
+		c.TaxTotal += v.TaxAmount
+		c.Total += v.Total
+	}
+}
+
+// Remaining returns the credit not applied or refunded yet
+func (c *CreditNote) Remaining() int {
+	return c.Total - c.AmountApplied - c.AmountRefunded
+}
+
+// SameCustomer reports whether an invoice was billed to the customer of the credit note.
+// Linked customers are compared by ID, free text customers by name.
+func (c *CreditNote) SameCustomer(inv *Invoice) bool {
+	if c.CustomerID != nil && inv.CustomerID != nil {
+		return *c.CustomerID == *inv.CustomerID
+	}
+	if c.CustomerID != nil || inv.CustomerID != nil {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(c.Customer), strings.TrimSpace(inv.Customer))
+}
+
+// Taxes groups the tax of the lines by rate for the totals of the PDF
+func (c *CreditNote) Taxes(items []CreditNoteItem) []InvoiceTax {
+	var taxes []InvoiceTax
+	groups := make(map[taxGroupKey]int)
+	for _, v := range items {
+		if v.TaxRateID == nil {
+			continue
+		}
+		key := taxGroupKey{taxRateID: *v.TaxRateID, name: v.TaxName, rate: v.TaxRate}
+		idx, ok := groups[key]
+		if !ok {
+			idx = len(taxes)
+			groups[key] = idx
+			taxes = append(taxes, InvoiceTax{
+				ID:        uint(idx + 1),
+				TaxRateID: v.TaxRateID,
+				Name:      v.TaxName,
+				Rate:      v.TaxRate,
+			})
+		}
+		taxes[idx].TaxableAmount += v.Subtotal
+		taxes[idx].TaxAmount += v.TaxAmount
+	}
+	return taxes
+}
+
+// Response takes the original invoice and the invoices allocations were applied to, by internal ID
+func (c *CreditNote) Response(invoices map[int64]InvoiceRef, items []CreditNoteItem, allocations []CreditAllocation) CreditNoteResponse {
+	itemResponses := make([]CreditNoteItemResponse, len(items))
+	for i, v := range items {
+		itemResponses[i] = CreditNoteItemResponse{
+			InvoiceItemID: v.InvoiceItemID,
+			Description:   v.Description,
+			Qty:           v.Qty,
+			Price:         v.Price,
+			TaxRateID:     v.TaxRateID,
+			TaxName:       v.TaxName,
+			TaxRate:       v.TaxRate,
+			Subtotal:      v.Subtotal,
+			TaxAmount:     v.TaxAmount,
+			Total:         v.Total,
+		}
+	}
+	allocationResponses := make([]CreditAllocationResponse, len(allocations))
+	for i, v := range allocations {
+		allocationResponses[i] = CreditAllocationResponse{
+			Amount:    v.Amount,
+			Note:      v.Note,
+			Refund:    v.InvoiceID == nil,
+			CreatedAt: v.CreatedAt,
+		}
+		if v.InvoiceID != nil {
+			ref := invoices[*v.InvoiceID]
+			allocationResponses[i].Invoice = &ref
+		}
+	}
+	return CreditNoteResponse{
+		ID:              c.ID,
+		Number:          c.Number,
+		Invoice:         invoices[c.InvoiceID],
+		ProfileID:       c.ProfileID,
+		Issuer:          c.Issuer,
+		CustomerID:      c.CustomerID,
+		Customer:        c.Customer,
+		CustomerCompany: c.CustomerCompany,
+		CustomerEmail:   c.CustomerEmail,
+		CustomerPhone:   c.CustomerPhone,
+		CustomerAddress: c.CustomerAddress,
+		CustomerTaxID:   c.CustomerTaxID,
+		IssueDate:       c.IssueDate,
+		Reason:          c.Reason,
+		Currency:        c.Currency,
+		TaxMode:         c.TaxMode,
+		Subtotal:        c.Subtotal,
+		TaxTotal:        c.TaxTotal,
+		Total:           c.Total,
+		AmountApplied:   c.AmountApplied,
+		AmountRefunded:  c.AmountRefunded,
+		Remaining:       c.Remaining(),
+		Items:           itemResponses,
+		Allocations:     allocationResponses,
+		CreatedAt:       c.CreatedAt,
+	}
+}
@@ -0,0 +1,92 @@
+package domain
+
+import "time"
+
+// CreditNoteItemRequest selects how many units of an invoice item are credited
+type CreditNoteItemRequest struct {
+	ItemID uint `json:"item_id" validate:"required,min=1"`
+	Qty    int  `json:"qty" validate:"required,min=1"`
+}
+
+// CreditNoteRequest represents credit note input against an issued invoice
+type CreditNoteRequest struct {
+	InvoiceID string                  `json:"invoice_id" validate:"required,uuid"`
+	IssueDate string                  `json:"issue_date" validate:"required,datetime=2006-01-02"`
+	Reason    string                  `json:"reason" validate:"required,min=1,max=1000"`
+	Items     []CreditNoteItemRequest `json:"items" validate:"required,min=1,dive"`
+	UserID    uint                    `json:"-"`
+}
+
+// ApplyCreditRequest represents applying remaining credit to another open invoice
+type ApplyCreditRequest struct {
+	InvoiceID string `json:"invoice_id" validate:"required,uuid"`
+	Amount    int    `json:"amount" validate:"required,min=1"`
+	ID        uint   `json:"-"`
+	UserID    uint   `json:"-"`
+}
+
+// RefundCreditRequest represents paying remaining credit back to the customer
+type RefundCreditRequest struct {
+	Amount int    `json:"amount" validate:"required,min=1"`
+	Note   string `json:"note" validate:"max=500"`
+	ID     uint   `json:"-"`
+	UserID uint   `json:"-"`
+}
+
+// InvoiceRef identifies a related invoice in responses
+type InvoiceRef struct {
+	ID     string `json:"id"`
+	Number string `json:"number"`
+}
+
+// CreditNoteItemResponse represents credit note item output
+type CreditNoteItemResponse struct {
+	InvoiceItemID uint   `json:"invoice_item_id"`
+	Description   string `json:"description"`
+	Qty           int    `json:"qty"`
+	Price         int    `json:"price"`
+	TaxRateID     *uint  `json:"tax_rate_id"`
+	TaxName       string `json:"tax_name"`
+	TaxRate       int    `json:"tax_rate"`
+	Subtotal      int    `json:"subtotal"`
+	TaxAmount     int    `json:"tax_amount"`
+	Total         int    `json:"total"`
+}
+
+// CreditAllocationResponse represents credit applied to an invoice or refunded
+type CreditAllocationResponse struct {
+	Invoice   *InvoiceRef `json:"invoice,omitempty"`
+	Refund    bool        `json:"refund"`
+	Amount    int         `json:"amount"`
+	Note      string      `json:"note,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// CreditNoteResponse represents credit note output
+type CreditNoteResponse struct {
+	ID              uint                       `json:"id"`
+	Number          string                     `json:"number"`
+	Invoice         InvoiceRef                 `json:"invoice"`
+	ProfileID       *uint                      `json:"profile_id"`
+	Issuer          string                     `json:"issuer"`
+	CustomerID      *uint                      `json:"customer_id"`
+	Customer        string                     `json:"customer"`
+	CustomerCompany string                     `json:"customer_company"`
+	CustomerEmail   string                     `json:"customer_email"`
+	CustomerPhone   string                     `json:"customer_phone"`
+	CustomerAddress string                     `json:"customer_address"`
+	CustomerTaxID   string                     `json:"customer_tax_id"`
+	IssueDate       string                     `json:"issue_date"`
+	Reason          string                     `json:"reason"`
+	Currency        string                     `json:"currency"`
+	TaxMode         TaxMode                    `json:"tax_mode"`
+	Subtotal        int                        `json:"subtotal"`
+	TaxTotal        int                        `json:"tax_total"`
+	Total           int                        `json:"total"`
+	AmountApplied   int                        `json:"amount_applied"`
+	AmountRefunded  int                        `json:"amount_refunded"`
+	Remaining       int                        `json:"remaining"`
+	Items           []CreditNoteItemResponse   `json:"items,omitempty"`
+	Allocations     []CreditAllocationResponse `json:"allocations,omitempty"`
+	CreatedAt       time.Time                  `json:"created_at"`
+}
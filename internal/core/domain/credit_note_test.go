@@ -0,0 +1,49 @@
+package domain
+
+import "testing"
+
+func TestCreditItemSplitsAddUpToBilledAmounts(t *testing.T) {
+	item := InvoiceItem{ID: 1, Qty: 3, Price: 1000, Subtotal: 2900, TaxAmount: 319, Total: 3219}
+
+	first, ok := CreditItem(item, CreditedItem{}, 1)
+	if !ok {
+		t.Fatal("expected first credit to be accepted")
+	}
+	if first.Subtotal != 967 || first.TaxAmount != 106 || first.Total != 1073 {
+		t.Fatalf("unexpected proportional line %+v", first)
+	}
+
+	credited := CreditedItem{InvoiceItemID: 1, Qty: first.Qty, Subtotal: first.Subtotal, TaxAmount: first.TaxAmount}
+	rest, ok := CreditItem(item, credited, 2)
+	if !ok {
+		t.Fatal("expected remaining units to be accepted")
+	}
+	if first.Subtotal+rest.Subtotal != item.Subtotal || first.TaxAmount+rest.TaxAmount != item.TaxAmount {
+		t.Fatalf("expected credits to add up to the item, got %+v and %+v", first, rest)
+	}
+
+	credited.Qty += rest.Qty
+	if _, ok := CreditItem(item, credited, 1); ok {
+		t.Fatal("expected fully credited item to be rejected")
+	}
+}
+
+func TestCreditNoteSameCustomer(t *testing.T) {
+	id, other := uint(1), uint(2)
+	cases := []struct {
+		name string
+		note CreditNote
+		inv  Invoice
+		want bool
+	}{
+		{"same linked customer", CreditNote{CustomerID: &id}, Invoice{CustomerID: &id}, true},
+		{"other linked customer", CreditNote{CustomerID: &id}, Invoice{CustomerID: &other}, false},
+		{"linked and free text", CreditNote{CustomerID: &id, Customer: "Acme"}, Invoice{Customer: "Acme"}, false},
+		{"free text ignores case", CreditNote{Customer: "PT Acme "}, Invoice{Customer: "pt acme"}, true},
+	}
+	for _, c := range cases {
+		if got := c.note.SameCustomer(&c.inv); got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+}
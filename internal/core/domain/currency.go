@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of accounts and invoices created before multi-currency support
+const DefaultCurrency = "IDR"
+
+// Currency describes how amounts of a currency are stored and printed.
+// Amounts are always stored as integers in the minor unit of their currency.
+type Currency struct {
+	Code        string
+	Symbol      string
+	MinorUnits  int
+	ThousandSep string
+	DecimalSep  string
+}
+
+// IDR is kept at 0 minor units, rupiah amounts have always been stored as whole rupiah
+var currencies = map[string]Currency{
+	"IDR": {Code: "IDR", Symbol: "Rp", MinorUnits: 0, ThousandSep: ".", DecimalSep: ","},
+	"USD": {Code: "USD", Symbol: "$", MinorUnits: 2, ThousandSep: ",", DecimalSep: "."},
+	"SGD": {Code: "SGD", Symbol: "S$", MinorUnits: 2, ThousandSep: ",", DecimalSep: "."},
+	"MYR": {Code: "MYR", Symbol: "RM", MinorUnits: 2, ThousandSep: ",", DecimalSep: "."},
+	"AUD": {Code: "AUD", Symbol: "A$", MinorUnits: 2, ThousandSep: ",", DecimalSep: "."},
+	"EUR": {Code: "EUR", Symbol: "€", MinorUnits: 2, ThousandSep: ".", DecimalSep: ","},
+	"GBP": {Code: "GBP", Symbol: "£", MinorUnits: 2, ThousandSep: ",", DecimalSep: "."},
+	"JPY": {Code: "JPY", Symbol: "¥", MinorUnits: 0, ThousandSep: ",", DecimalSep: "."},
+}
+
+// LookupCurrency returns the currency registered under code
+func LookupCurrency(code string) (Currency, bool) {
+	c, ok := currencies[strings.ToUpper(code)]
+	return c, ok
+}
+
+// Currencies returns every supported currency ordered by code
+func Currencies() []Currency {
+	res := make([]Currency, 0, len(currencies))
+	for _, v := range currencies {
+		res = append(res, v)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Code < res[j].Code })
+	return res
+}
+
+// Format prints an amount given in minor units, e.g. 123456 USD as "$ 1,234.56"
+func (c Currency) Format(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return sign + c.Symbol + " " + formatDigits(amount, c.MinorUnits, c.ThousandSep, c.DecimalSep)
+}
+
+// formatDigits prints a non negative amount in minor units with the given separators
+func formatDigits(amount, minorUnits int, thousandSep, decimalSep string) string {
+	digits := strconv.Itoa(amount)
+	if len(digits) <= minorUnits {
+		digits = strings.Repeat("0", minorUnits-len(digits)+1) + digits
+	}
+	whole, fraction := digits[:len(digits)-minorUnits], digits[len(digits)-minorUnits:]
+
+	if fraction != "" {
+		return groupDigits(whole, thousandSep) + decimalSep + fraction
+	}
+	return groupDigits(whole, thousandSep)
+}
+
+// groupDigits puts the thousand separator between every three digits of a whole number
+func groupDigits(whole, thousandSep string) string {
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(thousandSep)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (c Currency) Response() CurrencyResponse {
+	return CurrencyResponse{
+		Code:       c.Code,
+		Symbol:     c.Symbol,
+		MinorUnits: c.MinorUnits,
+	}
+}
+
+// FormatMoney prints an amount in minor units of the currency code, unknown codes fall back to the bare number
+func FormatMoney(amount int, code string) string {
+	c, ok := LookupCurrency(code)
+	if !ok {
+		return strconv.Itoa(amount)
+	}
+	return c.Format(amount)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "testing"
+
+func TestFormatMoney(t *testing.T) {
+	cases := []struct {
+		amount int
+		code   string
+		want   string
+	}{
+		{1500000, "IDR", "Rp 1.500.000"},
+		{123456, "USD", "$ 1,234.56"},
+		{5, "USD", "$ 0.05"},
+		{-2500, "SGD", "-S$ 25.00"},
+		{1000, "XXX", "1000"},
+	}
+	for _, c := range cases {
+		if got := FormatMoney(c.amount, c.code); got != c.want {
+			t.Fatalf("FormatMoney(%d, %s) expected %q, got %q", c.amount, c.code, c.want, got)
+		}
+	}
+}
+
+func TestParseExchangeRate(t *testing.T) {
+	rate, err := ParseExchangeRate("16250.5")
+	if err != nil || rate != 1625050000000 {
+		t.Fatalf("unexpected rate %d, err %v", rate, err)
+	}
+	if got := FormatExchangeRate(rate); got != "16250.5" {
+		t.Fatalf("expected 16250.5, got %s", got)
+	}
+	if _, err = ParseExchangeRate("-1"); err == nil {
+		t.Fatal("expected negative rate to be rejected")
+	}
+}
+
+func TestConvertAmount(t *testing.T) {
+	rate, _ := ParseExchangeRate("16250")
+
+	// 12.34 USD into rupiah
+	if got := ConvertAmount(1234, "USD", "IDR", rate); got != 200525 {
+		t.Fatalf("expected 200525, got %d", got)
+	}
+
+	inverse := (&ExchangeRate{Currency: "USD", BaseCurrency: "IDR", Rate: rate}).Inverse()
+	if got := ConvertAmount(200525, "IDR", "USD", inverse.Rate); got != 1234 {
+		t.Fatalf("expected 1234, got %d", got)
+	}
+}
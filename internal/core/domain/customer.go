@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Customer is an entry of the address book, invoices keep a snapshot of it
+type Customer struct {
+	ID       uint
+	AuthorID uint
+	Name     string
+	Company  string
+	Email    string
+	Phone    string
+	Address  string
+	TaxID    string
+	Notes    string
+	Timestamp
+}
+
+func (Customer) TableName() string {
+	return "app.customers"
+}
+
+func (c *Customer) Response() CustomerResponse {
+	return CustomerResponse{
+		ID:        c.ID,
+		Name:      c.Name,
+		Company:   c.Company,
+		Email:     c.Email,
+		Phone:     c.Phone,
+		Address:   c.Address,
+		TaxID:     c.TaxID,
+		Notes:     c.Notes,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// UnlinkedCustomer is a free text customer spelling found on invoices without a customer
+type UnlinkedCustomer struct {
+	AuthorID uint
+	Customer string
+	Total    int
+}
+
+// legalForms are company prefixes and suffixes ignored when matching customer names
+var legalForms = map[string]bool{"pt": true, "cv": true, "ud": true, "pd": true, "fa": true, "tbk": true}
+
+// NormalizeCustomerName returns the key used to match different spellings of the same customer,
+// e.g. "PT. Maju Jaya", "pt maju  jaya" and "Maju Jaya" all become "maju jaya"
+func NormalizeCustomerName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for len(words) > 1 && legalForms[words[0]] {
+		words = words[1:]
+	}
+	for len(words) > 1 && legalForms[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+
+	return strings.Join(words, " ")
+}
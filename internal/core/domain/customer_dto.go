@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// CustomerRequest represents customer input, TaxID holds the NPWP
+type CustomerRequest struct {
+	ID      uint   `json:"-"`
+	Name    string `json:"name" validate:"required,min=1,max=200"`
+	Company string `json:"company" validate:"max=200"`
+	Email   string `json:"email" validate:"omitempty,email,max=255"`
+	Phone   string `json:"phone" validate:"max=50"`
+	Address string `json:"address" validate:"max=1000"`
+	TaxID   string `json:"tax_id" validate:"max=30"`
+	Notes   string `json:"notes" validate:"max=1000"`
+	UserID  uint   `json:"-"`
+}
+
+// CustomerResponse represents customer output
+type CustomerResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Company   string    `json:"company"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	Address   string    `json:"address"`
+	TaxID     string    `json:"tax_id"`
+	Notes     string    `json:"notes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CustomerDedupeResult summarizes the linking of free text invoice customers
+type CustomerDedupeResult struct {
+	CustomersCreated int `json:"customers_created"`
+	InvoicesLinked   int `json:"invoices_linked"`
+}
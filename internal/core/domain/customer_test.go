@@ -0,0 +1,19 @@
+package domain
+
+import "testing"
+
+func TestNormalizeCustomerName(t *testing.T) {
+	cases := map[string]string{
+		"PT. Maju Jaya":     "maju jaya",
+		"pt maju  jaya":     "maju jaya",
+		"Maju Jaya, Tbk":    "maju jaya",
+		"  CV Sinar-Abadi ": "sinar abadi",
+		"Budi":              "budi",
+		"PT":                "pt",
+	}
+	for name, want := range cases {
+		if got := NormalizeCustomerName(name); got != want {
+			t.Fatalf("NormalizeCustomerName(%q) expected %q, got %q", name, want, got)
+		}
+	}
+}
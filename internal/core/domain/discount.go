@@ -0,0 +1,31 @@
+package domain
+
+// maxDiscountPercentage is the upper bound of a percentage discount
+const maxDiscountPercentage = 100
+
+// Valid reports whether value is an acceptable discount of type t on base.
+// An empty type is only valid without a value.
+func (t DiscountType) Valid(value, base int) bool {
+	switch t {
+	case "":
+		return value == 0
+	case DiscountTypePercentage:
+		return value >= 0 && value <= maxDiscountPercentage
+	case DiscountTypeAmount:
+		return value >= 0 && value <= base
+	default:
+		return false
+	}
+}
+
+// Amount returns how much of base is taken off by a discount of type t, never more than base
+func (t DiscountType) Amount(value, base int) int {
+	var amount int
+	switch t {
+	case DiscountTypePercentage:
+		amount = divRound(base*value, maxDiscountPercentage)
+	case DiscountTypeAmount:
+		amount = value
+	}
+	return min(max(amount, 0), base)
+}
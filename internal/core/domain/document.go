@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// StoredDocument is a file kept in a document store, Key is relative to the store
+type StoredDocument struct {
+	Key        string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// DocumentPrefix is the folder holding every rendering of one document, e.g. invoice/<id>/
+func DocumentPrefix(doc DocumentType, id string) string {
+	return string(doc) + "/" + id + "/"
+}
+
+// DocumentKey names a rendering after a hash of everything it was rendered from, e.g. invoice/<id>/<sha256>.pdf.
+// Any change to the content gives another key, so a stale rendering is never looked up again.
+func DocumentKey(doc DocumentType, id string, content ...any) (string, error) {
+	h := sha256.New()
+	if err := json.NewEncoder(h).Encode(content); err != nil {
+		return "", err
+	}
+	return DocumentPrefix(doc, id) + hex.EncodeToString(h.Sum(nil)) + ".pdf", nil
+}
+
+// StaleDocuments picks the documents a cleanup removes: files outside of a document folder, such as
+// caches of older releases, every rendering of a document but the newest, and anything older than retention.
+// A retention of zero keeps the newest rendering of every document.
+func StaleDocuments(docs []StoredDocument, now time.Time, retention time.Duration) []StoredDocument {
+	newest := map[string]StoredDocument{}
+	for _, v := range docs {
+		dir := path.Dir(v.Key)
+		if cur, ok := newest[dir]; !ok || v.ModifiedAt.After(cur.ModifiedAt) {
+			newest[dir] = v
+		}
+	}
+
+	var stale []StoredDocument
+	for _, v := range docs {
+		dir := path.Dir(v.Key)
+		switch {
+		case strings.Count(v.Key, "/") != 2:
+			stale = append(stale, v)
+		case newest[dir].Key != v.Key:
+			stale = append(stale, v)
+		case retention > 0 && v.ModifiedAt.Before(now.Add(-retention)):
+			stale = append(stale, v)
+		}
+	}
+	slices.SortFunc(stale, func(a, b StoredDocument) int { return strings.Compare(a.Key, b.Key) })
+	return stale
+}
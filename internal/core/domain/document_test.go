@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocumentKey(t *testing.T) {
+	a, err := DocumentKey(DocumentInvoice, "abc", 1, map[string]int{"total": 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(a, "invoice/abc/") || !strings.HasSuffix(a, ".pdf") || len(a) != len("invoice/abc/")+64+4 {
+		t.Fatalf("unexpected key %s", a)
+	}
+
+	b, _ := DocumentKey(DocumentInvoice, "abc", 1, map[string]int{"total": 100})
+	c, _ := DocumentKey(DocumentInvoice, "abc", 1, map[string]int{"total": 101})
+	d, _ := DocumentKey(DocumentInvoice, "abc", 2, map[string]int{"total": 100})
+	if a != b {
+		t.Fatalf("expected the same content to give the same key, got %s and %s", a, b)
+	}
+	if a == c || a == d {
+		t.Fatal("expected other content or another layout version to give another key")
+	}
+}
+
+func TestStaleDocuments(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	docs := []StoredDocument{
+		{Key: "invoice/a/old.pdf", ModifiedAt: now.Add(-2 * time.Hour)},
+		{Key: "invoice/a/new.pdf", ModifiedAt: now.Add(-time.Hour)},
+		{Key: "invoice/b/only.pdf", ModifiedAt: now.Add(-time.Hour)},
+		{Key: "quote/7/unused.pdf", ModifiedAt: now.Add(-40 * 24 * time.Hour)},
+		{Key: "invoice_a_classic_id-ID_1f3a5f.pdf", ModifiedAt: now},
+	}
+
+	stale := StaleDocuments(docs, now, 30*24*time.Hour)
+	var keys []string
+	for _, v := range stale {
+		keys = append(keys, v.Key)
+	}
+	want := "invoice/a/old.pdf,invoice_a_classic_id-ID_1f3a5f.pdf,quote/7/unused.pdf"
+	if got := strings.Join(keys, ","); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	if got := StaleDocuments(docs, now, 0); len(got) != 2 {
+		t.Fatalf("expected only superseded and stray files without retention, got %d", len(got))
+	}
+}
@@ -3,17 +3,88 @@ package domain
 // HTTP Error Response Codes
 const (
 	// 400 Bad Request Errors
-	ErrPhoneAlreadyRegistered = "400:phone number already registered"
-	ErrInvalidRegisterRequest = "400:invalid register request"
-	ErrInvalidCredentials     = "400:invalid credentials"
-	ErrInvalidRefreshToken    = "400:invalid refresh token"
-	ErrRefreshTokenExpired    = "400:refresh token has expired"
-	ErrInvoiceIDRequired      = "400:invoice ID is required for update"
-	ErrInvalidPackage         = "400:invalid package"
+	ErrPhoneAlreadyRegistered   = "400:phone number already registered"
+	ErrInvalidRegisterRequest   = "400:invalid register request"
+	ErrInvalidCredentials       = "400:invalid credentials"
+	ErrInvalidRefreshToken      = "400:invalid refresh token"
+	ErrRefreshTokenExpired      = "400:refresh token has expired"
+	ErrInvoiceIDRequired        = "400:invoice ID is required for update"
+	ErrInvalidPackage           = "400:invalid package"
+	ErrPaymentExceedsBalance    = "400:payment amount exceeds outstanding balance"
+	ErrInvalidTaxRate           = "400:invalid tax rate"
+	ErrInvalidDiscount          = "400:invalid discount"
+	ErrInvalidCurrency          = "400:unsupported currency"
+	ErrInvalidExchangeRate      = "400:invalid exchange rate"
+	ErrInvalidLogo              = "400:logo must be a png or jpeg image up to 1MB"
+	ErrDueDateRequired          = "400:due date is required without payment terms"
+	ErrIssuerRequired           = "400:issuer is required without a business profile"
+	ErrInvalidRecurringSchedule = "400:end date must not be before start date"
+	ErrInvalidNumberingTemplate = "400:numbering template must contain one {SEQ} and the date parts its reset period needs"
+	ErrInvalidCreditItem        = "400:credited item is not on the invoice or exceeds the quantity left to credit"
+	ErrCreditExceedsRemaining   = "400:amount exceeds the remaining credit"
+	ErrCreditExceedsBalance     = "400:amount exceeds outstanding balance of the invoice"
+	ErrInvalidQuoteValidity     = "400:valid until must not be before the issue date"
+	ErrInvalidQuoteItem         = "400:quote line is not on the quote or already invoiced"
+	ErrInvalidShareExpiry       = "400:share link expiry must be in the future"
+	ErrInvalidInvoiceFilter     = "400:invalid invoice filter"
+	ErrInvalidInvoiceSort       = "400:invoice can only be sorted by created_at, issue_date, due_date, number, customer, status or total"
+	ErrInvalidCursor            = "400:invalid cursor, start again from the first page"
+	ErrInvalidImportFile        = "400:import file must be a CSV file up to 2MB with a header row"
+	ErrImportTooLarge           = "400:import file has more than 5000 rows, split it into smaller files"
+	ErrInvalidIfMatch           = "400:invalid If-Match header, send the ETag of the invoice"
+	ErrInvalidAttachment        = "400:attachment must be a pdf, png, jpeg or webp file up to 10MB"
 
 	// 404 Not Found Errors
-	ErrNotFoundInvoice = "404:not found invoice"
-	ErrNotFoundToken   = "404:not found token"
+	ErrNotFoundInvoice          = "404:not found invoice"
+	ErrNotFoundToken            = "404:not found token"
+	ErrNotFoundPayment          = "404:not found payment"
+	ErrNotFoundTaxRate          = "404:not found tax rate"
+	ErrNotFoundCustomer         = "404:not found customer"
+	ErrNotFoundExchangeRate     = "404:not found exchange rate for currency"
+	ErrNotFoundBusinessProfile  = "404:not found business profile"
+	ErrNotFoundRecurringInvoice = "404:not found recurring invoice"
+	ErrNotFoundNumberingScheme  = "404:not found numbering scheme for document"
+	ErrNotFoundCreditNote       = "404:not found credit note"
+	ErrNotFoundQuote            = "404:not found quote"
+	ErrNotFoundShareLink        = "404:not found share link"
+	ErrNotFoundExport           = "404:not found export"
+	ErrNotFoundRevision         = "404:not found invoice revision"
+	ErrNotFoundAttachment       = "404:not found attachment"
+
+	// 409 Conflict Errors
+	ErrInvalidInvoiceTransition = "409:invalid invoice status transition"
+	ErrInvoiceLocked            = "409:paid or void invoice can not be edited"
+	ErrPaymentNotAllowed        = "409:invoice does not accept payments in its current status"
+	ErrPaymentAlreadyReversed   = "409:payment already reversed"
+	ErrInvoiceHasPayments       = "409:invoice with payments can not be voided"
+	ErrInvoiceTotalBelowPaid    = "409:invoice total can not be lower than amount paid"
+	ErrInvoiceNotDeletable      = "409:only draft or void invoice can be deleted"
+	ErrInvoiceCurrencyLocked    = "409:invoice currency can not be changed once payments are recorded"
+	ErrRecurringRunExists       = "409:invoice already created for this recurring run"
+	ErrInvalidRecurringStatus   = "409:recurring invoice can not be paused or resumed in its current status"
+	ErrInvoiceNumberExists      = "409:invoice number already used, change the numbering template"
+	ErrCreditNoteNumberExists   = "409:credit note number already used, change the numbering template"
+	ErrInvoiceNotIssued         = "409:credit notes can only be issued for sent invoices"
+	ErrInvoiceHasCreditNotes    = "409:invoice with credit notes can not be edited or voided"
+	ErrCreditNotApplicable      = "409:credit can only be applied to open invoices of the same customer and currency"
+	ErrInvalidQuoteTransition   = "409:invalid quote status transition"
+	ErrQuoteLocked              = "409:quote can only be edited while draft or sent"
+	ErrQuoteExpired             = "409:quote validity date has passed"
+	ErrQuoteNotAccepted         = "409:only accepted quotes can be converted"
+	ErrQuoteNotDeletable        = "409:quote with invoiced lines can not be deleted"
+	ErrQuoteNumberExists        = "409:quote number already used, change the numbering template"
+	ErrInvoiceNotShareable      = "409:draft invoices can not be shared"
+	ErrExportNotReady           = "409:export is not finished yet"
+	ErrExportFailed             = "409:export failed, export again"
+	ErrInvoiceAlreadyImported   = "409:invoice already imported for this reference"
+	ErrTooManyAttachments       = "409:invoice already has 20 attachments, delete one first"
+
+	// 410 Gone Errors
+	ErrShareLinkExpired = "410:share link has expired or was revoked"
+	ErrExportExpired    = "410:export file has expired, export again"
+
+	// 412 Precondition Failed Errors
+	ErrInvoiceVersionMismatch = "412:invoice was changed by someone else, reload it and try again"
 
 	// 401 Unauthorized Errors
 	ErrUnauthorized = "401:unauthorized"
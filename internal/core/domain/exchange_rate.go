@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ExchangeRateScale is the fixed point scale of stored exchange rates, rates keep 8 decimals
+const ExchangeRateScale int64 = 100000000
+
+// ExchangeRate is the price of one major unit of Currency expressed in BaseCurrency,
+// e.g. Currency USD, BaseCurrency IDR and Rate 16250 * ExchangeRateScale
+type ExchangeRate struct {
+	ID            uint
+	AuthorID      uint
+	Currency      string
+	BaseCurrency  string
+	Rate          int64
+	EffectiveDate string
+	Timestamp
+}
+
+func (ExchangeRate) TableName() string {
+	return "app.exchange_rates"
+}
+
+func (e *ExchangeRate) Response() ExchangeRateResponse {
+	return ExchangeRateResponse{
+		ID:            e.ID,
+		Currency:      e.Currency,
+		BaseCurrency:  e.BaseCurrency,
+		Rate:          FormatExchangeRate(e.Rate),
+		EffectiveDate: e.EffectiveDate,
+		CreatedAt:     e.CreatedAt,
+	}
+}
+
+// Inverse returns the rate converting BaseCurrency back into Currency
+func (e *ExchangeRate) Inverse() ExchangeRate {
+	inv := *e
+	inv.Currency, inv.BaseCurrency = e.BaseCurrency, e.Currency
+	inv.Rate = 0
+	if e.Rate > 0 {
+		scale := big.NewInt(ExchangeRateScale)
+		r := new(big.Rat).SetFrac(new(big.Int).Mul(scale, scale), big.NewInt(e.Rate))
+		inv.Rate = roundRat(r).Int64()
+	}
+	return inv
+}
+
+// ParseExchangeRate converts a decimal string such as "16250.5" into a scaled rate
+func ParseExchangeRate(s string) (int64, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() <= 0 {
+		return 0, fmt.Errorf(ErrInvalidExchangeRate)
+	}
+	scaled := roundRat(r.Mul(r, new(big.Rat).SetInt64(ExchangeRateScale)))
+	if !scaled.IsInt64() || scaled.Sign() <= 0 {
+		return 0, fmt.Errorf(ErrInvalidExchangeRate)
+	}
+	return scaled.Int64(), nil
+}
+
+// FormatExchangeRate prints a scaled rate as a decimal string without trailing zeros
+func FormatExchangeRate(rate int64) string {
+	s := new(big.Rat).SetFrac64(rate, ExchangeRateScale).FloatString(8)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// ConvertAmount converts an amount in minor units of from into minor units of to
+// using a scaled rate giving the price of one major unit of from in to
+func ConvertAmount(amount int, from, to string, rate int64) int {
+	if from == to {
+		return amount
+	}
+	src, _ := LookupCurrency(from)
+	dst, _ := LookupCurrency(to)
+
+	num := new(big.Int).Mul(big.NewInt(int64(amount)), big.NewInt(rate))
+	num.Mul(num, pow10(dst.MinorUnits))
+	den := new(big.Int).Mul(big.NewInt(ExchangeRateScale), pow10(src.MinorUnits))
+
+	return int(roundRat(new(big.Rat).SetFrac(num, den)).Int64())
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundRat rounds a rational half away from zero
+func roundRat(r *big.Rat) *big.Int {
+	num, den := new(big.Int).Set(r.Num()), r.Denom()
+	half := new(big.Int).Quo(den, big.NewInt(2))
+	if num.Sign() < 0 {
+		num.Sub(num, half)
+	} else {
+		num.Add(num, half)
+	}
+	return num.Quo(num, den)
+}
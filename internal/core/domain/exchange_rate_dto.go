@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// ExchangeRateRequest represents a manual exchange rate input, rate is the price of one
+// major unit of currency in the account base currency, e.g. "16250.5"
+type ExchangeRateRequest struct {
+	Currency      string `json:"currency" validate:"required,len=3"`
+	Rate          string `json:"rate" validate:"required,numeric"`
+	EffectiveDate string `json:"effective_date" validate:"required,datetime=2006-01-02"`
+	UserID        uint   `json:"-"`
+}
+
+// BaseCurrencyRequest represents the account base currency input
+type BaseCurrencyRequest struct {
+	Currency string `json:"currency" validate:"required,len=3"`
+	UserID   uint   `json:"-"`
+}
+
+// ExchangeRateResponse represents exchange rate output
+type ExchangeRateResponse struct {
+	ID            uint      `json:"id"`
+	Currency      string    `json:"currency"`
+	BaseCurrency  string    `json:"base_currency"`
+	Rate          string    `json:"rate"`
+	EffectiveDate string    `json:"effective_date"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CurrencyResponse represents a supported currency
+type CurrencyResponse struct {
+	Code       string `json:"code"`
+	Symbol     string `json:"symbol"`
+	MinorUnits int    `json:"minor_units"`
+}
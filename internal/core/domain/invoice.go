@@ -1,28 +1,158 @@
 package domain
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
+type InvoiceStatus string
+
+const (
+	InvoiceStatusDraft         InvoiceStatus = "draft"
+	InvoiceStatusSent          InvoiceStatus = "sent"
+	InvoiceStatusPartiallyPaid InvoiceStatus = "partially_paid"
+	InvoiceStatusPaid          InvoiceStatus = "paid"
+	InvoiceStatusOverdue       InvoiceStatus = "overdue"
+	InvoiceStatusVoid          InvoiceStatus = "void"
+)
+
+// invoiceTransitions lists the statuses an invoice may move to from each status
+var invoiceTransitions = map[InvoiceStatus][]InvoiceStatus{
+	InvoiceStatusDraft:         {InvoiceStatusSent, InvoiceStatusVoid},
+	InvoiceStatusSent:          {InvoiceStatusPartiallyPaid, InvoiceStatusPaid, InvoiceStatusOverdue, InvoiceStatusVoid},
+	InvoiceStatusPartiallyPaid: {InvoiceStatusPaid, InvoiceStatusOverdue},
+	InvoiceStatusOverdue:       {InvoiceStatusPartiallyPaid, InvoiceStatusPaid, InvoiceStatusVoid},
+	InvoiceStatusPaid:          {},
+	InvoiceStatusVoid:          {},
+}
+
+// CanTransitionTo reports whether an invoice in status s may move to next
+func (s InvoiceStatus) CanTransitionTo(next InvoiceStatus) bool {
+	for _, v := range invoiceTransitions[s] {
+		if v == next {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsPayment reports whether payments may be recorded against an invoice in status s
+func (s InvoiceStatus) AcceptsPayment() bool {
+	return s == InvoiceStatusSent || s == InvoiceStatusPartiallyPaid || s == InvoiceStatusOverdue
+}
+
+// IsDeletable reports whether an invoice in status s may be moved to the trash
+func (s InvoiceStatus) IsDeletable() bool {
+	return s == InvoiceStatusDraft || s == InvoiceStatusVoid
+}
+
+// IsLocked reports whether an invoice in status s can no longer be edited
+func (s InvoiceStatus) IsLocked() bool {
+	return s == InvoiceStatusPaid || s == InvoiceStatusVoid
+}
+
+// invoiceSortFields lists the fields an invoice list may be sorted by
+var invoiceSortFields = map[string]bool{
+	"created_at": true,
+	"issue_date": true,
+	"due_date":   true,
+	"number":     true,
+	"customer":   true,
+	"status":     true,
+	"total":      true,
+}
+
 type Invoice struct {
-	ID        int64
-	AuthorID  uint
-	Issuer    string
-	Customer  string
-	IssueDate string
-	DueDate   time.Time
-	Note      string
-	Status    string
+	ID int64
+	// PublicID is the opaque identifier used in URLs, ID is only unique per author
+	PublicID string
+	// Number is the human facing number, assigned from the numbering scheme when the invoice is sent
+	Number          string
+	AuthorID        uint
+	ProfileID       *uint
+	Issuer          string
+	Customer        string
+	CustomerID      *uint
+	CustomerCompany string
+	CustomerEmail   string
+	CustomerPhone   string
+	CustomerAddress string
+	CustomerTaxID   string
+	IssueDate       string
+	DueDate         time.Time
+	Note            string
+	Status          InvoiceStatus
+	Currency        string
+	ExchangeRate    int64
+	BaseCurrency    string
+	TaxMode         TaxMode
+	TaxRateID       *uint
+	DiscountType    DiscountType
+	Discount        int
+	Subtotal        int
+	DiscountAmount  int
+	DiscountTotal   int
+	TaxTotal        int
+	Total           int
+	AmountPaid      int
+	// AmountCredited is the part of the total settled by credit notes instead of payments
+	AmountCredited int
+	BaseTotal      int
+	// RecurringID and RecurringRunDate link an invoice to the schedule run that created it
+	RecurringID      *uint
+	RecurringRunDate string
+	// ImportRef is the reference of the import file row group the invoice was created from
+	ImportRef string
+	// Version counts the changes of the invoice from 1, an update from a stale version is refused
+	Version int
 	Timestamp
 }
 
+// ETag returns the entity tag of an invoice version
+func InvoiceETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// ParseIfMatch reads the invoice version of an If-Match header, an empty header or * matches any version and returns 0.
+// If-Match compares tags strongly, so a weak tag never matches the invoice.
+func ParseIfMatch(header string) (int, error) {
+	tag := strings.TrimSpace(header)
+	if tag == "" || tag == "*" {
+		return 0, nil
+	}
+
+	if strings.HasPrefix(tag, "W/") {
+		return 0, fmt.Errorf(ErrInvoiceVersionMismatch)
+	}
+	if len(tag) < 3 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, fmt.Errorf(ErrInvalidIfMatch)
+	}
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf(ErrInvalidIfMatch)
+	}
+	return version, nil
+}
+
 type InvoiceItem struct {
-	ID          uint
-	InvoiceID   int64
-	Description string
-	Qty         int
-	Price       int
-	Total       int
+	ID              uint
+	InvoiceID       int64
+	AuthorID        uint
+	Description     string
+	Qty             int
+	Price           int
+	DiscountType    DiscountType
+	Discount        int
+	DiscountAmount  int
+	InvoiceDiscount int
+	TaxRateID       *uint
+	TaxName         string
+	TaxRate         int
+	Subtotal        int
+	TaxAmount       int
+	Total           int
 	Timestamp
 }
 
@@ -34,32 +164,145 @@ func (InvoiceItem) TableName() string {
 	return "app.invoice_items"
 }
 
-func (i *Invoice) Response(items []InvoiceItem) InvoiceResponse {
+// SnapshotCustomer copies the current customer details onto the invoice,
+// later edits of the customer do not change issued invoices
+func (i *Invoice) SnapshotCustomer(c *Customer) {
+	i.CustomerID = &c.ID
+	i.Customer = c.Name
+	i.CustomerCompany = c.Company
+	i.CustomerEmail = c.Email
+	i.CustomerPhone = c.Phone
+	i.CustomerAddress = c.Address
+	i.CustomerTaxID = c.TaxID
+}
+
+// Settled returns the amount paid or credited so far
+func (i *Invoice) Settled() int {
+	return i.AmountPaid + i.AmountCredited
+}
+
+// Balance returns the amount still owed on the invoice
+func (i *Invoice) Balance() int {
+	return i.Total - i.Settled()
+}
+
+// SettledStatus returns the status implied by the amount paid or credited so far.
+// It only applies to invoices that have already been issued. Due dates are compared
+// by day as in MarkOverdue, an invoice due today is not overdue yet.
+func (i *Invoice) SettledStatus(now time.Time) InvoiceStatus {
+	switch {
+	case i.Settled() > 0 && i.Settled() >= i.Total:
+		return InvoiceStatusPaid
+	case i.Settled() > 0:
+		return InvoiceStatusPartiallyPaid
+	case !i.DueDate.IsZero() && i.DueDate.Format(time.DateOnly) < now.Format(time.DateOnly):
+		return InvoiceStatusOverdue
+	default:
+		return InvoiceStatusSent
+	}
+}
+
+func (i *Invoice) Response(items []InvoiceItem, taxes []InvoiceTax) InvoiceResponse {
 	var itemResponses []InvoiceItemResponse
 	for _, item := range items {
 		itemResponses = append(itemResponses, item.Response())
 	}
+	var taxResponses []InvoiceTaxResponse
+	for _, tax := range taxes {
+		taxResponses = append(taxResponses, tax.Response())
+	}
 	return InvoiceResponse{
-		ID:        i.ID,
-		Issuer:    i.Issuer,
-		Customer:  i.Customer,
-		IssueDate: i.IssueDate,
-		DueDate:   i.DueDate,
-		Note:      i.Note,
-		Items:     itemResponses,
-		Status:    i.Status,
-		CreatedAt: i.CreatedAt,
-		UpdatedAt: i.UpdatedAt,
+		ID:              i.PublicID,
+		Number:          i.Number,
+		ProfileID:       i.ProfileID,
+		RecurringID:     i.RecurringID,
+		Issuer:          i.Issuer,
+		Customer:        i.Customer,
+		CustomerID:      i.CustomerID,
+		CustomerCompany: i.CustomerCompany,
+		CustomerEmail:   i.CustomerEmail,
+		CustomerPhone:   i.CustomerPhone,
+		CustomerAddress: i.CustomerAddress,
+		CustomerTaxID:   i.CustomerTaxID,
+		IssueDate:       i.IssueDate,
+		DueDate:         i.DueDate,
+		Note:            i.Note,
+		Items:           itemResponses,
+		Status:          i.Status,
+		Version:         i.Version,
+		Currency:        i.Currency,
+		ExchangeRate:    FormatExchangeRate(i.ExchangeRate),
+		BaseCurrency:    i.BaseCurrency,
+		BaseTotal:       i.BaseTotal,
+		TaxMode:         i.TaxMode,
+		TaxRateID:       i.TaxRateID,
+		DiscountType:    i.DiscountType,
+		Discount:        i.Discount,
+		Subtotal:        i.Subtotal,
+		DiscountAmount:  i.DiscountAmount,
+		DiscountTotal:   i.DiscountTotal,
+		TaxTotal:        i.TaxTotal,
+		Taxes:           taxResponses,
+		Total:           i.Total,
+		AmountPaid:      i.AmountPaid,
+		AmountCredited:  i.AmountCredited,
+		Balance:         i.Balance(),
+		CreatedAt:       i.CreatedAt,
+		UpdatedAt:       i.UpdatedAt,
 	}
 }
 
 func (i *InvoiceItem) Response() InvoiceItemResponse {
 	return InvoiceItemResponse{
-		ID:          i.ID,
-		Description: i.Description,
-		Qty:         i.Qty,
-		Price:       i.Price,
-		Total:       i.Total,
-		CreatedAt:   i.CreatedAt,
+		ID:              i.ID,
+		Description:     i.Description,
+		Qty:             i.Qty,
+		Price:           i.Price,
+		DiscountType:    i.DiscountType,
+		Discount:        i.Discount,
+		DiscountAmount:  i.DiscountAmount,
+		InvoiceDiscount: i.InvoiceDiscount,
+		TaxRateID:       i.TaxRateID,
+		TaxName:         i.TaxName,
+		TaxRate:         i.TaxRate,
+		Subtotal:        i.Subtotal,
+		TaxAmount:       i.TaxAmount,
+		Total:           i.Total,
+		CreatedAt:       i.CreatedAt,
+	}
+}
+
+// Normalize checks the filters of an invoice list and parses its statuses and sort order.
+// Invoices are listed newest first when no sort is given.
+func (r *InvoicePageReq) Normalize() error {
+	r.Statuses = nil
+	for _, v := range strings.Split(r.Status, ",") {
+		status := InvoiceStatus(strings.TrimSpace(v))
+		if status == "" {
+			continue
+		}
+		if _, ok := invoiceTransitions[status]; !ok {
+			return fmt.Errorf(ErrInvalidInvoiceFilter)
+		}
+		r.Statuses = append(r.Statuses, status)
+	}
+
+	switch {
+	case r.IssueFrom != "" && r.IssueTo != "" && r.IssueFrom > r.IssueTo:
+		return fmt.Errorf(ErrInvalidInvoiceFilter)
+	case r.DueFrom != "" && r.DueTo != "" && r.DueFrom > r.DueTo:
+		return fmt.Errorf(ErrInvalidInvoiceFilter)
+	case r.MinTotal != nil && r.MaxTotal != nil && *r.MinTotal > *r.MaxTotal:
+		return fmt.Errorf(ErrInvalidInvoiceFilter)
+	}
+
+	r.SortField, r.SortDesc = "created_at", true
+	if sort := strings.TrimSpace(r.Sort); sort != "" {
+		field, desc := strings.CutPrefix(sort, "-")
+		if !invoiceSortFields[field] {
+			return fmt.Errorf(ErrInvalidInvoiceSort)
+		}
+		r.SortField, r.SortDesc = field, desc
 	}
+	return nil
 }
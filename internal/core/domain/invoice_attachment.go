@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// MaxAttachmentSize is the largest file accepted as an invoice attachment
+	MaxAttachmentSize = 10 << 20
+	// MaxAttachments bounds how many files one invoice may have attached
+	MaxAttachments          = 20
+	maxAttachmentNameLength = 255
+)
+
+// AttachmentExtensions maps the accepted attachment content types to their file extension
+var AttachmentExtensions = map[string]string{
+	"application/pdf": "pdf",
+	"image/png":       "png",
+	"image/jpeg":      "jpg",
+	"image/webp":      "webp",
+}
+
+// InvoiceAttachment is a supporting document of an invoice such as a delivery proof or a contract.
+// The file itself is kept in the blob store under BlobKey.
+type InvoiceAttachment struct {
+	ID          uint
+	AuthorID    uint
+	InvoiceID   int64
+	Name        string
+	ContentType string
+	Size        int
+	BlobKey     string
+	AppendToPDF bool
+	CreatedAt   time.Time
+}
+
+func (InvoiceAttachment) TableName() string {
+	return "app.invoice_attachments"
+}
+
+// IsPDF reports whether the attachment can be appended to the invoice PDF
+func (a *InvoiceAttachment) IsPDF() bool {
+	return a.ContentType == "application/pdf"
+}
+
+func (a *InvoiceAttachment) Response() InvoiceAttachmentResponse {
+	return InvoiceAttachmentResponse{
+		ID:          a.ID,
+		Name:        a.Name,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		AppendToPDF: a.AppendToPDF,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+// AttachmentName keeps the base name of an uploaded file without control characters or quotes,
+// files without a usable name are called attachment with the extension of their type
+func AttachmentName(name, ext string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name))
+	if name == "" || name == "." || name == "/" {
+		return "attachment." + ext
+	}
+
+	if runes := []rune(name); len(runes) > maxAttachmentNameLength {
+		name = string(runes[:maxAttachmentNameLength])
+	}
+	return name
+}
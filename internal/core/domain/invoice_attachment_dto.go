@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// InvoiceAttachmentRequest represents an uploaded attachment, ContentType is sniffed from the content
+type InvoiceAttachmentRequest struct {
+	InvoiceID   string `query:"-"`
+	AppendToPDF bool   `query:"append_to_pdf"`
+	Name        string `query:"-"`
+	ContentType string `query:"-"`
+	Content     []byte `query:"-"`
+	UserID      uint   `query:"-"`
+}
+
+type InvoiceAttachmentResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int       `json:"size"`
+	AppendToPDF bool      `json:"append_to_pdf"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttachmentName(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "delivery proof.pdf", "delivery proof.pdf"},
+		{"unix path", "../../etc/passwd", "passwd"},
+		{"windows path", `C:\Users\me\contract.pdf`, "contract.pdf"},
+		{"quotes and control characters", "a\"b\r\n.png", "ab.png"},
+		{"empty", "", "attachment.pdf"},
+		{"only a directory", "docs/", "docs"},
+		{"root", "/", "attachment.pdf"},
+	}
+	for _, c := range cases {
+		if got := AttachmentName(c.in, "pdf"); got != c.want {
+			t.Errorf("%s: expected %q, got %q", c.name, c.want, got)
+		}
+	}
+
+	if got := AttachmentName(strings.Repeat("é", 300), "pdf"); len([]rune(got)) != maxAttachmentNameLength {
+		t.Errorf("expected name cut to %d characters, got %d", maxAttachmentNameLength, len([]rune(got)))
+	}
+}
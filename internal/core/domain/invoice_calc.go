@@ -0,0 +1,102 @@
+package domain
+
+// InvoiceTotals is the outcome of pricing the items of an invoice.
+// Subtotal is the sum of line amounts after item discounts, in the pricing basis of the tax mode.
+type InvoiceTotals struct {
+	Subtotal       int
+	DiscountAmount int
+	DiscountTotal  int
+	TaxTotal       int
+	Total          int
+	Taxes          []InvoiceTax
+}
+
+type taxGroupKey struct {
+	taxRateID uint
+	name      string
+	rate      int
+}
+
+// CalculateInvoice prices every item in place and returns the invoice totals.
+// Items must carry Qty, Price, their discount and their tax snapshot (TaxRateID, TaxName, TaxRate).
+// Item discounts apply first, the invoice discount is then spread over the lines in proportion
+// to their discounted amount so tax is always computed on what the customer actually pays.
+// In inclusive mode the item price already contains tax, in exclusive mode tax is added on top.
+func CalculateInvoice(mode TaxMode, discountType DiscountType, discount int, items []InvoiceItem) InvoiceTotals {
+	var totals InvoiceTotals
+	groups := make(map[taxGroupKey]int)
+
+	nets := make([]int, len(items))
+	for i := range items {
+		item := &items[i]
+		gross := item.Qty * item.Price
+		item.DiscountAmount = item.DiscountType.Amount(item.Discount, gross)
+		nets[i] = gross - item.DiscountAmount
+
+		totals.Subtotal += nets[i]
+		totals.DiscountTotal += item.DiscountAmount
+	}
+
+	totals.DiscountAmount = discountType.Amount(discount, totals.Subtotal)
+	totals.DiscountTotal += totals.DiscountAmount
+
+	remaining, remainingNet := totals.DiscountAmount, totals.Subtotal
+	for i := range items {
+		item := &items[i]
+
+		// Allocate against what is left so the shares always add up to the invoice discount
+		item.InvoiceDiscount = 0
+		if remainingNet > 0 {
+			item.InvoiceDiscount = divRound(remaining*nets[i], remainingNet)
+		}
+		remaining -= item.InvoiceDiscount
+		remainingNet -= nets[i]
+
+		amount := nets[i] - item.InvoiceDiscount
+
+		switch {
+		case item.TaxRate <= 0:
+			item.Subtotal = amount
+			item.TaxAmount = 0
+		case mode == TaxModeInclusive:
+			item.Subtotal = divRound(amount*basisPoints, basisPoints+item.TaxRate)
+			item.TaxAmount = amount - item.Subtotal
+		default:
+			item.Subtotal = amount
+			item.TaxAmount = divRound(amount*item.TaxRate, basisPoints)
+		}
+		item.Total = item.Subtotal + item.TaxAmount
+
+		totals.TaxTotal += item.TaxAmount
+		totals.Total += item.Total
+
+		if item.TaxRateID == nil {
+			continue
+		}
+
+		key := taxGroupKey{taxRateID: *item.TaxRateID, name: item.TaxName, rate: item.TaxRate}
+		idx, ok := groups[key]
+		if !ok {
+			idx = len(totals.Taxes)
+			groups[key] = idx
+			totals.Taxes = append(totals.Taxes, InvoiceTax{
+				ID:        uint(idx + 1),
+				TaxRateID: item.TaxRateID,
+				Name:      item.TaxName,
+				Rate:      item.TaxRate,
+			})
+		}
+		totals.Taxes[idx].TaxableAmount += item.Subtotal
+		totals.Taxes[idx].TaxAmount += item.TaxAmount
+	}
+
+	return totals
+}
+
+// divRound divides two non-negative integers rounding half up
+func divRound(a, b int) int {
+	if b == 0 {
+		return 0
+	}
+	return (a + b/2) / b
+}
@@ -0,0 +1,146 @@
+package domain
+
+import "testing"
+
+func TestCalculateInvoiceExclusive(t *testing.T) {
+	ppn := uint(1)
+	items := []InvoiceItem{
+		{Qty: 2, Price: 50000, TaxRateID: &ppn, TaxName: "PPN", TaxRate: 1100},
+		{Qty: 1, Price: 25000},
+	}
+
+	totals := CalculateInvoice(TaxModeExclusive, "", 0, items)
+
+	if items[0].Subtotal != 100000 || items[0].TaxAmount != 11000 || items[0].Total != 111000 {
+		t.Fatalf("unexpected taxed item: %+v", items[0])
+	}
+	if items[1].Subtotal != 25000 || items[1].TaxAmount != 0 || items[1].Total != 25000 {
+		t.Fatalf("unexpected untaxed item: %+v", items[1])
+	}
+	if totals.Subtotal != 125000 || totals.TaxTotal != 11000 || totals.Total != 136000 {
+		t.Fatalf("unexpected totals: %+v", totals)
+	}
+	if len(totals.Taxes) != 1 || totals.Taxes[0].TaxableAmount != 100000 || totals.Taxes[0].TaxAmount != 11000 {
+		t.Fatalf("unexpected tax breakdown: %+v", totals.Taxes)
+	}
+}
+
+func TestCalculateInvoiceInclusive(t *testing.T) {
+	ppn := uint(1)
+	items := []InvoiceItem{
+		{Qty: 1, Price: 111000, TaxRateID: &ppn, TaxName: "PPN", TaxRate: 1100},
+	}
+
+	totals := CalculateInvoice(TaxModeInclusive, "", 0, items)
+
+	if items[0].Subtotal != 100000 || items[0].TaxAmount != 11000 || items[0].Total != 111000 {
+		t.Fatalf("unexpected inclusive item: %+v", items[0])
+	}
+	if totals.Total != 111000 {
+		t.Fatalf("expected grand total to equal the inclusive price, got %d", totals.Total)
+	}
+}
+
+func TestCalculateInvoiceGroupsTaxes(t *testing.T) {
+	ppn11, ppn12 := uint(1), uint(2)
+	items := []InvoiceItem{
+		{Qty: 1, Price: 1000, TaxRateID: &ppn11, TaxName: "PPN", TaxRate: 1100},
+		{Qty: 1, Price: 1000, TaxRateID: &ppn12, TaxName: "PPN", TaxRate: 1200},
+		{Qty: 1, Price: 1000, TaxRateID: &ppn11, TaxName: "PPN", TaxRate: 1100},
+	}
+
+	totals := CalculateInvoice(TaxModeExclusive, "", 0, items)
+
+	if len(totals.Taxes) != 2 {
+		t.Fatalf("expected 2 tax lines, got %d", len(totals.Taxes))
+	}
+	if totals.Taxes[0].TaxableAmount != 2000 || totals.Taxes[0].TaxAmount != 220 {
+		t.Fatalf("unexpected 11%% line: %+v", totals.Taxes[0])
+	}
+	if totals.Taxes[1].TaxAmount != 120 {
+		t.Fatalf("unexpected 12%% line: %+v", totals.Taxes[1])
+	}
+}
+
+func TestFormatTaxRate(t *testing.T) {
+	cases := map[int]string{1100: "11%", 1250: "12.5%", 75: "0.75%", 0: "0%"}
+	for rate, want := range cases {
+		if got := FormatTaxRate(rate); got != want {
+			t.Fatalf("FormatTaxRate(%d) expected %s, got %s", rate, want, got)
+		}
+	}
+}
+
+func TestCalculateInvoiceItemDiscount(t *testing.T) {
+	items := []InvoiceItem{
+		{Qty: 2, Price: 50000, DiscountType: DiscountTypePercentage, Discount: 10},
+		{Qty: 1, Price: 30000, DiscountType: DiscountTypeAmount, Discount: 5000},
+	}
+
+	totals := CalculateInvoice(TaxModeExclusive, "", 0, items)
+
+	if items[0].DiscountAmount != 10000 || items[0].Total != 90000 {
+		t.Fatalf("unexpected percentage discounted item: %+v", items[0])
+	}
+	if items[1].DiscountAmount != 5000 || items[1].Total != 25000 {
+		t.Fatalf("unexpected amount discounted item: %+v", items[1])
+	}
+	if totals.Subtotal != 115000 || totals.DiscountTotal != 15000 || totals.Total != 115000 {
+		t.Fatalf("unexpected totals: %+v", totals)
+	}
+}
+
+func TestCalculateInvoiceDiscountBeforeTax(t *testing.T) {
+	ppn := uint(1)
+	items := []InvoiceItem{
+		{Qty: 1, Price: 100000, TaxRateID: &ppn, TaxName: "PPN", TaxRate: 1100},
+		{Qty: 1, Price: 50000},
+	}
+
+	totals := CalculateInvoice(TaxModeExclusive, DiscountTypeAmount, 15000, items)
+
+	if items[0].InvoiceDiscount != 10000 || items[1].InvoiceDiscount != 5000 {
+		t.Fatalf("invoice discount not allocated proportionally: %d, %d", items[0].InvoiceDiscount, items[1].InvoiceDiscount)
+	}
+	if items[0].Subtotal != 90000 || items[0].TaxAmount != 9900 {
+		t.Fatalf("tax not computed on discounted amount: %+v", items[0])
+	}
+	if totals.Subtotal != 150000 || totals.DiscountAmount != 15000 || totals.TaxTotal != 9900 || totals.Total != 144900 {
+		t.Fatalf("unexpected totals: %+v", totals)
+	}
+}
+
+func TestCalculateInvoiceDiscountAllocationRemainder(t *testing.T) {
+	items := []InvoiceItem{{Qty: 1, Price: 1}, {Qty: 1, Price: 1}, {Qty: 1, Price: 1}}
+
+	totals := CalculateInvoice(TaxModeExclusive, DiscountTypeAmount, 2, items)
+
+	allocated := 0
+	for _, v := range items {
+		allocated += v.InvoiceDiscount
+	}
+	if allocated != 2 || totals.Total != 1 {
+		t.Fatalf("expected whole discount to be allocated, got %d and total %d", allocated, totals.Total)
+	}
+}
+
+func TestDiscountTypeValid(t *testing.T) {
+	cases := []struct {
+		kind  DiscountType
+		value int
+		base  int
+		want  bool
+	}{
+		{"", 0, 100, true},
+		{"", 10, 100, false},
+		{DiscountTypePercentage, 100, 100, true},
+		{DiscountTypePercentage, 101, 100, false},
+		{DiscountTypeAmount, 100, 100, true},
+		{DiscountTypeAmount, 101, 100, false},
+	}
+	for _, c := range cases {
+		if got := c.kind.Valid(c.value, c.base); got != c.want {
+			t.Fatalf("%q.Valid(%d, %d) expected %v, got %v", c.kind, c.value, c.base, c.want, got)
+		}
+	}
+}
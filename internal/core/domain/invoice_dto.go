@@ -2,52 +2,130 @@ package domain
 
 import "time"
 
+// InvoicePageReq lists invoices with optional filters, every filter given must match.
+// Status takes a comma separated list and Sort a field name, prefixed with - for descending order.
 type InvoicePageReq struct {
 	PaginationRequest
+	Status     string `query:"status"`
+	CustomerID uint   `query:"customer_id" validate:"omitempty,min=1"`
+	IssueFrom  string `query:"issue_from" validate:"omitempty,datetime=2006-01-02"`
+	IssueTo    string `query:"issue_to" validate:"omitempty,datetime=2006-01-02"`
+	DueFrom    string `query:"due_from" validate:"omitempty,datetime=2006-01-02"`
+	DueTo      string `query:"due_to" validate:"omitempty,datetime=2006-01-02"`
+	MinTotal   *int   `query:"min_total" validate:"omitempty,min=0"`
+	MaxTotal   *int   `query:"max_total" validate:"omitempty,min=0"`
+	Overdue    bool   `query:"overdue"`
+	Sort       string `query:"sort"`
+
+	// Set by Normalize
+	Statuses  []InvoiceStatus `query:"-"`
+	SortField string          `query:"-"`
+	SortDesc  bool            `query:"-"`
 }
 
 // InvoiceItemRequest represents invoice item input
 type InvoiceItemRequest struct {
-	Description string `json:"description" validate:"required,min=1,max=500"`
-	Qty         int    `json:"qty" validate:"required,min=1"`
-	Price       int    `json:"price" validate:"required,min=0"`
+	Description  string       `json:"description" validate:"required,min=1,max=500"`
+	Qty          int          `json:"qty" validate:"required,min=1"`
+	Price        int          `json:"price" validate:"required,min=0"`
+	DiscountType DiscountType `json:"discount_type" validate:"required_with=Discount,omitempty,oneof=percentage amount"`
+	Discount     int          `json:"discount" validate:"min=0"`
+	TaxRateID    *uint        `json:"tax_rate_id" validate:"omitempty,min=1"`
 }
 
 // CreateInvoiceRequest represents invoice creation input
 type InvoiceRequest struct {
-	ID        int64                `json:"id" validate:"required"`
-	Issuer    string               `json:"issuer" validate:"required,min=1,max=200"`
-	Customer  string               `json:"customer" validate:"required,min=1,max=200"`
-	IssueDate string               `json:"issue_date" validate:"required,datetime=2006-01-02"`
-	DueDate   string               `json:"due_date" validate:"required,datetime=2006-01-02 15:04:05"`
-	Note      string               `json:"note" validate:"max=1000"`
-	Items     []InvoiceItemRequest `json:"items" validate:"required,min=1,dive"`
-	UserID    uint                 `json:"-"`
+	ID           string               `json:"id" validate:"required,uuid"`
+	ProfileID    *uint                `json:"profile_id" validate:"omitempty,min=1"`
+	Issuer       string               `json:"issuer" validate:"max=200"`
+	Customer     string               `json:"customer" validate:"required_without=CustomerID,max=200"`
+	CustomerID   *uint                `json:"customer_id" validate:"omitempty,min=1"`
+	IssueDate    string               `json:"issue_date" validate:"required,datetime=2006-01-02"`
+	DueDate      string               `json:"due_date" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+	Note         string               `json:"note" validate:"max=1000"`
+	Currency     string               `json:"currency" validate:"omitempty,len=3"`
+	ExchangeRate string               `json:"exchange_rate" validate:"omitempty,numeric"`
+	TaxMode      TaxMode              `json:"tax_mode" validate:"omitempty,oneof=exclusive inclusive"`
+	TaxRateID    *uint                `json:"tax_rate_id" validate:"omitempty,min=1"`
+	DiscountType DiscountType         `json:"discount_type" validate:"required_with=Discount,omitempty,oneof=percentage amount"`
+	Discount     int                  `json:"discount" validate:"min=0"`
+	Items        []InvoiceItemRequest `json:"items" validate:"required,min=1,dive"`
+	UserID       uint                 `json:"-"`
+
+	// Set by the scheduler, an invoice is created only once per run of a recurring invoice
+	RecurringID      *uint  `json:"-"`
+	RecurringRunDate string `json:"-"`
+
+	// Set by an import, an invoice is created only once per reference
+	ImportRef string `json:"-"`
+
+	// Set from the If-Match header, the update is refused when the invoice has another version
+	Version int `json:"-"`
+}
+
+// InvoiceCreateResponse represents the identifier of a created invoice
+type InvoiceCreateResponse struct {
+	ID string `json:"id"`
 }
 
 // InvoiceItemResponse represents invoice item output
 type InvoiceItemResponse struct {
-	ID          uint      `json:"id"`
-	InvoiceID   int64     `json:"invoice_id"`
-	Description string    `json:"description"`
-	Qty         int       `json:"qty"`
-	Price       int       `json:"price"`
-	Total       int       `json:"total"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID              uint         `json:"id"`
+	Description     string       `json:"description"`
+	Qty             int          `json:"qty"`
+	Price           int          `json:"price"`
+	DiscountType    DiscountType `json:"discount_type"`
+	Discount        int          `json:"discount"`
+	DiscountAmount  int          `json:"discount_amount"`
+	InvoiceDiscount int          `json:"invoice_discount"`
+	TaxRateID       *uint        `json:"tax_rate_id"`
+	TaxName         string       `json:"tax_name"`
+	TaxRate         int          `json:"tax_rate"`
+	Subtotal        int          `json:"subtotal"`
+	TaxAmount       int          `json:"tax_amount"`
+	Total           int          `json:"total"`
+	CreatedAt       time.Time    `json:"created_at"`
 }
 
 // InvoiceResponse represents invoice output
 type InvoiceResponse struct {
-	ID        int64                 `json:"id"`
-	Customer  string                `json:"customer"`
-	Issuer    string                `json:"issuer"`
-	IssueDate string                `json:"issue_date"`
-	DueDate   time.Time             `json:"due_date"`
-	Note      string                `json:"note"`
-	Status    string                `json:"status"`
-	Items     []InvoiceItemResponse `json:"items,omitempty"`
-	CreatedAt time.Time             `json:"created_at"`
-	UpdatedAt time.Time             `json:"updated_at"`
+	ID              string                `json:"id"`
+	Number          string                `json:"number"`
+	Customer        string                `json:"customer"`
+	CustomerID      *uint                 `json:"customer_id"`
+	CustomerCompany string                `json:"customer_company"`
+	CustomerEmail   string                `json:"customer_email"`
+	CustomerPhone   string                `json:"customer_phone"`
+	CustomerAddress string                `json:"customer_address"`
+	CustomerTaxID   string                `json:"customer_tax_id"`
+	ProfileID       *uint                 `json:"profile_id"`
+	Issuer          string                `json:"issuer"`
+	IssueDate       string                `json:"issue_date"`
+	DueDate         time.Time             `json:"due_date"`
+	Note            string                `json:"note"`
+	Status          InvoiceStatus         `json:"status"`
+	Version         int                   `json:"version"`
+	Currency        string                `json:"currency"`
+	ExchangeRate    string                `json:"exchange_rate"`
+	BaseCurrency    string                `json:"base_currency"`
+	BaseTotal       int                   `json:"base_total"`
+	RecurringID     *uint                 `json:"recurring_id"`
+	TaxMode         TaxMode               `json:"tax_mode"`
+	TaxRateID       *uint                 `json:"tax_rate_id"`
+	DiscountType    DiscountType          `json:"discount_type"`
+	Discount        int                   `json:"discount"`
+	Subtotal        int                   `json:"subtotal"`
+	DiscountAmount  int                   `json:"discount_amount"`
+	DiscountTotal   int                   `json:"discount_total"`
+	TaxTotal        int                   `json:"tax_total"`
+	Taxes           []InvoiceTaxResponse  `json:"taxes,omitempty"`
+	Total           int                   `json:"total"`
+	AmountPaid      int                   `json:"amount_paid"`
+	AmountCredited  int                   `json:"amount_credited"`
+	Balance         int                   `json:"balance"`
+	Items           []InvoiceItemResponse `json:"items,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
 }
 
 // InvoiceListResponse represents list of invoices output
@@ -0,0 +1,267 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportXLSX ExportFormat = "xlsx"
+)
+
+// ContentType returns the media type of files in the format
+func (f ExportFormat) ContentType() string {
+	if f == ExportXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportRows selects whether an export has a row per invoice or a row per invoice item
+type ExportRows string
+
+const (
+	ExportRowsInvoice ExportRows = "invoice"
+	ExportRowsItem    ExportRows = "item"
+)
+
+type ExportStatus string
+
+const (
+	ExportStatusPending ExportStatus = "pending"
+	ExportStatusRunning ExportStatus = "running"
+	ExportStatusDone    ExportStatus = "done"
+	ExportStatusFailed  ExportStatus = "failed"
+)
+
+// InvoiceExport is an export run in the background, Filters holds the list filters as JSON.
+// FilePath is the key of its file in the blob store, the file is removed once ExpiresAt passes.
+type InvoiceExport struct {
+	ID         uint
+	AuthorID   uint
+	Format     ExportFormat
+	Rows       ExportRows
+	Locale     Locale
+	Filters    string
+	Status     ExportStatus
+	RowCount   int
+	FilePath   string
+	Error      string
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	ExpiresAt  *time.Time
+	Timestamp
+}
+
+func (InvoiceExport) TableName() string {
+	return "app.invoice_exports"
+}
+
+// FileName returns the name the export is downloaded as
+func (e *InvoiceExport) FileName() string {
+	return ExportFileName(e.Rows, e.Format, e.CreatedAt)
+}
+
+// ExportFileName names an export file after its rows and the time it was made
+func ExportFileName(rows ExportRows, format ExportFormat, t time.Time) string {
+	name := "invoices"
+	if rows == ExportRowsItem {
+		name = "invoice_items"
+	}
+	return fmt.Sprintf("%s_%s.%s", name, t.Format("20060102_150405"), format)
+}
+
+func (e *InvoiceExport) Response() InvoiceExportResponse {
+	return InvoiceExportResponse{
+		ID:         e.ID,
+		Format:     e.Format,
+		Rows:       e.Rows,
+		Locale:     e.Locale,
+		Status:     e.Status,
+		RowCount:   e.RowCount,
+		FileName:   e.FileName(),
+		Error:      e.Error,
+		FinishedAt: e.FinishedAt,
+		ExpiresAt:  e.ExpiresAt,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+type ExportCellKind int
+
+const (
+	ExportText ExportCellKind = iota
+	// ExportNumber holds Value in MinorUnits, e.g. an amount of money or a tax rate
+	ExportNumber
+	ExportDate
+)
+
+// ExportCell is one value of an export row, formats write numbers and dates their own way
+type ExportCell struct {
+	Kind       ExportCellKind
+	Text       string
+	Value      int
+	MinorUnits int
+	Date       time.Time
+}
+
+func textCell(s string) ExportCell {
+	return ExportCell{Kind: ExportText, Text: s}
+}
+
+func numberCell(v, minorUnits int) ExportCell {
+	return ExportCell{Kind: ExportNumber, Value: v, MinorUnits: minorUnits}
+}
+
+func dateCell(t time.Time) ExportCell {
+	return ExportCell{Kind: ExportDate, Date: t}
+}
+
+// Decimal prints a number cell with a dot before its minor units and no grouping, e.g. "1234.56"
+func (c ExportCell) Decimal() string {
+	if c.Value < 0 {
+		return "-" + formatDigits(-c.Value, c.MinorUnits, "", ".")
+	}
+	return formatDigits(c.Value, c.MinorUnits, "", ".")
+}
+
+// Format prints a cell for people reading the locale
+func (c ExportCell) Format(l Locale) string {
+	switch c.Kind {
+	case ExportNumber:
+		return l.FormatNumber(c.Value, c.MinorUnits)
+	case ExportDate:
+		return l.FormatDate(c.Date)
+	}
+	return c.Text
+}
+
+var invoiceExportHeader = []string{
+	"Number", "Invoice ID", "Status", "Issue Date", "Due Date", "Customer", "Customer Company", "Customer Email",
+	"Customer Tax ID", "Issuer", "Currency", "Subtotal", "Discount", "Tax", "Total", "Paid", "Credited", "Balance",
+	"Base Currency", "Base Total", "Note",
+}
+
+var invoiceItemExportHeader = []string{
+	"Number", "Invoice ID", "Status", "Issue Date", "Customer", "Currency", "Description", "Qty", "Price",
+	"Discount", "Tax", "Tax Rate (%)", "Subtotal", "Tax Amount", "Total",
+}
+
+// ExportHeader returns the column names of an export
+func ExportHeader(rows ExportRows) []string {
+	if rows == ExportRowsItem {
+		return invoiceItemExportHeader
+	}
+	return invoiceExportHeader
+}
+
+func currencyMinorUnits(code string) int {
+	c, _ := LookupCurrency(code)
+	return c.MinorUnits
+}
+
+// ExportRow returns the row of an invoice in an export with a row per invoice
+func (i *Invoice) ExportRow() []ExportCell {
+	minor := currencyMinorUnits(i.Currency)
+	issueDate, _ := time.Parse(time.DateOnly, i.IssueDate)
+	return []ExportCell{
+		textCell(i.Number),
+		textCell(i.PublicID),
+		textCell(string(i.Status)),
+		dateCell(issueDate),
+		dateCell(i.DueDate),
+		textCell(i.Customer),
+		textCell(i.CustomerCompany),
+		textCell(i.CustomerEmail),
+		textCell(i.CustomerTaxID),
+		textCell(i.Issuer),
+		textCell(i.Currency),
+		numberCell(i.Subtotal, minor),
+		numberCell(i.DiscountTotal, minor),
+		numberCell(i.TaxTotal, minor),
+		numberCell(i.Total, minor),
+		numberCell(i.AmountPaid, minor),
+		numberCell(i.AmountCredited, minor),
+		numberCell(i.Balance(), minor),
+		textCell(i.BaseCurrency),
+		numberCell(i.BaseTotal, currencyMinorUnits(i.BaseCurrency)),
+		textCell(i.Note),
+	}
+}
+
+// ExportItemRow returns the row of an invoice item in an export with a row per item
+func (i *Invoice) ExportItemRow(item *InvoiceItem) []ExportCell {
+	minor := currencyMinorUnits(i.Currency)
+	issueDate, _ := time.Parse(time.DateOnly, i.IssueDate)
+	return []ExportCell{
+		textCell(i.Number),
+		textCell(i.PublicID),
+		textCell(string(i.Status)),
+		dateCell(issueDate),
+		textCell(i.Customer),
+		textCell(i.Currency),
+		textCell(item.Description),
+		numberCell(item.Qty, 0),
+		numberCell(item.Price, minor),
+		numberCell(item.DiscountAmount+item.InvoiceDiscount, minor),
+		textCell(item.TaxName),
+		// rates are stored in basis points, two minor units of a percentage
+		numberCell(item.TaxRate, 2),
+		numberCell(item.Subtotal, minor),
+		numberCell(item.TaxAmount, minor),
+		numberCell(item.Total, minor),
+	}
+}
+
+// Normalize fills the defaults of an export and checks its list filters
+func (r *InvoiceExportRequest) Normalize() error {
+	if r.Rows == "" {
+		r.Rows = ExportRowsInvoice
+	}
+	if r.Locale == "" {
+		r.Locale = DefaultLocale
+	}
+	return r.InvoicePageReq.Normalize()
+}
+
+// Filters returns the list filters of an export request
+func (r *InvoiceExportRequest) Filters() InvoiceExportFilters {
+	return InvoiceExportFilters{
+		Search:     r.Search,
+		Status:     r.Status,
+		CustomerID: r.CustomerID,
+		IssueFrom:  r.IssueFrom,
+		IssueTo:    r.IssueTo,
+		DueFrom:    r.DueFrom,
+		DueTo:      r.DueTo,
+		MinTotal:   r.MinTotal,
+		MaxTotal:   r.MaxTotal,
+		Overdue:    r.Overdue,
+		Sort:       r.Sort,
+	}
+}
+
+// Request rebuilds the export request of a background export from its stored filters
+func (f InvoiceExportFilters) Request(e *InvoiceExport) InvoiceExportRequest {
+	return InvoiceExportRequest{
+		InvoicePageReq: InvoicePageReq{
+			PaginationRequest: PaginationRequest{Search: f.Search, UserID: e.AuthorID},
+			Status:            f.Status,
+			CustomerID:        f.CustomerID,
+			IssueFrom:         f.IssueFrom,
+			IssueTo:           f.IssueTo,
+			DueFrom:           f.DueFrom,
+			DueTo:             f.DueTo,
+			MinTotal:          f.MinTotal,
+			MaxTotal:          f.MaxTotal,
+			Overdue:           f.Overdue,
+			Sort:              f.Sort,
+		},
+		Format: e.Format,
+		Rows:   e.Rows,
+		Locale: e.Locale,
+	}
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// InvoiceExportRequest exports the invoices matching the filters of the invoice list
+type InvoiceExportRequest struct {
+	InvoicePageReq
+	Format ExportFormat `query:"format" validate:"required,oneof=csv xlsx"`
+	Rows   ExportRows   `query:"rows" validate:"omitempty,oneof=invoice item"`
+	Locale Locale       `query:"locale" validate:"omitempty,oneof=id-ID en-US"`
+}
+
+// InvoiceExportFilters is the part of an export request kept with a background export
+type InvoiceExportFilters struct {
+	Search     string `json:"search,omitempty"`
+	Status     string `json:"status,omitempty"`
+	CustomerID uint   `json:"customer_id,omitempty"`
+	IssueFrom  string `json:"issue_from,omitempty"`
+	IssueTo    string `json:"issue_to,omitempty"`
+	DueFrom    string `json:"due_from,omitempty"`
+	DueTo      string `json:"due_to,omitempty"`
+	MinTotal   *int   `json:"min_total,omitempty"`
+	MaxTotal   *int   `json:"max_total,omitempty"`
+	Overdue    bool   `json:"overdue,omitempty"`
+	Sort       string `json:"sort,omitempty"`
+}
+
+// InvoiceExportResponse represents background export output, the file can be downloaded once Status is done
+type InvoiceExportResponse struct {
+	ID         uint         `json:"id"`
+	Format     ExportFormat `json:"format"`
+	Rows       ExportRows   `json:"rows"`
+	Locale     Locale       `json:"locale"`
+	Status     ExportStatus `json:"status"`
+	RowCount   int          `json:"row_count"`
+	FileName   string       `json:"file_name"`
+	Error      string       `json:"error,omitempty"`
+	FinishedAt *time.Time   `json:"finished_at"`
+	ExpiresAt  *time.Time   `json:"expires_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
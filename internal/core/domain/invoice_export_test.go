@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocaleFormatNumber(t *testing.T) {
+	cases := []struct {
+		locale        Locale
+		amount, minor int
+		want          string
+	}{
+		{LocaleID, 123456, 2, "1.234,56"},
+		{LocaleEN, 123456, 2, "1,234.56"},
+		{LocaleID, 1500000, 0, "1.500.000"},
+		{LocaleEN, -5, 2, "-0.05"},
+	}
+	for _, c := range cases {
+		if got := c.locale.FormatNumber(c.amount, c.minor); got != c.want {
+			t.Errorf("%s FormatNumber(%d, %d) expected %q, got %q", c.locale, c.amount, c.minor, c.want, got)
+		}
+	}
+
+	date := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	if got := LocaleID.FormatDate(date); got != "10/03/2026" {
+		t.Errorf("expected id-ID date 10/03/2026, got %s", got)
+	}
+	if got := LocaleEN.FormatDate(date); got != "03/10/2026" {
+		t.Errorf("expected en-US date 03/10/2026, got %s", got)
+	}
+	if got := LocaleEN.FormatDate(time.Time{}); got != "" {
+		t.Errorf("expected zero date to print empty, got %s", got)
+	}
+}
+
+func TestInvoiceExportRows(t *testing.T) {
+	inv := Invoice{Number: "INV/2026/03/0001", IssueDate: "2026-03-10", Currency: "USD", Total: 123456, BaseCurrency: "IDR", BaseTotal: 20000000}
+	row := inv.ExportRow()
+	if len(row) != len(ExportHeader(ExportRowsInvoice)) {
+		t.Fatalf("expected %d cells, got %d", len(ExportHeader(ExportRowsInvoice)), len(row))
+	}
+	if got := row[14].Decimal(); got != "1234.56" {
+		t.Errorf("expected total 1234.56, got %s", got)
+	}
+	if got := row[19].Format(LocaleID); got != "20.000.000" {
+		t.Errorf("expected base total 20.000.000, got %s", got)
+	}
+
+	item := InvoiceItem{Description: "Consulting", Qty: 2, Price: 61728, TaxRate: 1100, Total: 123456}
+	if got := len(inv.ExportItemRow(&item)); got != len(ExportHeader(ExportRowsItem)) {
+		t.Fatalf("expected %d item cells, got %d", len(ExportHeader(ExportRowsItem)), got)
+	}
+	if got := inv.ExportItemRow(&item)[11].Format(LocaleEN); got != "11.00" {
+		t.Errorf("expected tax rate 11.00, got %s", got)
+	}
+}
@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxImportSize is the largest import file accepted, in bytes
+	MaxImportSize = 2 * 1024 * 1024
+	// MaxImportRows is the most item rows one import file may have
+	MaxImportRows = 5000
+	// maxImportRefLength is the longest reference an import row may have
+	maxImportRefLength = 100
+)
+
+type ImportStatus string
+
+const (
+	// ImportStatusReady is an invoice that would be created, by a dry run or an import rolled back by another invoice
+	ImportStatusReady     ImportStatus = "ready"
+	ImportStatusCreated   ImportStatus = "created"
+	ImportStatusDuplicate ImportStatus = "duplicate"
+	ImportStatusInvalid   ImportStatus = "invalid"
+	ImportStatusFailed    ImportStatus = "failed"
+)
+
+// importInvoiceColumns are the columns that describe the invoice, every row of a reference repeats them
+var importInvoiceColumns = []string{
+	"profile_id", "issuer", "customer", "customer_id", "issue_date", "due_date", "note",
+	"currency", "exchange_rate", "tax_mode", "tax_rate_id", "discount_type", "discount",
+}
+
+// importItemColumns are the columns that describe the item of a row
+var importItemColumns = []string{
+	"description", "qty", "price", "item_discount_type", "item_discount", "item_tax_rate_id",
+}
+
+// importRequiredColumns must be in the header of every import file
+var importRequiredColumns = []string{"reference", "issue_date", "description", "qty", "price"}
+
+// InvoiceImport groups the rows of an import file into invoices by their reference.
+// Values are taken as they are sent to the invoice API, amounts in minor units and dates as yyyy-mm-dd.
+type InvoiceImport struct {
+	Groups  []InvoiceImportGroup
+	Rows    int
+	columns map[string]int
+	index   map[string]int
+}
+
+// InvoiceImportGroup is the invoice of one reference, Lines holds the file line of each item
+type InvoiceImportGroup struct {
+	Reference string
+	Request   InvoiceRequest
+	Lines     []int
+	Errors    []InvoiceImportError
+	// invoice is the invoice columns of the first row, the other rows must match it
+	invoice []string
+}
+
+// NewInvoiceImport reads the header row of an import file, column names are matched case insensitively
+func NewInvoiceImport(header []string) (*InvoiceImport, error) {
+	known := map[string]bool{"reference": true}
+	for _, v := range append(importInvoiceColumns, importItemColumns...) {
+		known[v] = true
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, v := range header {
+		name := strings.ToLower(strings.TrimSpace(v))
+		if _, dup := columns[name]; !known[name] || dup {
+			return nil, fmt.Errorf(ErrInvalidImportFile)
+		}
+		columns[name] = i
+	}
+	for _, v := range importRequiredColumns {
+		if _, ok := columns[v]; !ok {
+			return nil, fmt.Errorf(ErrInvalidImportFile)
+		}
+	}
+
+	return &InvoiceImport{columns: columns, index: map[string]int{}}, nil
+}
+
+// Add puts a row into the invoice of its reference, errors found while reading it are kept on the group
+func (im *InvoiceImport) Add(line int, record []string) {
+	im.Rows++
+	value := func(column string) string {
+		i, ok := im.columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	ref := value("reference")
+	i, ok := im.index[ref]
+	if !ok {
+		i = len(im.Groups)
+		im.index[ref] = i
+		im.Groups = append(im.Groups, InvoiceImportGroup{Reference: ref})
+	}
+	g := &im.Groups[i]
+
+	var errs []string
+	switch {
+	case ref == "":
+		errs = append(errs, "'reference' is required")
+	case len(ref) > maxImportRefLength:
+		errs = append(errs, fmt.Sprintf("maximum field 'reference' is %d characters", maxImportRefLength))
+	}
+
+	invoice := make([]string, len(importInvoiceColumns))
+	for j, v := range importInvoiceColumns {
+		invoice[j] = value(v)
+	}
+	if g.invoice == nil {
+		g.invoice = invoice
+		g.Request = InvoiceRequest{
+			Issuer:       value("issuer"),
+			Customer:     value("customer"),
+			IssueDate:    value("issue_date"),
+			DueDate:      value("due_date"),
+			Note:         value("note"),
+			Currency:     value("currency"),
+			ExchangeRate: value("exchange_rate"),
+			TaxMode:      TaxMode(value("tax_mode")),
+			DiscountType: DiscountType(value("discount_type")),
+			ImportRef:    ref,
+		}
+		g.Request.ProfileID = importUint(value, "profile_id", &errs)
+		g.Request.CustomerID = importUint(value, "customer_id", &errs)
+		g.Request.TaxRateID = importUint(value, "tax_rate_id", &errs)
+		g.Request.Discount = importInt(value, "discount", &errs)
+	} else {
+		for j, v := range importInvoiceColumns {
+			if invoice[j] != g.invoice[j] {
+				errs = append(errs, fmt.Sprintf("'%s' differs from the first row of reference '%s'", v, ref))
+			}
+		}
+	}
+
+	item := InvoiceItemRequest{
+		Description:  value("description"),
+		DiscountType: DiscountType(value("item_discount_type")),
+	}
+	item.Qty = importInt(value, "qty", &errs)
+	item.Price = importInt(value, "price", &errs)
+	item.Discount = importInt(value, "item_discount", &errs)
+	item.TaxRateID = importUint(value, "item_tax_rate_id", &errs)
+
+	g.Request.Items = append(g.Request.Items, item)
+	g.Lines = append(g.Lines, line)
+	g.AddErrors(line, errs)
+}
+
+// AddErrors records the errors of a line of the group, a line without errors is left out
+func (g *InvoiceImportGroup) AddErrors(line int, errs []string) {
+	if len(errs) == 0 {
+		return
+	}
+	for i := range g.Errors {
+		if g.Errors[i].Line == line {
+			g.Errors[i].Errors = append(g.Errors[i].Errors, errs...)
+			return
+		}
+	}
+	g.Errors = append(g.Errors, InvoiceImportError{Line: line, Reference: g.Reference, Errors: errs})
+}
+
+// Result reports the outcome of the group, the invoice ID is only known once it is created
+func (g *InvoiceImportGroup) Result(status ImportStatus, invoiceID, message string) InvoiceImportResult {
+	return InvoiceImportResult{
+		Reference: g.Reference,
+		Line:      g.Lines[0],
+		Items:     len(g.Lines),
+		Status:    status,
+		InvoiceID: invoiceID,
+		Error:     message,
+	}
+}
+
+// importInt reads a whole number column, an empty column is zero
+func importInt(value func(string) string, column string, errs *[]string) int {
+	v := value(column)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("'%s' must be a whole number", column))
+	}
+	return n
+}
+
+// importUint reads an optional ID column, an empty column is nil
+func importUint(value func(string) string, column string, errs *[]string) *uint {
+	v := value(column)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("'%s' must be a whole number", column))
+		return nil
+	}
+	id := uint(n)
+	return &id
+}
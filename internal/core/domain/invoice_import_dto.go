@@ -0,0 +1,36 @@
+package domain
+
+// InvoiceImportRequest represents an uploaded CSV file of invoices, one row per item
+type InvoiceImportRequest struct {
+	DryRun  bool   `query:"dry_run"`
+	Content []byte `query:"-"`
+	UserID  uint   `query:"-"`
+}
+
+// InvoiceImportError lists what is wrong with one line of an import file
+type InvoiceImportError struct {
+	Line      int      `json:"line"`
+	Reference string   `json:"reference"`
+	Errors    []string `json:"errors"`
+}
+
+// InvoiceImportResult represents the outcome of one invoice of an import file
+type InvoiceImportResult struct {
+	Reference string       `json:"reference"`
+	Line      int          `json:"line"`
+	Items     int          `json:"items"`
+	Status    ImportStatus `json:"status"`
+	InvoiceID string       `json:"invoice_id,omitempty"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// InvoiceImportResponse represents the report of an import, nothing is created while any line has errors
+type InvoiceImportResponse struct {
+	DryRun    bool                  `json:"dry_run"`
+	Rows      int                   `json:"rows"`
+	Invoices  int                   `json:"invoices"`
+	Created   int                   `json:"created"`
+	Duplicate int                   `json:"duplicate"`
+	Results   []InvoiceImportResult `json:"results"`
+	Errors    []InvoiceImportError  `json:"errors"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "testing"
+
+func TestNewInvoiceImportHeader(t *testing.T) {
+	if _, err := NewInvoiceImport([]string{"Reference", " issue_date", "DESCRIPTION", "qty", "price"}); err != nil {
+		t.Fatalf("expected header to be accepted, got %v", err)
+	}
+
+	invalid := [][]string{
+		{"reference", "issue_date", "description", "qty"},
+		{"reference", "issue_date", "description", "qty", "price", "amount"},
+		{"reference", "issue_date", "description", "qty", "price", "qty"},
+	}
+	for _, header := range invalid {
+		if _, err := NewInvoiceImport(header); err == nil || err.Error() != ErrInvalidImportFile {
+			t.Errorf("expected header %v to be rejected, got %v", header, err)
+		}
+	}
+}
+
+func TestInvoiceImportGroups(t *testing.T) {
+	im, err := NewInvoiceImport([]string{"reference", "customer", "customer_id", "issue_date", "description", "qty", "price"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	im.Add(2, []string{"A-1", "Budi", "7", "2026-03-10", "Design", "1", "150000"})
+	im.Add(3, []string{"B-1", "Andi", "", "2026-03-11", "Hosting", "2", "50000"})
+	im.Add(4, []string{"A-1", "Budi", "7", "2026-03-10", "Development", "3", "x"})
+	im.Add(5, []string{"A-1", "Budi", "8", "2026-03-10", "Support", "1", "10000"})
+
+	if im.Rows != 4 || len(im.Groups) != 2 {
+		t.Fatalf("expected 4 rows in 2 invoices, got %d rows in %d invoices", im.Rows, len(im.Groups))
+	}
+
+	a := im.Groups[0]
+	if a.Reference != "A-1" || a.Request.ImportRef != "A-1" || len(a.Request.Items) != 3 {
+		t.Fatalf("expected reference A-1 with 3 items, got %+v", a)
+	}
+	if a.Request.CustomerID == nil || *a.Request.CustomerID != 7 || a.Request.Items[0].Price != 150000 {
+		t.Errorf("expected invoice columns of the first row, got %+v", a.Request)
+	}
+	if len(a.Errors) != 2 || a.Errors[0].Line != 4 || a.Errors[1].Line != 5 {
+		t.Fatalf("expected errors on lines 4 and 5, got %+v", a.Errors)
+	}
+	if a.Errors[0].Errors[0] != "'price' must be a whole number" {
+		t.Errorf("unexpected error on line 4: %v", a.Errors[0].Errors)
+	}
+
+	if b := im.Groups[1]; len(b.Errors) != 0 || b.Request.CustomerID != nil || b.Lines[0] != 3 {
+		t.Errorf("expected reference B-1 on line 3 without errors, got %+v", b)
+	}
+}
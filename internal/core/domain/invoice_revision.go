@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RevisionEvent names the change that produced a revision, status changes use the new status
+type RevisionEvent string
+
+const (
+	RevisionCreated RevisionEvent = "created"
+	RevisionUpdated RevisionEvent = "updated"
+)
+
+// revisionIgnored are snapshot fields that change on every write without changing what the invoice says
+var revisionIgnored = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"version":    true,
+}
+
+// revisionIndex matches the list indexes of a snapshot path
+var revisionIndex = regexp.MustCompile(`\[\d+\]`)
+
+// InvoiceRevision is an immutable snapshot of an invoice as it was after a change.
+// Revisions are numbered per invoice from 1 and are never updated, Snapshot holds the invoice as JSON.
+type InvoiceRevision struct {
+	ID        uint
+	AuthorID  uint
+	InvoiceID int64
+	Revision  int
+	Event     RevisionEvent
+	ActorID   uint
+	Snapshot  string
+	CreatedAt time.Time
+}
+
+func (InvoiceRevision) TableName() string {
+	return "app.invoice_revisions"
+}
+
+// NewInvoiceRevision captures an invoice with its items and taxes, the revision number is given when it is stored
+func NewInvoiceRevision(inv *Invoice, items []InvoiceItem, taxes []InvoiceTax, actorID uint, event RevisionEvent, t time.Time) (InvoiceRevision, error) {
+	snapshot, err := json.Marshal(inv.Response(items, taxes))
+	if err != nil {
+		return InvoiceRevision{}, err
+	}
+	return InvoiceRevision{
+		AuthorID:  inv.AuthorID,
+		InvoiceID: inv.ID,
+		Event:     event,
+		ActorID:   actorID,
+		Snapshot:  string(snapshot),
+		CreatedAt: t,
+	}, nil
+}
+
+// Invoice returns the invoice as it was in the revision
+func (r *InvoiceRevision) Invoice() (InvoiceResponse, error) {
+	var res InvoiceResponse
+	err := json.Unmarshal([]byte(r.Snapshot), &res)
+	return res, err
+}
+
+// Response leaves the snapshot out, it tells who changed the invoice when
+func (r *InvoiceRevision) Response() InvoiceRevisionResponse {
+	return InvoiceRevisionResponse{
+		Revision:  r.Revision,
+		Event:     r.Event,
+		ActorID:   r.ActorID,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// DiffRevisions lists the fields that differ between two revisions by their JSON path, e.g. items[1].qty.
+// A field only in one of the revisions, like an added item, has a nil value on the other side.
+func DiffRevisions(from, to *InvoiceRevision) ([]RevisionChange, error) {
+	var a, b any
+	if err := json.Unmarshal([]byte(from.Snapshot), &a); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(to.Snapshot), &b); err != nil {
+		return nil, err
+	}
+
+	before, after := map[string]any{}, map[string]any{}
+	flattenSnapshot("", a, before)
+	flattenSnapshot("", b, after)
+
+	paths := make([]string, 0, len(after))
+	for k := range after {
+		paths = append(paths, k)
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			paths = append(paths, k)
+		}
+	}
+	// Items are listed in their order, items[10] after items[9]
+	slices.SortFunc(paths, func(x, y string) int {
+		return strings.Compare(revisionIndex.ReplaceAllStringFunc(x, padRevisionIndex), revisionIndex.ReplaceAllStringFunc(y, padRevisionIndex))
+	})
+
+	changes := []RevisionChange{}
+	for _, k := range paths {
+		if !reflect.DeepEqual(before[k], after[k]) {
+			changes = append(changes, RevisionChange{Field: k, From: before[k], To: after[k]})
+		}
+	}
+	return changes, nil
+}
+
+// padRevisionIndex pads a list index so paths sort by number
+func padRevisionIndex(index string) string {
+	return fmt.Sprintf("[%08s]", index[1:len(index)-1])
+}
+
+// flattenSnapshot puts the leaves of a decoded JSON document into out by path, empty objects and lists are left out
+func flattenSnapshot(path string, v any, out map[string]any) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if revisionIgnored[k] {
+				continue
+			}
+			if path != "" {
+				k = path + "." + k
+			}
+			flattenSnapshot(k, child, out)
+		}
+	case []any:
+		for i, child := range t {
+			flattenSnapshot(fmt.Sprintf("%s[%d]", path, i), child, out)
+		}
+	default:
+		out[path] = v
+	}
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// InvoiceRevisionDiffRequest compares two revisions of an invoice, To defaults to the latest revision
+type InvoiceRevisionDiffRequest struct {
+	From int `query:"from" validate:"required,min=1"`
+	To   int `query:"to" validate:"omitempty,min=1"`
+}
+
+// InvoiceRevisionResponse represents a revision output, Invoice is only set when a single revision is asked for
+type InvoiceRevisionResponse struct {
+	Revision  int              `json:"revision"`
+	Event     RevisionEvent    `json:"event"`
+	ActorID   uint             `json:"actor_id"`
+	CreatedAt time.Time        `json:"created_at"`
+	Invoice   *InvoiceResponse `json:"invoice,omitempty"`
+}
+
+// RevisionChange represents one field that differs between two revisions
+type RevisionChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// InvoiceRevisionDiffResponse represents the changes from one revision of an invoice to another
+type InvoiceRevisionDiffResponse struct {
+	From    InvoiceRevisionResponse `json:"from"`
+	To      InvoiceRevisionResponse `json:"to"`
+	Changes []RevisionChange        `json:"changes"`
+}
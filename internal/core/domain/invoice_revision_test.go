@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	inv := Invoice{ID: 1, PublicID: "abc", AuthorID: 2, Customer: "Budi", Status: InvoiceStatusDraft, Total: 1000}
+	items := make([]InvoiceItem, 10)
+	for i := range items {
+		items[i] = InvoiceItem{ID: uint(i + 1), Description: "Design", Qty: 1, Price: 100}
+	}
+	from, err := NewInvoiceRevision(&inv, items, nil, 2, RevisionCreated, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inv.Customer = "Andi"
+	inv.UpdatedAt = time.Now()
+	items[9].Qty = 3
+	items = append(items, InvoiceItem{ID: 11, Description: "Hosting", Qty: 1, Price: 50})
+	for i := range items {
+		items[i].CreatedAt = time.Now()
+	}
+	to, err := NewInvoiceRevision(&inv, items, nil, 2, RevisionUpdated, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := DiffRevisions(&from, &to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"customer", "items[9].qty", "items[10].description", "items[10].discount", "items[10].discount_amount"}
+	if len(changes) < len(want) {
+		t.Fatalf("expected at least %d changes, got %+v", len(want), changes)
+	}
+	for i, v := range want {
+		if changes[i].Field != v {
+			t.Errorf("expected change %d on %s, got %s", i, v, changes[i].Field)
+		}
+	}
+	if changes[0].From != "Budi" || changes[0].To != "Andi" {
+		t.Errorf("expected customer Budi to Andi, got %v to %v", changes[0].From, changes[0].To)
+	}
+	if changes[2].From != nil || changes[2].To != "Hosting" {
+		t.Errorf("expected added item without previous value, got %v to %v", changes[2].From, changes[2].To)
+	}
+
+	same, err := DiffRevisions(&to, &to)
+	if err != nil || len(same) != 0 {
+		t.Errorf("expected no changes between the same revision, got %+v, %v", same, err)
+	}
+}
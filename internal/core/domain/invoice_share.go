@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// InvoiceShare lets a customer open an invoice without an account through a signed token.
+// The token only carries the share ID, so a share can be revoked before it expires.
+type InvoiceShare struct {
+	ID            uint
+	AuthorID      uint
+	InvoiceID     int64
+	ExpiresAt     time.Time
+	RevokedAt     *time.Time
+	FirstViewedAt *time.Time
+	LastViewedAt  *time.Time
+	ViewCount     int
+	Timestamp
+}
+
+func (InvoiceShare) TableName() string {
+	return "app.invoice_shares"
+}
+
+// Active reports whether the share can still be opened at now
+func (s *InvoiceShare) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Response takes the public ID of the shared invoice, token is only known right after the share is created
+func (s *InvoiceShare) Response(invoiceID, token string, now time.Time) InvoiceShareResponse {
+	return InvoiceShareResponse{
+		ID:            s.ID,
+		InvoiceID:     invoiceID,
+		Token:         token,
+		ExpiresAt:     s.ExpiresAt,
+		RevokedAt:     s.RevokedAt,
+		Active:        s.Active(now),
+		FirstViewedAt: s.FirstViewedAt,
+		LastViewedAt:  s.LastViewedAt,
+		ViewCount:     s.ViewCount,
+		CreatedAt:     s.CreatedAt,
+	}
+}
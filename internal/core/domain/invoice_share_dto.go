@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// InvoiceShareRequest represents share link input, the link expires after the configured period unless ExpiresAt is given
+type InvoiceShareRequest struct {
+	InvoiceID string `json:"-"`
+	ExpiresAt string `json:"expires_at" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+	UserID    uint   `json:"-"`
+}
+
+// InvoiceShareResponse represents share link output
+type InvoiceShareResponse struct {
+	ID            uint       `json:"id"`
+	InvoiceID     string     `json:"invoice_id"`
+	Token         string     `json:"token,omitempty"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at"`
+	Active        bool       `json:"active"`
+	FirstViewedAt *time.Time `json:"first_viewed_at"`
+	LastViewedAt  *time.Time `json:"last_viewed_at"`
+	ViewCount     int        `json:"view_count"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInvoiceShareActive(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	revoked := now.Add(-time.Hour)
+	cases := []struct {
+		name  string
+		share InvoiceShare
+		want  bool
+	}{
+		{"not expired", InvoiceShare{ExpiresAt: now.Add(time.Minute)}, true},
+		{"expired", InvoiceShare{ExpiresAt: now}, false},
+		{"revoked", InvoiceShare{ExpiresAt: now.Add(time.Hour), RevokedAt: &revoked}, false},
+	}
+	for _, c := range cases {
+		if got := c.share.Active(now); got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInvoicePageReqNormalize(t *testing.T) {
+	req := InvoicePageReq{Status: "sent, overdue", Sort: "-due_date"}
+	if err := req.Normalize(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []InvoiceStatus{InvoiceStatusSent, InvoiceStatusOverdue}; !reflect.DeepEqual(req.Statuses, want) {
+		t.Errorf("expected statuses %v, got %v", want, req.Statuses)
+	}
+	if req.SortField != "due_date" || !req.SortDesc {
+		t.Errorf("expected due_date descending, got %s desc=%v", req.SortField, req.SortDesc)
+	}
+
+	req = InvoicePageReq{}
+	if err := req.Normalize(); err != nil || req.SortField != "created_at" || !req.SortDesc {
+		t.Errorf("expected newest first by default, got %s desc=%v err=%v", req.SortField, req.SortDesc, err)
+	}
+
+	min, max := 500, 100
+	invalid := []InvoicePageReq{
+		{Status: "unpaid"},
+		{Sort: "author_id"},
+		{Sort: "total; DROP TABLE app.invoices"},
+		{IssueFrom: "2026-02-01", IssueTo: "2026-01-31"},
+		{MinTotal: &min, MaxTotal: &max},
+	}
+	for _, v := range invalid {
+		if err := v.Normalize(); err == nil {
+			t.Errorf("expected %+v to be rejected", v)
+		}
+	}
+}
+
+func TestParseIfMatch(t *testing.T) {
+	cases := []struct {
+		header  string
+		version int
+		err     string
+	}{
+		{"", 0, ""},
+		{"*", 0, ""},
+		{`"3"`, 3, ""},
+		{` "12" `, 12, ""},
+		{InvoiceETag(7), 7, ""},
+		{` W/"12" `, 0, ErrInvoiceVersionMismatch},
+		{"3", 0, ErrInvalidIfMatch},
+		{`"0"`, 0, ErrInvalidIfMatch},
+		{`"abc"`, 0, ErrInvalidIfMatch},
+		{`"1", "2"`, 0, ErrInvalidIfMatch},
+	}
+	for _, c := range cases {
+		version, err := ParseIfMatch(c.header)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != c.err || version != c.version {
+			t.Errorf("ParseIfMatch(%q) expected %d, error %q, got %d, %v", c.header, c.version, c.err, version, err)
+		}
+	}
+}
+
+func TestInvoiceSettledStatus(t *testing.T) {
+	due := time.Date(2026, 3, 24, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name       string
+		amountPaid int
+		now        time.Time
+		status     InvoiceStatus
+	}{
+		{"before due", 0, due.AddDate(0, 0, -1), InvoiceStatusSent},
+		{"due today", 0, due.Add(15 * time.Hour), InvoiceStatusSent},
+		{"day after due", 0, due.AddDate(0, 0, 1), InvoiceStatusOverdue},
+		{"partially paid late", 400, due.AddDate(0, 0, 1), InvoiceStatusPartiallyPaid},
+		{"paid", 1000, due.AddDate(0, 0, 1), InvoiceStatusPaid},
+	}
+	for _, c := range cases {
+		inv := Invoice{Total: 1000, AmountPaid: c.amountPaid, DueDate: due}
+		if got := inv.SettledStatus(c.now); got != c.status {
+			t.Errorf("%s: expected %s, got %s", c.name, c.status, got)
+		}
+	}
+}
+
+func TestInvoiceStatusCanTransitionTo(t *testing.T) {
+	statuses := []InvoiceStatus{
+		InvoiceStatusDraft,
+		InvoiceStatusSent,
+		InvoiceStatusPartiallyPaid,
+		InvoiceStatusPaid,
+		InvoiceStatusOverdue,
+		InvoiceStatusVoid,
+	}
+	allowed := map[[2]InvoiceStatus]bool{
+		{InvoiceStatusDraft, InvoiceStatusSent}:            true,
+		{InvoiceStatusDraft, InvoiceStatusVoid}:            true,
+		{InvoiceStatusSent, InvoiceStatusPartiallyPaid}:    true,
+		{InvoiceStatusSent, InvoiceStatusPaid}:             true,
+		{InvoiceStatusSent, InvoiceStatusOverdue}:          true,
+		{InvoiceStatusSent, InvoiceStatusVoid}:             true,
+		{InvoiceStatusPartiallyPaid, InvoiceStatusPaid}:    true,
+		{InvoiceStatusPartiallyPaid, InvoiceStatusOverdue}: true,
+		{InvoiceStatusOverdue, InvoiceStatusPartiallyPaid}: true,
+		{InvoiceStatusOverdue, InvoiceStatusPaid}:          true,
+		{InvoiceStatusOverdue, InvoiceStatusVoid}:          true,
+	}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]InvoiceStatus{from, to}]
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s -> %s: expected %v, got %v", from, to, want, got)
+			}
+		}
+	}
+	if InvoiceStatusDraft.CanTransitionTo("archived") || InvoiceStatus("archived").CanTransitionTo(InvoiceStatusSent) {
+		t.Errorf("expected unknown statuses to be refused")
+	}
+}
+
+func TestInvoiceStatusIsDeletable(t *testing.T) {
+	cases := map[InvoiceStatus]bool{
+		InvoiceStatusDraft:         true,
+		InvoiceStatusSent:          false,
+		InvoiceStatusPartiallyPaid: false,
+		InvoiceStatusPaid:          false,
+		InvoiceStatusOverdue:       false,
+		InvoiceStatusVoid:          true,
+	}
+	for status, want := range cases {
+		if got := status.IsDeletable(); got != want {
+			t.Errorf("%s: expected %v, got %v", status, want, got)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Locale selects how numbers and dates are written for people, e.g. in exports and PDFs
+type Locale string
+
+const (
+	LocaleID Locale = "id-ID"
+	LocaleEN Locale = "en-US"
+)
+
+// DefaultLocale is used when a request does not ask for one
+const DefaultLocale = LocaleID
+
+type localeFormat struct {
+	thousandSep string
+	decimalSep  string
+	date        string
+	// listSep separates values in CSV files, spreadsheets of locales with a decimal comma expect a semicolon
+	listSep rune
+	months  [12]string
+}
+
+var localeFormats = map[Locale]localeFormat{
+	LocaleID: {thousandSep: ".", decimalSep: ",", date: "02/01/2006", listSep: ';', months: [12]string{
+		"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember",
+	}},
+	LocaleEN: {thousandSep: ",", decimalSep: ".", date: "01/02/2006", listSep: ',', months: [12]string{
+		"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December",
+	}},
+}
+
+func (l Locale) format() localeFormat {
+	if f, ok := localeFormats[l]; ok {
+		return f
+	}
+	return localeFormats[DefaultLocale]
+}
+
+// FormatNumber prints an amount given in minor units without a currency symbol, e.g. 123456 with 2 minor units
+// as "1.234,56" in id-ID and "1,234.56" in en-US
+func (l Locale) FormatNumber(amount, minorUnits int) string {
+	f := l.format()
+	if amount < 0 {
+		return "-" + formatDigits(-amount, minorUnits, f.thousandSep, f.decimalSep)
+	}
+	return formatDigits(amount, minorUnits, f.thousandSep, f.decimalSep)
+}
+
+// FormatDate prints a date the way the locale writes it, a zero time prints as empty
+func (l Locale) FormatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(l.format().date)
+}
+
+// FormatLongDate prints a date with the month name, e.g. "16 Oktober 2026" in id-ID and "October 16, 2026" in en-US
+func (l Locale) FormatLongDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	f := l.format()
+	month := f.months[t.Month()-1]
+	if l.DateLayout() == "mm/dd/yyyy" {
+		return fmt.Sprintf("%s %d, %d", month, t.Day(), t.Year())
+	}
+	return fmt.Sprintf("%d %s %d", t.Day(), month, t.Year())
+}
+
+// FormatMoney prints an amount in minor units of the currency code with the separators of the locale,
+// e.g. 123456 USD as "$ 1.234,56" in id-ID. Unknown codes print the bare number.
+func (l Locale) FormatMoney(amount int, code string) string {
+	c, ok := LookupCurrency(code)
+	if !ok {
+		return l.FormatNumber(amount, 0)
+	}
+	if amount < 0 {
+		return "-" + c.Symbol + " " + l.FormatNumber(-amount, c.MinorUnits)
+	}
+	return c.Symbol + " " + l.FormatNumber(amount, c.MinorUnits)
+}
+
+// FormatTaxRate prints a rate in hundredths of a percent, e.g. 1150 as "11,5%" in id-ID
+func (l Locale) FormatTaxRate(rate int) string {
+	return strings.Replace(FormatTaxRate(rate), ".", l.format().decimalSep, 1)
+}
+
+// FormatDecimal prints a decimal string such as a stored exchange rate, e.g. "16250.5" as "16.250,5" in id-ID.
+// Values that are not plain decimals are returned as they are.
+func (l Locale) FormatDecimal(value string) string {
+	sign, digits := "", value
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" || strings.Trim(whole+fraction, "0123456789") != "" {
+		return value
+	}
+
+	f := l.format()
+	res := sign + groupDigits(whole, f.thousandSep)
+	if fraction != "" {
+		res += f.decimalSep + fraction
+	}
+	return res
+}
+
+// DateLayout returns the day, month and year order of the locale as a spreadsheet number format
+func (l Locale) DateLayout() string {
+	if l.format().date == "01/02/2006" {
+		return "mm/dd/yyyy"
+	}
+	return "dd/mm/yyyy"
+}
+
+// ListSeparator returns the field separator spreadsheets of the locale expect in CSV files
+func (l Locale) ListSeparator() rune {
+	return l.format().listSep
+}
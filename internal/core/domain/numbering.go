@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DocumentType names a kind of numbered document, each has its own scheme and sequence
+type DocumentType string
+
+const (
+	DocumentInvoice    DocumentType = "invoice"
+	DocumentCreditNote DocumentType = "credit_note"
+	DocumentQuote      DocumentType = "quote"
+)
+
+type NumberingReset string
+
+const (
+	NumberingResetYearly  NumberingReset = "yearly"
+	NumberingResetMonthly NumberingReset = "monthly"
+	NumberingResetNever   NumberingReset = "never"
+)
+
+// maxSequenceWidth is the widest zero padding a {SEQ:n} token may ask for
+const maxSequenceWidth = 10
+
+// defaultNumberingSchemes is used for accounts that did not configure a scheme
+var defaultNumberingSchemes = map[DocumentType]NumberingScheme{
+	DocumentInvoice:    {Document: DocumentInvoice, Template: "INV/{YYYY}/{MM}/{SEQ:4}", Reset: NumberingResetMonthly},
+	DocumentCreditNote: {Document: DocumentCreditNote, Template: "CN/{YYYY}/{MM}/{SEQ:4}", Reset: NumberingResetMonthly},
+	DocumentQuote:      {Document: DocumentQuote, Template: "QUO/{YYYY}/{MM}/{SEQ:4}", Reset: NumberingResetMonthly},
+}
+
+// numberingToken matches the placeholders of a numbering template
+var numberingToken = regexp.MustCompile(`\{([A-Z]+)(?::(\d+))?\}`)
+
+// NumberingScheme formats the human facing numbers of a document type, e.g. INV/{YYYY}/{MM}/{SEQ:5}.
+// The sequence restarts every period of Reset and is counted per account.
+type NumberingScheme struct {
+	ID       uint
+	AuthorID uint
+	Document DocumentType
+	Template string
+	Reset    NumberingReset
+	Timestamp
+}
+
+func (NumberingScheme) TableName() string {
+	return "app.numbering_schemes"
+}
+
+// DefaultNumberingScheme returns the scheme of a document type for an account without one
+func DefaultNumberingScheme(authorID uint, document DocumentType) (NumberingScheme, bool) {
+	scheme, ok := defaultNumberingSchemes[document]
+	scheme.AuthorID = authorID
+	return scheme, ok
+}
+
+// Validate checks that a template has exactly one sequence and the date parts its reset period needs,
+// so numbers never repeat across periods.
+func (n *NumberingScheme) Validate() error {
+	var seq int
+	tokens := map[string]bool{}
+	for _, m := range numberingToken.FindAllStringSubmatch(n.Template, -1) {
+		switch m[1] {
+		case "SEQ":
+			seq++
+			if m[2] != "" {
+				width, _ := strconv.Atoi(m[2])
+				if width < 1 || width > maxSequenceWidth {
+					return fmt.Errorf(ErrInvalidNumberingTemplate)
+				}
+			}
+		case "YYYY", "YY", "MM", "DD":
+			if m[2] != "" {
+				return fmt.Errorf(ErrInvalidNumberingTemplate)
+			}
+		default:
+			return fmt.Errorf(ErrInvalidNumberingTemplate)
+		}
+		tokens[m[1]] = true
+	}
+
+	year := tokens["YYYY"] || tokens["YY"]
+	switch {
+	case seq != 1:
+		return fmt.Errorf(ErrInvalidNumberingTemplate)
+	case n.Reset == NumberingResetYearly && !year:
+		return fmt.Errorf(ErrInvalidNumberingTemplate)
+	case n.Reset == NumberingResetMonthly && (!year || !tokens["MM"]):
+		return fmt.Errorf(ErrInvalidNumberingTemplate)
+	}
+	return nil
+}
+
+// Period returns the key of the sequence a document dated date counts in
+func (n *NumberingScheme) Period(date time.Time) string {
+	switch n.Reset {
+	case NumberingResetYearly:
+		return date.Format("2006")
+	case NumberingResetMonthly:
+		return date.Format("2006-01")
+	}
+	return ""
+}
+
+// Format renders the number of the seq-th document of a period.
+// A sequence wider than its padding is printed in full, it never wraps around.
+func (n *NumberingScheme) Format(date time.Time, seq int64) string {
+	return numberingToken.ReplaceAllStringFunc(n.Template, func(token string) string {
+		m := numberingToken.FindStringSubmatch(token)
+		switch m[1] {
+		case "YYYY":
+			return date.Format("2006")
+		case "YY":
+			return date.Format("06")
+		case "MM":
+			return date.Format("01")
+		case "DD":
+			return date.Format("02")
+		case "SEQ":
+			width, _ := strconv.Atoi(m[2])
+			s := strconv.FormatInt(seq, 10)
+			if len(s) < width {
+				s = strings.Repeat("0", width-len(s)) + s
+			}
+			return s
+		}
+		return token
+	})
+}
+
+func (n *NumberingScheme) Response(next string) NumberingSchemeResponse {
+	return NumberingSchemeResponse{
+		Document:   n.Document,
+		Template:   n.Template,
+		Reset:      n.Reset,
+		NextNumber: next,
+	}
+}
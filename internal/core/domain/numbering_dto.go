@@ -0,0 +1,17 @@
+package domain
+
+// NumberingSchemeRequest represents numbering scheme input, the document comes from the path
+type NumberingSchemeRequest struct {
+	Document DocumentType   `json:"-"`
+	Template string         `json:"template" validate:"required,min=1,max=100"`
+	Reset    NumberingReset `json:"reset" validate:"required,oneof=yearly monthly never"`
+	UserID   uint           `json:"-"`
+}
+
+// NumberingSchemeResponse represents numbering scheme output with the number the next document gets today
+type NumberingSchemeResponse struct {
+	Document   DocumentType   `json:"document"`
+	Template   string         `json:"template"`
+	Reset      NumberingReset `json:"reset"`
+	NextNumber string         `json:"next_number"`
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNumberingSchemeFormat(t *testing.T) {
+	date := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		template string
+		seq      int64
+		want     string
+	}{
+		{"INV/{YYYY}/{MM}/{SEQ:5}", 42, "INV/2026/03/00042"},
+		{"{YY}{MM}{DD}-{SEQ}", 7, "260307-7"},
+		{"INV-{SEQ:3}", 12345, "INV-12345"},
+	}
+	for _, c := range cases {
+		n := NumberingScheme{Template: c.template}
+		if got := n.Format(date, c.seq); got != c.want {
+			t.Fatalf("Format(%q, %d) expected %q, got %q", c.template, c.seq, c.want, got)
+		}
+	}
+}
+
+func TestNumberingSchemePeriod(t *testing.T) {
+	date := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	cases := map[NumberingReset]string{
+		NumberingResetYearly:  "2026",
+		NumberingResetMonthly: "2026-03",
+		NumberingResetNever:   "",
+	}
+	for reset, want := range cases {
+		n := NumberingScheme{Reset: reset}
+		if got := n.Period(date); got != want {
+			t.Fatalf("Period with %s reset expected %q, got %q", reset, want, got)
+		}
+	}
+}
+
+func TestNumberingSchemeValidate(t *testing.T) {
+	cases := []struct {
+		template string
+		reset    NumberingReset
+		valid    bool
+	}{
+		{"INV/{YYYY}/{MM}/{SEQ:5}", NumberingResetMonthly, true},
+		{"INV/{YY}/{SEQ}", NumberingResetYearly, true},
+		{"INV-{SEQ:6}", NumberingResetNever, true},
+		{"INV/{YYYY}/{SEQ}", NumberingResetMonthly, false},
+		{"INV/{MM}/{SEQ}", NumberingResetYearly, false},
+		{"INV/{YYYY}", NumberingResetNever, false},
+		{"INV/{SEQ}/{SEQ}", NumberingResetNever, false},
+		{"INV/{SEQ:0}", NumberingResetNever, false},
+		{"INV/{SEQ:11}", NumberingResetNever, false},
+		{"INV/{HH}/{SEQ}", NumberingResetNever, false},
+	}
+	for _, c := range cases {
+		n := NumberingScheme{Template: c.template, Reset: c.reset}
+		err := n.Validate()
+		if c.valid && err != nil {
+			t.Fatalf("Validate(%q, %s) expected valid, got %v", c.template, c.reset, err)
+		}
+		if !c.valid && (err == nil || err.Error() != ErrInvalidNumberingTemplate) {
+			t.Fatalf("Validate(%q, %s) expected %s, got %v", c.template, c.reset, ErrInvalidNumberingTemplate, err)
+		}
+	}
+}
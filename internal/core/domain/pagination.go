@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Cursor points at the row a cursor page continues from. Clients get it as an opaque string.
+// Order names the sort order it was made for and Values holds the sort keys of the row;
+// Before pages back towards the start of the list instead of onwards.
+type Cursor struct {
+	Order  string   `json:"o"`
+	Values []string `json:"v"`
+	Before bool     `json:"b,omitempty"`
+}
+
+// Encode returns the opaque form of a cursor
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor reads a cursor from its opaque form
+func DecodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf(ErrInvalidCursor)
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(b, &c); err != nil || c.Order == "" || len(c.Values) == 0 {
+		return nil, fmt.Errorf(ErrInvalidCursor)
+	}
+	return &c, nil
+}
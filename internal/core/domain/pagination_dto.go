@@ -1,12 +1,24 @@
 package domain
 
+type PaginationMode string
+
+const (
+	PaginationPage   PaginationMode = "page"
+	PaginationCursor PaginationMode = "cursor"
+)
+
 type (
+	// PaginationRequest pages through a list by page number, or by cursor when Mode is cursor or a Cursor is given.
+	// Cursor pages skip counting the total unless WithTotal is set.
 	PaginationRequest struct {
-		Page   uint8  `query:"page"`
-		Limit  uint8  `query:"limit"`
-		Offset uint64 `query:"-"`
-		Search string `query:"search"`
-		UserID uint   `query:"user_id"`
+		Page      uint32         `query:"page"`
+		Limit     uint32         `query:"limit"`
+		Offset    uint64         `query:"-"`
+		Search    string         `query:"search"`
+		Mode      PaginationMode `query:"mode" validate:"omitempty,oneof=page cursor"`
+		Cursor    string         `query:"cursor"`
+		WithTotal bool           `query:"with_total"`
+		UserID    uint           `query:"user_id"`
 	}
 
 	PaginationResponse struct {
@@ -14,10 +26,23 @@ type (
 		Data []any                  `json:"data"`
 	}
 
+	// PaginationMetaResponse describes a page, totals are left out of cursor pages unless asked for
 	PaginationMetaResponse struct {
-		Page      uint8  `json:"page"`
-		Limit     uint8  `json:"limit"`
-		TotalData uint64 `json:"total_data"`
-		TotalPage uint8  `json:"total_page"`
+		Page       uint32  `json:"page,omitempty"`
+		Limit      uint32  `json:"limit"`
+		TotalData  *uint64 `json:"total_data,omitempty"`
+		TotalPage  *uint32 `json:"total_page,omitempty"`
+		NextCursor string  `json:"next_cursor,omitempty"`
+		PrevCursor string  `json:"prev_cursor,omitempty"`
 	}
 )
+
+// Pagination returns the paging part of a list request, request types embedding it share the defaults
+func (p *PaginationRequest) Pagination() *PaginationRequest {
+	return p
+}
+
+// CursorMode reports whether the list is paged by cursor instead of page number
+func (p *PaginationRequest) CursorMode() bool {
+	return p.Mode == PaginationCursor || p.Cursor != ""
+}
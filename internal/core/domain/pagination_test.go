@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := Cursor{Order: "due_date", Values: []string{"2026-03-10T00:00:00Z", "220260310001"}, Before: true}
+	got, err := DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(*got, c) {
+		t.Errorf("expected %+v, got %+v", c, *got)
+	}
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	for _, s := range []string{"not a cursor!", "e30", Cursor{Order: "created_at"}.Encode()} {
+		if _, err := DecodeCursor(s); err == nil || err.Error() != ErrInvalidCursor {
+			t.Errorf("expected %q to be rejected, got %v", s, err)
+		}
+	}
+}
+
+func TestPaginationRequestCursorMode(t *testing.T) {
+	cases := []struct {
+		req  PaginationRequest
+		want bool
+	}{
+		{PaginationRequest{}, false},
+		{PaginationRequest{Mode: PaginationPage}, false},
+		{PaginationRequest{Mode: PaginationCursor}, true},
+		{PaginationRequest{Cursor: "abc"}, true},
+	}
+	for _, c := range cases {
+		if got := c.req.CursorMode(); got != c.want {
+			t.Errorf("%+v: expected %v, got %v", c.req, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+)
+
+type PaymentMethod string
+
+const (
+	PaymentMethodCash         PaymentMethod = "cash"
+	PaymentMethodBankTransfer PaymentMethod = "bank_transfer"
+	PaymentMethodCard         PaymentMethod = "card"
+	PaymentMethodEWallet      PaymentMethod = "e_wallet"
+	PaymentMethodOther        PaymentMethod = "other"
+)
+
+// Payment is a single amount of money received against an invoice.
+// Payments are never edited; a mistaken payment is reversed instead.
+type Payment struct {
+	ID             int64
+	InvoiceID      int64
+	AuthorID       uint
+	Amount         int
+	PaidAt         time.Time
+	Method         PaymentMethod
+	Reference      string
+	Note           string
+	ReversedAt     *time.Time
+	ReversalReason string
+	Timestamp
+}
+
+func (Payment) TableName() string {
+	return "app.invoice_payments"
+}
+
+// IsReversed reports whether the payment has been reversed
+func (p *Payment) IsReversed() bool {
+	return p.ReversedAt != nil
+}
+
+// Response takes the public ID of the invoice, the stored invoice ID is internal
+func (p *Payment) Response(invoiceID string) PaymentResponse {
+	return PaymentResponse{
+		ID:             p.ID,
+		InvoiceID:      invoiceID,
+		Amount:         p.Amount,
+		PaidAt:         p.PaidAt.Format(time.DateOnly),
+		Method:         p.Method,
+		Reference:      p.Reference,
+		Note:           p.Note,
+		Reversed:       p.IsReversed(),
+		ReversedAt:     p.ReversedAt,
+		ReversalReason: p.ReversalReason,
+		CreatedAt:      p.CreatedAt,
+	}
+}
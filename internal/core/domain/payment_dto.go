@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// PaymentRequest represents a payment received against an invoice
+type PaymentRequest struct {
+	Amount    int           `json:"amount" validate:"required,min=1"`
+	PaidAt    string        `json:"paid_at" validate:"required,datetime=2006-01-02"`
+	Method    PaymentMethod `json:"method" validate:"required,oneof=cash bank_transfer card e_wallet other"`
+	Reference string        `json:"reference" validate:"max=200"`
+	Note      string        `json:"note" validate:"max=1000"`
+	InvoiceID string        `json:"-"`
+	UserID    uint          `json:"-"`
+}
+
+// ReversePaymentRequest represents the reversal of a recorded payment
+type ReversePaymentRequest struct {
+	Reason    string `json:"reason" validate:"required,min=1,max=500"`
+	PaymentID int64  `json:"-"`
+	InvoiceID string `json:"-"`
+	UserID    uint   `json:"-"`
+}
+
+// PaymentResponse represents payment output
+type PaymentResponse struct {
+	ID             int64         `json:"id"`
+	InvoiceID      string        `json:"invoice_id"`
+	Amount         int           `json:"amount"`
+	PaidAt         string        `json:"paid_at"`
+	Method         PaymentMethod `json:"method"`
+	Reference      string        `json:"reference"`
+	Note           string        `json:"note"`
+	Reversed       bool          `json:"reversed"`
+	ReversedAt     *time.Time    `json:"reversed_at,omitempty"`
+	ReversalReason string        `json:"reversal_reason,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PDFTemplate selects the layout invoices, quotes and credit notes are rendered with
+type PDFTemplate string
+
+const (
+	PDFTemplateClassic PDFTemplate = "classic"
+	PDFTemplateModern  PDFTemplate = "modern"
+	PDFTemplateCompact PDFTemplate = "compact"
+)
+
+const (
+	DefaultPDFTemplate = PDFTemplateClassic
+	// DefaultBrandColor is used by accounts that did not pick a color of their own
+	DefaultBrandColor = "#1F3A5F"
+)
+
+var brandColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// PDFSettings is how an account wants its documents rendered
+type PDFSettings struct {
+	Template   PDFTemplate
+	Locale     Locale
+	BrandColor string
+}
+
+// PDFSettings returns the document settings of the user, what was never set falls back to the defaults
+func (u *User) PDFSettings() PDFSettings {
+	s := PDFSettings{
+		Template:   PDFTemplate(u.PDFTemplate),
+		Locale:     Locale(u.PDFLocale),
+		BrandColor: strings.ToUpper(u.BrandColor),
+	}
+	switch s.Template {
+	case PDFTemplateClassic, PDFTemplateModern, PDFTemplateCompact:
+	default:
+		s.Template = DefaultPDFTemplate
+	}
+	if _, ok := localeFormats[s.Locale]; !ok {
+		s.Locale = DefaultLocale
+	}
+	if !brandColorPattern.MatchString(s.BrandColor) {
+		s.BrandColor = DefaultBrandColor
+	}
+	return s
+}
+
+// RGB returns the red, green and blue parts of the brand color
+func (s PDFSettings) RGB() (int, int, int) {
+	color := s.BrandColor
+	if !brandColorPattern.MatchString(color) {
+		color = DefaultBrandColor
+	}
+	v, _ := strconv.ParseUint(color[1:], 16, 32)
+	return int(v >> 16 & 0xFF), int(v >> 8 & 0xFF), int(v & 0xFF)
+}
+
+func (s PDFSettings) Response() PDFSettingsResponse {
+	return PDFSettingsResponse{
+		Template:   s.Template,
+		Locale:     s.Locale,
+		BrandColor: s.BrandColor,
+	}
+}
+
+// PDFLabels are the fixed words printed on documents
+type PDFLabels struct {
+	Invoice             string
+	Quotation           string
+	CreditNote          string
+	Draft               string
+	BillTo              string
+	IssueDate           string
+	DueDate             string
+	ValidUntil          string
+	InvoiceRef          string
+	No                  string
+	Item                string
+	Qty                 string
+	Price               string
+	Tax                 string
+	Amount              string
+	Subtotal            string
+	Discount            string
+	Included            string
+	Total               string
+	TotalCredit         string
+	Rate                string
+	PaymentInstructions string
+	AccountHolder       string
+	TaxID               string
+}
+
+var pdfLabels = map[Locale]PDFLabels{
+	LocaleID: {
+		Invoice:             "FAKTUR",
+		Quotation:           "PENAWARAN HARGA",
+		CreditNote:          "NOTA KREDIT",
+		Draft:               "DRAF",
+		BillTo:              "Kepada",
+		IssueDate:           "Tanggal",
+		DueDate:             "Jatuh tempo",
+		ValidUntil:          "Berlaku sampai",
+		InvoiceRef:          "Faktur",
+		No:                  "No",
+		Item:                "Deskripsi",
+		Qty:                 "Jml",
+		Price:               "Harga",
+		Tax:                 "Pajak",
+		Amount:              "Jumlah",
+		Subtotal:            "Subtotal",
+		Discount:            "Diskon",
+		Included:            "Termasuk",
+		Total:               "Total",
+		TotalCredit:         "Total Kredit",
+		Rate:                "Kurs",
+		PaymentInstructions: "Instruksi Pembayaran",
+		AccountHolder:       "a.n.",
+		TaxID:               "NPWP",
+	},
+	LocaleEN: {
+		Invoice:             "INVOICE",
+		Quotation:           "QUOTATION",
+		CreditNote:          "CREDIT NOTE",
+		Draft:               "DRAFT",
+		BillTo:              "Bill To",
+		IssueDate:           "Date",
+		DueDate:             "Due date",
+		ValidUntil:          "Valid until",
+		InvoiceRef:          "Invoice",
+		No:                  "No",
+		Item:                "Item",
+		Qty:                 "Qty",
+		Price:               "Price",
+		Tax:                 "Tax",
+		Amount:              "Amount",
+		Subtotal:            "Subtotal",
+		Discount:            "Discount",
+		Included:            "Incl.",
+		Total:               "Total",
+		TotalCredit:         "Total Credit",
+		Rate:                "Rate",
+		PaymentInstructions: "Payment Instructions",
+		AccountHolder:       "a/n",
+		TaxID:               "Tax ID",
+	},
+}
+
+// PDFLabels returns the document labels in the language of the locale
+func (l Locale) PDFLabels() PDFLabels {
+	if v, ok := pdfLabels[l]; ok {
+		return v
+	}
+	return pdfLabels[DefaultLocale]
+}
+
+// DiscountLabel names a discount line, percentage discounts show their rate
+func (p PDFLabels) DiscountLabel(t DiscountType, value int) string {
+	if t == DiscountTypePercentage {
+		return fmt.Sprintf("%s %d%%", p.Discount, value)
+	}
+	return p.Discount
+}
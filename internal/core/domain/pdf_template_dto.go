@@ -0,0 +1,15 @@
+package domain
+
+// PDFSettingsRequest represents the document settings of an account
+type PDFSettingsRequest struct {
+	Template   PDFTemplate `json:"template" validate:"required,oneof=classic modern compact"`
+	Locale     Locale      `json:"locale" validate:"required,oneof=id-ID en-US"`
+	BrandColor string      `json:"brand_color" validate:"omitempty,hexcolor,len=7"`
+	UserID     uint        `json:"-"`
+}
+
+type PDFSettingsResponse struct {
+	Template   PDFTemplate `json:"template"`
+	Locale     Locale      `json:"locale"`
+	BrandColor string      `json:"brand_color"`
+}
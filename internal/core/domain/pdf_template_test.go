@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserPDFSettings(t *testing.T) {
+	s := (&User{}).PDFSettings()
+	if s.Template != PDFTemplateClassic || s.Locale != LocaleID || s.BrandColor != DefaultBrandColor {
+		t.Fatalf("expected default settings, got %+v", s)
+	}
+
+	s = (&User{PDFTemplate: "modern", PDFLocale: "en-US", BrandColor: "#aa3300"}).PDFSettings()
+	if s.Template != PDFTemplateModern || s.Locale != LocaleEN || s.BrandColor != "#AA3300" {
+		t.Fatalf("unexpected settings %+v", s)
+	}
+	if r, g, b := s.RGB(); r != 0xAA || g != 0x33 || b != 0 {
+		t.Fatalf("unexpected rgb %d %d %d", r, g, b)
+	}
+
+	s = (&User{PDFTemplate: "fancy", PDFLocale: "fr-FR", BrandColor: "red"}).PDFSettings()
+	if s != (PDFSettings{Template: DefaultPDFTemplate, Locale: DefaultLocale, BrandColor: DefaultBrandColor}) {
+		t.Fatalf("expected unknown values to fall back to defaults, got %+v", s)
+	}
+}
+
+func TestLocaleDocumentFormats(t *testing.T) {
+	date := time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		locale  Locale
+		date    string
+		money   string
+		rate    string
+		decimal string
+		label   string
+	}{
+		{LocaleID, "16 Oktober 2026", "$ 1.234,56", "11,5%", "16.250,5", "Kepada"},
+		{LocaleEN, "October 16, 2026", "$ 1,234.56", "11.5%", "16,250.5", "Bill To"},
+	}
+	for _, c := range cases {
+		if got := c.locale.FormatLongDate(date); got != c.date {
+			t.Fatalf("%s: expected date %q, got %q", c.locale, c.date, got)
+		}
+		if got := c.locale.FormatMoney(123456, "USD"); got != c.money {
+			t.Fatalf("%s: expected money %q, got %q", c.locale, c.money, got)
+		}
+		if got := c.locale.FormatTaxRate(1150); got != c.rate {
+			t.Fatalf("%s: expected rate %q, got %q", c.locale, c.rate, got)
+		}
+		if got := c.locale.FormatDecimal("16250.5"); got != c.decimal {
+			t.Fatalf("%s: expected decimal %q, got %q", c.locale, c.decimal, got)
+		}
+		if got := c.locale.PDFLabels().BillTo; got != c.label {
+			t.Fatalf("%s: expected label %q, got %q", c.locale, c.label, got)
+		}
+	}
+
+	if got := LocaleEN.FormatMoney(-150000, "IDR"); got != "-Rp 150,000" {
+		t.Fatalf("unexpected negative amount %q", got)
+	}
+	if got := LocaleID.FormatDecimal("1e5"); got != "1e5" {
+		t.Fatalf("expected non decimal to be kept, got %q", got)
+	}
+	if got := LocaleEN.PDFLabels().DiscountLabel(DiscountTypePercentage, 10); got != "Discount 10%" {
+		t.Fatalf("unexpected discount label %q", got)
+	}
+}
@@ -0,0 +1,287 @@
+package domain
+
+import "time"
+
+type QuoteStatus string
+
+const (
+	QuoteStatusDraft     QuoteStatus = "draft"
+	QuoteStatusSent      QuoteStatus = "sent"
+	QuoteStatusAccepted  QuoteStatus = "accepted"
+	QuoteStatusDeclined  QuoteStatus = "declined"
+	QuoteStatusConverted QuoteStatus = "converted"
+)
+
+// quoteTransitions lists the statuses a quote may move to from each status.
+// An accepted quote becomes converted once every line is invoiced.
+var quoteTransitions = map[QuoteStatus][]QuoteStatus{
+	QuoteStatusDraft:     {QuoteStatusSent},
+	QuoteStatusSent:      {QuoteStatusAccepted, QuoteStatusDeclined},
+	QuoteStatusAccepted:  {QuoteStatusConverted},
+	QuoteStatusDeclined:  {},
+	QuoteStatusConverted: {},
+}
+
+// CanTransitionTo reports whether a quote in status s may move to next
+func (s QuoteStatus) CanTransitionTo(next QuoteStatus) bool {
+	for _, v := range quoteTransitions[s] {
+		if v == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEditable reports whether a quote in status s may still be changed
+func (s QuoteStatus) IsEditable() bool {
+	return s == QuoteStatusDraft || s == QuoteStatusSent
+}
+
+// Quote offers items to a customer until ValidUntil, an accepted quote is converted into invoices.
+// Dates are stored as YYYY-MM-DD, totals are kept for listing and recomputed from the lines.
+type Quote struct {
+	ID              uint
+	AuthorID        uint
+	Number          string
+	ProfileID       *uint
+	Issuer          string
+	CustomerID      *uint
+	Customer        string
+	CustomerCompany string
+	CustomerEmail   string
+	CustomerPhone   string
+	CustomerAddress string
+	CustomerTaxID   string
+	IssueDate       string
+	ValidUntil      string
+	Note            string
+	Status          QuoteStatus
+	Currency        string
+	TaxMode         TaxMode
+	TaxRateID       *uint
+	DiscountType    DiscountType
+	Discount        int
+	Subtotal        int
+	DiscountAmount  int
+	DiscountTotal   int
+	TaxTotal        int
+	Total           int
+	Timestamp
+}
+
+// QuoteItem is a quoted line with its tax snapshot, InvoiceID is the public ID of the invoice
+// the line was converted into and empty until then
+type QuoteItem struct {
+	ID           uint
+	QuoteID      uint
+	Description  string
+	Qty          int
+	Price        int
+	DiscountType DiscountType
+	Discount     int
+	TaxRateID    *uint
+	TaxName      string
+	TaxRate      int
+	InvoiceID    string
+	Timestamp
+}
+
+func (Quote) TableName() string {
+	return "app.quotes"
+}
+
+func (QuoteItem) TableName() string {
+	return "app.quote_items"
+}
+
+// SnapshotCustomer copies the current customer details onto the quote
+func (q *Quote) SnapshotCustomer(c *Customer) {
+	q.CustomerID = &c.ID
+	q.Customer = c.Name
+	q.CustomerCompany = c.Company
+	q.CustomerEmail = c.Email
+	q.CustomerPhone = c.Phone
+	q.CustomerAddress = c.Address
+	q.CustomerTaxID = c.TaxID
+}
+
+// SetTotals stores the totals of the priced lines
+func (q *Quote) SetTotals(totals InvoiceTotals) {
+	q.Subtotal = totals.Subtotal
+	q.DiscountAmount = totals.DiscountAmount
+	q.DiscountTotal = totals.DiscountTotal
+	q.TaxTotal = totals.TaxTotal
+	q.Total = totals.Total
+}
+
+// Expired reports whether the validity date of the quote has passed on today
+func (q *Quote) Expired(today time.Time) bool {
+	return q.ValidUntil < today.Format(time.DateOnly)
+}
+
+// Lines prices the quoted items the way an invoice of them would be priced
+func (q *Quote) Lines(items []QuoteItem) ([]InvoiceItem, InvoiceTotals) {
+	lines := make([]InvoiceItem, len(items))
+	for i, v := range items {
+		lines[i] = InvoiceItem{
+			ID:           v.ID,
+			AuthorID:     q.AuthorID,
+			Description:  v.Description,
+			Qty:          v.Qty,
+			Price:        v.Price,
+			DiscountType: v.DiscountType,
+			Discount:     v.Discount,
+			TaxRateID:    v.TaxRateID,
+			TaxName:      v.TaxName,
+			TaxRate:      v.TaxRate,
+			Timestamp:    v.Timestamp,
+		}
+	}
+	totals := CalculateInvoice(q.TaxMode, q.DiscountType, q.Discount, lines)
+	return lines, totals
+}
+
+// Converted reports whether any line of the quote was invoiced
+func Converted(items []QuoteItem) bool {
+	for _, v := range items {
+		if v.InvoiceID != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectLines picks the lines to convert, all lines not invoiced yet when ids is empty.
+// It reports false when an ID is not on the quote, repeated or already invoiced.
+func SelectLines(items []QuoteItem, ids []uint) ([]QuoteItem, bool) {
+	var selected []QuoteItem
+	if len(ids) == 0 {
+		for _, v := range items {
+			if v.InvoiceID == "" {
+				selected = append(selected, v)
+			}
+		}
+		return selected, len(selected) > 0
+	}
+
+	byID := make(map[uint]QuoteItem, len(items))
+	for _, v := range items {
+		byID[v.ID] = v
+	}
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		item, ok := byID[id]
+		if !ok || seen[id] || item.InvoiceID != "" {
+			return nil, false
+		}
+		seen[id] = true
+		selected = append(selected, item)
+	}
+	return selected, true
+}
+
+// InvoiceRequest builds the invoice of some lines of the quote.
+// A fixed quote discount is split like on the quote, each invoice takes the share of its lines,
+// so the invoices of a fully converted quote add up to the quote.
+func (q *Quote) InvoiceRequest(items, selected []QuoteItem, issueDate, dueDate string) InvoiceRequest {
+	req := InvoiceRequest{
+		ProfileID:    q.ProfileID,
+		Issuer:       q.Issuer,
+		Customer:     q.Customer,
+		CustomerID:   q.CustomerID,
+		IssueDate:    issueDate,
+		DueDate:      dueDate,
+		Note:         q.Note,
+		Currency:     q.Currency,
+		TaxMode:      q.TaxMode,
+		TaxRateID:    q.TaxRateID,
+		DiscountType: q.DiscountType,
+		Discount:     q.Discount,
+		Items:        make([]InvoiceItemRequest, len(selected)),
+		UserID:       q.AuthorID,
+	}
+
+	share := make(map[uint]int, len(items))
+	if q.DiscountType == DiscountTypeAmount {
+		lines, _ := q.Lines(items)
+		for _, v := range lines {
+			share[v.ID] = v.InvoiceDiscount
+		}
+		req.Discount = 0
+	}
+
+	for i, v := range selected {
+		req.Items[i] = InvoiceItemRequest{
+			Description:  v.Description,
+			Qty:          v.Qty,
+			Price:        v.Price,
+			DiscountType: v.DiscountType,
+			Discount:     v.Discount,
+			TaxRateID:    v.TaxRateID,
+		}
+		req.Discount += share[v.ID]
+	}
+	return req
+}
+
+func (q *Quote) Response(items []QuoteItem, today time.Time) QuoteResponse {
+	res := QuoteResponse{
+		ID:              q.ID,
+		Number:          q.Number,
+		ProfileID:       q.ProfileID,
+		Issuer:          q.Issuer,
+		CustomerID:      q.CustomerID,
+		Customer:        q.Customer,
+		CustomerCompany: q.CustomerCompany,
+		CustomerEmail:   q.CustomerEmail,
+		CustomerPhone:   q.CustomerPhone,
+		CustomerAddress: q.CustomerAddress,
+		CustomerTaxID:   q.CustomerTaxID,
+		IssueDate:       q.IssueDate,
+		ValidUntil:      q.ValidUntil,
+		Expired:         q.Status.IsEditable() && q.Expired(today),
+		Note:            q.Note,
+		Status:          q.Status,
+		Currency:        q.Currency,
+		TaxMode:         q.TaxMode,
+		TaxRateID:       q.TaxRateID,
+		DiscountType:    q.DiscountType,
+		Discount:        q.Discount,
+		Subtotal:        q.Subtotal,
+		DiscountAmount:  q.DiscountAmount,
+		DiscountTotal:   q.DiscountTotal,
+		TaxTotal:        q.TaxTotal,
+		Total:           q.Total,
+		CreatedAt:       q.CreatedAt,
+		UpdatedAt:       q.UpdatedAt,
+	}
+	if len(items) == 0 {
+		return res
+	}
+
+	lines, totals := q.Lines(items)
+	res.Items = make([]QuoteItemResponse, len(lines))
+	for i, v := range lines {
+		res.Items[i] = QuoteItemResponse{
+			ID:              v.ID,
+			Description:     v.Description,
+			Qty:             v.Qty,
+			Price:           v.Price,
+			DiscountType:    v.DiscountType,
+			Discount:        v.Discount,
+			DiscountAmount:  v.DiscountAmount,
+			InvoiceDiscount: v.InvoiceDiscount,
+			TaxRateID:       v.TaxRateID,
+			TaxName:         v.TaxName,
+			TaxRate:         v.TaxRate,
+			Subtotal:        v.Subtotal,
+			TaxAmount:       v.TaxAmount,
+			Total:           v.Total,
+			InvoiceID:       items[i].InvoiceID,
+		}
+	}
+	for _, v := range totals.Taxes {
+		res.Taxes = append(res.Taxes, v.Response())
+	}
+	return res
+}
@@ -0,0 +1,97 @@
+package domain
+
+import "time"
+
+// QuoteRequest represents quote input, items replace the existing ones
+type QuoteRequest struct {
+	ID           uint                 `json:"-"`
+	ProfileID    *uint                `json:"profile_id" validate:"omitempty,min=1"`
+	Issuer       string               `json:"issuer" validate:"max=200"`
+	Customer     string               `json:"customer" validate:"required_without=CustomerID,max=200"`
+	CustomerID   *uint                `json:"customer_id" validate:"omitempty,min=1"`
+	IssueDate    string               `json:"issue_date" validate:"required,datetime=2006-01-02"`
+	ValidUntil   string               `json:"valid_until" validate:"required,datetime=2006-01-02"`
+	Note         string               `json:"note" validate:"max=1000"`
+	Currency     string               `json:"currency" validate:"omitempty,len=3"`
+	TaxMode      TaxMode              `json:"tax_mode" validate:"omitempty,oneof=exclusive inclusive"`
+	TaxRateID    *uint                `json:"tax_rate_id" validate:"omitempty,min=1"`
+	DiscountType DiscountType         `json:"discount_type" validate:"required_with=Discount,omitempty,oneof=percentage amount"`
+	Discount     int                  `json:"discount" validate:"min=0"`
+	Items        []InvoiceItemRequest `json:"items" validate:"required,min=1,dive"`
+	UserID       uint                 `json:"-"`
+}
+
+// InvoiceRequest prices the quote like an invoice with the same lines
+func (r *QuoteRequest) InvoiceRequest() InvoiceRequest {
+	return InvoiceRequest{
+		TaxMode:      r.TaxMode,
+		TaxRateID:    r.TaxRateID,
+		DiscountType: r.DiscountType,
+		Discount:     r.Discount,
+		Items:        r.Items,
+		UserID:       r.UserID,
+	}
+}
+
+// QuoteConvertRequest selects the quote lines to invoice, every line not invoiced yet when empty.
+// The invoice is dated today unless IssueDate is given.
+type QuoteConvertRequest struct {
+	ID        uint   `json:"-"`
+	ItemIDs   []uint `json:"item_ids" validate:"omitempty,dive,min=1"`
+	IssueDate string `json:"issue_date" validate:"omitempty,datetime=2006-01-02"`
+	DueDate   string `json:"due_date" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+	UserID    uint   `json:"-"`
+}
+
+// QuoteItemResponse represents quote item output, InvoiceID is set once the line is invoiced
+type QuoteItemResponse struct {
+	ID              uint         `json:"id"`
+	Description     string       `json:"description"`
+	Qty             int          `json:"qty"`
+	Price           int          `json:"price"`
+	DiscountType    DiscountType `json:"discount_type"`
+	Discount        int          `json:"discount"`
+	DiscountAmount  int          `json:"discount_amount"`
+	InvoiceDiscount int          `json:"invoice_discount"`
+	TaxRateID       *uint        `json:"tax_rate_id"`
+	TaxName         string       `json:"tax_name"`
+	TaxRate         int          `json:"tax_rate"`
+	Subtotal        int          `json:"subtotal"`
+	TaxAmount       int          `json:"tax_amount"`
+	Total           int          `json:"total"`
+	InvoiceID       string       `json:"invoice_id,omitempty"`
+}
+
+// QuoteResponse represents quote output
+type QuoteResponse struct {
+	ID              uint                 `json:"id"`
+	Number          string               `json:"number"`
+	ProfileID       *uint                `json:"profile_id"`
+	Issuer          string               `json:"issuer"`
+	CustomerID      *uint                `json:"customer_id"`
+	Customer        string               `json:"customer"`
+	CustomerCompany string               `json:"customer_company"`
+	CustomerEmail   string               `json:"customer_email"`
+	CustomerPhone   string               `json:"customer_phone"`
+	CustomerAddress string               `json:"customer_address"`
+	CustomerTaxID   string               `json:"customer_tax_id"`
+	IssueDate       string               `json:"issue_date"`
+	ValidUntil      string               `json:"valid_until"`
+	Expired         bool                 `json:"expired"`
+	Note            string               `json:"note"`
+	Status          QuoteStatus          `json:"status"`
+	Currency        string               `json:"currency"`
+	TaxMode         TaxMode              `json:"tax_mode"`
+	TaxRateID       *uint                `json:"tax_rate_id"`
+	DiscountType    DiscountType         `json:"discount_type"`
+	Discount        int                  `json:"discount"`
+	Subtotal        int                  `json:"subtotal"`
+	DiscountAmount  int                  `json:"discount_amount"`
+	DiscountTotal   int                  `json:"discount_total"`
+	TaxTotal        int                  `json:"tax_total"`
+	Taxes           []InvoiceTaxResponse `json:"taxes,omitempty"`
+	Total           int                  `json:"total"`
+	Items           []QuoteItemResponse  `json:"items,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+}
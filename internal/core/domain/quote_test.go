@@ -0,0 +1,53 @@
+package domain
+
+import "testing"
+
+func TestSelectLines(t *testing.T) {
+	items := []QuoteItem{{ID: 1}, {ID: 2, InvoiceID: "inv-1"}, {ID: 3}}
+
+	all, ok := SelectLines(items, nil)
+	if !ok || len(all) != 2 || all[0].ID != 1 || all[1].ID != 3 {
+		t.Fatalf("expected lines not invoiced yet, got %+v", all)
+	}
+
+	cases := []struct {
+		name string
+		ids  []uint
+		want bool
+	}{
+		{"selected lines", []uint{3}, true},
+		{"unknown line", []uint{4}, false},
+		{"repeated line", []uint{1, 1}, false},
+		{"invoiced line", []uint{1, 2}, false},
+	}
+	for _, c := range cases {
+		if _, got := SelectLines(items, c.ids); got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+
+	if _, ok := SelectLines([]QuoteItem{{ID: 1, InvoiceID: "inv-1"}}, nil); ok {
+		t.Fatal("expected fully invoiced quote to have nothing to select")
+	}
+}
+
+func TestQuoteInvoiceRequestSplitsFixedDiscount(t *testing.T) {
+	quote := Quote{TaxMode: TaxModeExclusive, DiscountType: DiscountTypeAmount, Discount: 1000}
+	items := []QuoteItem{
+		{ID: 1, Qty: 1, Price: 1000},
+		{ID: 2, Qty: 1, Price: 2000},
+		{ID: 3, Qty: 3, Price: 1000},
+	}
+
+	first := quote.InvoiceRequest(items, items[:1], "2026-01-01", "")
+	rest := quote.InvoiceRequest(items, items[1:], "2026-01-01", "")
+	if first.DiscountType != DiscountTypeAmount || len(rest.Items) != 2 {
+		t.Fatalf("unexpected invoice requests %+v and %+v", first, rest)
+	}
+	if first.Discount+rest.Discount != quote.Discount {
+		t.Fatalf("expected discount shares to add up to %d, got %d and %d", quote.Discount, first.Discount, rest.Discount)
+	}
+	if first.Discount != 167 {
+		t.Fatalf("expected proportional share of 167, got %d", first.Discount)
+	}
+}
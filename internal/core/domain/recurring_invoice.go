@@ -0,0 +1,254 @@
+package domain
+
+import "time"
+
+type RecurringInterval string
+
+const (
+	RecurringWeekly  RecurringInterval = "weekly"
+	RecurringMonthly RecurringInterval = "monthly"
+	RecurringYearly  RecurringInterval = "yearly"
+)
+
+type RecurringStatus string
+
+const (
+	RecurringStatusActive RecurringStatus = "active"
+	RecurringStatusPaused RecurringStatus = "paused"
+	RecurringStatusEnded  RecurringStatus = "ended"
+)
+
+// MaxRecurringCatchUp limits how many missed runs of one schedule are invoiced in a single pass
+const MaxRecurringCatchUp = 12
+
+// RecurringInvoice is a template the scheduler turns into an invoice on every run.
+// Dates are stored as YYYY-MM-DD, NextRunDate is empty once the schedule has ended.
+type RecurringInvoice struct {
+	ID           uint
+	AuthorID     uint
+	Name         string
+	ProfileID    *uint
+	Issuer       string
+	CustomerID   *uint
+	Customer     string
+	Note         string
+	Currency     string
+	TaxMode      TaxMode
+	TaxRateID    *uint
+	DiscountType DiscountType
+	Discount     int
+	Interval     RecurringInterval
+	DayOfMonth   int
+	StartDate    string
+	EndDate      string
+	DueDays      *int
+	AutoSend     bool
+	Status       RecurringStatus
+	NextRunDate  string
+	LastRunDate  string
+	Timestamp
+}
+
+// RecurringInvoiceItem is a line copied onto every invoice of a schedule
+type RecurringInvoiceItem struct {
+	ID           uint
+	RecurringID  uint
+	Description  string
+	Qty          int
+	Price        int
+	DiscountType DiscountType
+	Discount     int
+	TaxRateID    *uint
+	Timestamp
+}
+
+func (RecurringInvoice) TableName() string {
+	return "app.recurring_invoices"
+}
+
+func (RecurringInvoiceItem) TableName() string {
+	return "app.recurring_invoice_items"
+}
+
+// CalendarDate returns the local date of t at midnight UTC, the form schedule runs are computed in
+func CalendarDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// runDay returns the day of month runs fall on, the day of the start date unless set
+func (r *RecurringInvoice) runDay() int {
+	if r.DayOfMonth > 0 {
+		return r.DayOfMonth
+	}
+	start, _ := time.Parse(time.DateOnly, r.StartDate)
+	return start.Day()
+}
+
+// runOn returns the given day of a month, clamped to the last day of shorter months
+func runOn(year int, month time.Month, day int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// FirstRun returns the first run on or after the start date
+func (r *RecurringInvoice) FirstRun() time.Time {
+	start, _ := time.Parse(time.DateOnly, r.StartDate)
+	switch r.Interval {
+	case RecurringMonthly:
+		run := runOn(start.Year(), start.Month(), r.runDay())
+		if run.Before(start) {
+			run = runOn(start.Year(), start.Month()+1, r.runDay())
+		}
+		return run
+	case RecurringYearly:
+		run := runOn(start.Year(), start.Month(), r.runDay())
+		if run.Before(start) {
+			run = runOn(start.Year()+1, start.Month(), r.runDay())
+		}
+		return run
+	}
+	return start
+}
+
+// NextRun returns the run following the given run
+func (r *RecurringInvoice) NextRun(run time.Time) time.Time {
+	switch r.Interval {
+	case RecurringMonthly:
+		return runOn(run.Year(), run.Month()+1, r.runDay())
+	case RecurringYearly:
+		return runOn(run.Year()+1, run.Month(), r.runDay())
+	}
+	return run.AddDate(0, 0, 7)
+}
+
+// ended reports whether a run falls after the end date of the schedule
+func (r *RecurringInvoice) ended(run time.Time) bool {
+	return r.EndDate != "" && run.Format(time.DateOnly) > r.EndDate
+}
+
+// Reschedule moves the next run to the first run on or after today that was not invoiced yet.
+// Runs missed while a schedule was paused or before it was created are skipped, not billed.
+func (r *RecurringInvoice) Reschedule(today time.Time) {
+	run := r.FirstRun()
+	for run.Before(today) || (r.LastRunDate != "" && run.Format(time.DateOnly) <= r.LastRunDate) {
+		run = r.NextRun(run)
+	}
+	r.setNextRun(run)
+}
+
+// Advance records that the invoice of a run was created and moves to the following run
+func (r *RecurringInvoice) Advance(run time.Time) {
+	r.LastRunDate = run.Format(time.DateOnly)
+	r.setNextRun(r.NextRun(run))
+}
+
+func (r *RecurringInvoice) setNextRun(run time.Time) {
+	if r.ended(run) {
+		r.NextRunDate = ""
+		r.Status = RecurringStatusEnded
+		return
+	}
+	r.NextRunDate = run.Format(time.DateOnly)
+	if r.Status == RecurringStatusEnded {
+		r.Status = RecurringStatusActive
+	}
+}
+
+// IsDue reports whether an active schedule has a run on or before today
+func (r *RecurringInvoice) IsDue(today time.Time) bool {
+	return r.Status == RecurringStatusActive && r.NextRunDate != "" && r.NextRunDate <= today.Format(time.DateOnly)
+}
+
+// Preview returns up to n upcoming runs starting at the next run
+func (r *RecurringInvoice) Preview(n int) []string {
+	runs := make([]string, 0, n)
+	if r.NextRunDate == "" {
+		return runs
+	}
+	run, _ := time.Parse(time.DateOnly, r.NextRunDate)
+	for len(runs) < n && !r.ended(run) {
+		runs = append(runs, run.Format(time.DateOnly))
+		run = r.NextRun(run)
+	}
+	return runs
+}
+
+// InvoiceRequest builds the invoice of a run, due days fall back to the payment terms of the profile
+func (r *RecurringInvoice) InvoiceRequest(items []RecurringInvoiceItem, run time.Time) InvoiceRequest {
+	req := InvoiceRequest{
+		ProfileID:        r.ProfileID,
+		Issuer:           r.Issuer,
+		Customer:         r.Customer,
+		CustomerID:       r.CustomerID,
+		IssueDate:        run.Format(time.DateOnly),
+		Note:             r.Note,
+		Currency:         r.Currency,
+		TaxMode:          r.TaxMode,
+		TaxRateID:        r.TaxRateID,
+		DiscountType:     r.DiscountType,
+		Discount:         r.Discount,
+		Items:            make([]InvoiceItemRequest, len(items)),
+		UserID:           r.AuthorID,
+		RecurringID:      &r.ID,
+		RecurringRunDate: run.Format(time.DateOnly),
+	}
+	if r.DueDays != nil {
+		y, m, d := run.AddDate(0, 0, *r.DueDays).Date()
+		req.DueDate = time.Date(y, m, d, 23, 59, 59, 0, time.UTC).Format(time.DateTime)
+	}
+	for i, v := range items {
+		req.Items[i] = InvoiceItemRequest{
+			Description:  v.Description,
+			Qty:          v.Qty,
+			Price:        v.Price,
+			DiscountType: v.DiscountType,
+			Discount:     v.Discount,
+			TaxRateID:    v.TaxRateID,
+		}
+	}
+	return req
+}
+
+func (r *RecurringInvoice) Response(items []RecurringInvoiceItem) RecurringInvoiceResponse {
+	itemResponses := make([]RecurringInvoiceItemResponse, len(items))
+	for i, v := range items {
+		itemResponses[i] = RecurringInvoiceItemResponse{
+			Description:  v.Description,
+			Qty:          v.Qty,
+			Price:        v.Price,
+			DiscountType: v.DiscountType,
+			Discount:     v.Discount,
+			TaxRateID:    v.TaxRateID,
+		}
+	}
+	return RecurringInvoiceResponse{
+		ID:           r.ID,
+		Name:         r.Name,
+		ProfileID:    r.ProfileID,
+		Issuer:       r.Issuer,
+		CustomerID:   r.CustomerID,
+		Customer:     r.Customer,
+		Note:         r.Note,
+		Currency:     r.Currency,
+		TaxMode:      r.TaxMode,
+		TaxRateID:    r.TaxRateID,
+		DiscountType: r.DiscountType,
+		Discount:     r.Discount,
+		Interval:     r.Interval,
+		DayOfMonth:   r.DayOfMonth,
+		StartDate:    r.StartDate,
+		EndDate:      r.EndDate,
+		DueDays:      r.DueDays,
+		AutoSend:     r.AutoSend,
+		Status:       r.Status,
+		NextRunDate:  r.NextRunDate,
+		LastRunDate:  r.LastRunDate,
+		Items:        itemResponses,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+	}
+}
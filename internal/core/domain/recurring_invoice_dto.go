@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// RecurringInvoiceRequest represents recurring invoice input, items replace the existing ones.
+// Without DueDays invoices are due after the payment terms of the business profile.
+type RecurringInvoiceRequest struct {
+	ID           uint                 `json:"-"`
+	Name         string               `json:"name" validate:"required,min=1,max=200"`
+	ProfileID    *uint                `json:"profile_id" validate:"omitempty,min=1"`
+	Issuer       string               `json:"issuer" validate:"max=200"`
+	Customer     string               `json:"customer" validate:"required_without=CustomerID,max=200"`
+	CustomerID   *uint                `json:"customer_id" validate:"omitempty,min=1"`
+	Note         string               `json:"note" validate:"max=1000"`
+	Currency     string               `json:"currency" validate:"omitempty,len=3"`
+	TaxMode      TaxMode              `json:"tax_mode" validate:"omitempty,oneof=exclusive inclusive"`
+	TaxRateID    *uint                `json:"tax_rate_id" validate:"omitempty,min=1"`
+	DiscountType DiscountType         `json:"discount_type" validate:"required_with=Discount,omitempty,oneof=percentage amount"`
+	Discount     int                  `json:"discount" validate:"min=0"`
+	Interval     RecurringInterval    `json:"interval" validate:"required,oneof=weekly monthly yearly"`
+	DayOfMonth   int                  `json:"day_of_month" validate:"omitempty,min=1,max=31"`
+	StartDate    string               `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate      string               `json:"end_date" validate:"omitempty,datetime=2006-01-02"`
+	DueDays      *int                 `json:"due_days" validate:"omitempty,min=0,max=365"`
+	AutoSend     bool                 `json:"auto_send"`
+	Items        []InvoiceItemRequest `json:"items" validate:"required,min=1,dive"`
+	UserID       uint                 `json:"-"`
+}
+
+// RecurringPreviewRequest represents the number of upcoming runs to preview
+type RecurringPreviewRequest struct {
+	Count int `query:"count" validate:"omitempty,min=1,max=60"`
+}
+
+// RecurringInvoiceItemResponse represents recurring invoice item output
+type RecurringInvoiceItemResponse struct {
+	Description  string       `json:"description"`
+	Qty          int          `json:"qty"`
+	Price        int          `json:"price"`
+	DiscountType DiscountType `json:"discount_type"`
+	Discount     int          `json:"discount"`
+	TaxRateID    *uint        `json:"tax_rate_id"`
+}
+
+// RecurringInvoiceResponse represents recurring invoice output
+type RecurringInvoiceResponse struct {
+	ID           uint                           `json:"id"`
+	Name         string                         `json:"name"`
+	ProfileID    *uint                          `json:"profile_id"`
+	Issuer       string                         `json:"issuer"`
+	CustomerID   *uint                          `json:"customer_id"`
+	Customer     string                         `json:"customer"`
+	Note         string                         `json:"note"`
+	Currency     string                         `json:"currency"`
+	TaxMode      TaxMode                        `json:"tax_mode"`
+	TaxRateID    *uint                          `json:"tax_rate_id"`
+	DiscountType DiscountType                   `json:"discount_type"`
+	Discount     int                            `json:"discount"`
+	Interval     RecurringInterval              `json:"interval"`
+	DayOfMonth   int                            `json:"day_of_month"`
+	StartDate    string                         `json:"start_date"`
+	EndDate      string                         `json:"end_date"`
+	DueDays      *int                           `json:"due_days"`
+	AutoSend     bool                           `json:"auto_send"`
+	Status       RecurringStatus                `json:"status"`
+	NextRunDate  string                         `json:"next_run_date"`
+	LastRunDate  string                         `json:"last_run_date"`
+	Items        []RecurringInvoiceItemResponse `json:"items,omitempty"`
+	CreatedAt    time.Time                      `json:"created_at"`
+	UpdatedAt    time.Time                      `json:"updated_at"`
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mustDate(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+func TestRecurringInvoiceMonthlyClampsToMonthEnd(t *testing.T) {
+	r := RecurringInvoice{Interval: RecurringMonthly, DayOfMonth: 31, StartDate: "2026-01-15", Status: RecurringStatusActive}
+	r.Reschedule(mustDate("2026-01-10"))
+
+	want := []string{"2026-01-31", "2026-02-28", "2026-03-31", "2026-04-30"}
+	if got := r.Preview(4); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected runs %v, got %v", want, got)
+	}
+}
+
+func TestRecurringInvoiceWeeklyAndYearly(t *testing.T) {
+	weekly := RecurringInvoice{Interval: RecurringWeekly, StartDate: "2026-12-28", Status: RecurringStatusActive}
+	weekly.Reschedule(mustDate("2026-12-01"))
+	if got, want := weekly.Preview(2), []string{"2026-12-28", "2027-01-04"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected weekly runs %v, got %v", want, got)
+	}
+
+	yearly := RecurringInvoice{Interval: RecurringYearly, StartDate: "2028-02-29", Status: RecurringStatusActive}
+	yearly.Reschedule(mustDate("2028-01-01"))
+	if got, want := yearly.Preview(2), []string{"2028-02-29", "2029-02-28"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected yearly runs %v, got %v", want, got)
+	}
+}
+
+func TestRecurringInvoiceRescheduleSkipsPastRuns(t *testing.T) {
+	r := RecurringInvoice{Interval: RecurringMonthly, StartDate: "2026-01-05", Status: RecurringStatusPaused, LastRunDate: "2026-02-05"}
+	r.Reschedule(mustDate("2026-04-06"))
+
+	if r.NextRunDate != "2026-05-05" {
+		t.Fatalf("expected next run 2026-05-05, got %s", r.NextRunDate)
+	}
+	if r.Status != RecurringStatusPaused {
+		t.Fatalf("expected status to stay paused, got %s", r.Status)
+	}
+}
+
+func TestRecurringInvoiceAdvanceEndsSchedule(t *testing.T) {
+	r := RecurringInvoice{Interval: RecurringMonthly, StartDate: "2026-01-01", EndDate: "2026-02-15", Status: RecurringStatusActive}
+	r.Reschedule(mustDate("2026-01-01"))
+
+	r.Advance(mustDate("2026-01-01"))
+	if r.NextRunDate != "2026-02-01" || !r.IsDue(mustDate("2026-02-01")) {
+		t.Fatalf("expected next run 2026-02-01 to be due, got %q", r.NextRunDate)
+	}
+
+	r.Advance(mustDate("2026-02-01"))
+	if r.Status != RecurringStatusEnded || r.NextRunDate != "" || r.LastRunDate != "2026-02-01" {
+		t.Fatalf("expected ended schedule, got status %s next %q last %q", r.Status, r.NextRunDate, r.LastRunDate)
+	}
+	if r.IsDue(mustDate("2026-03-01")) {
+		t.Fatal("expected ended schedule to never be due")
+	}
+}
+
+func TestRecurringInvoiceRequest(t *testing.T) {
+	days := 14
+	r := RecurringInvoice{ID: 7, AuthorID: 3, Customer: "Budi", DueDays: &days}
+	items := []RecurringInvoiceItem{{Description: "Hosting", Qty: 1, Price: 150000}}
+
+	req := r.InvoiceRequest(items, mustDate("2026-03-01"))
+	if req.IssueDate != "2026-03-01" || req.DueDate != "2026-03-15 23:59:59" {
+		t.Fatalf("unexpected dates issue %s due %s", req.IssueDate, req.DueDate)
+	}
+	if req.RecurringID == nil || *req.RecurringID != 7 || req.RecurringRunDate != "2026-03-01" || req.UserID != 3 {
+		t.Fatalf("unexpected recurring run link %+v", req)
+	}
+	if len(req.Items) != 1 || req.Items[0].Price != 150000 {
+		t.Fatalf("unexpected items %+v", req.Items)
+	}
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type NotificationChannel string
+
+const (
+	NotificationEmail    NotificationChannel = "email"
+	NotificationWhatsApp NotificationChannel = "whatsapp"
+)
+
+// Notification is a message to a customer on one channel, Recipient is an email address or phone number
+type Notification struct {
+	Channel   NotificationChannel
+	Recipient string
+	Subject   string
+	Body      string
+}
+
+type ReminderStatus string
+
+const (
+	ReminderStatusPending ReminderStatus = "pending"
+	ReminderStatusSent    ReminderStatus = "sent"
+	ReminderStatusFailed  ReminderStatus = "failed"
+	ReminderStatusSkipped ReminderStatus = "skipped"
+)
+
+// MaxReminderDelay is how many days late a missed reminder is still sent, e.g. after downtime
+const MaxReminderDelay = 3
+
+// InvoiceReminder records a payment reminder of an invoice on one channel.
+// It is stored before the message goes out, so a reminder is never sent twice,
+// not even when the process stops halfway; Status tells how it ended.
+type InvoiceReminder struct {
+	ID         uint
+	AuthorID   uint
+	InvoiceID  int64
+	OffsetDays int
+	Channel    NotificationChannel
+	Recipient  string
+	Status     ReminderStatus
+	Error      string
+	SentAt     *time.Time
+	Timestamp
+}
+
+func (InvoiceReminder) TableName() string {
+	return "app.invoice_reminders"
+}
+
+// ReminderDueRange returns the due dates of invoices whose reminder at offsets[i] days is due on today.
+// Offsets must be sorted. A reminder is sent at most MaxReminderDelay days late and no longer once
+// the next offset is reached, so a customer gets one reminder at a time.
+func ReminderDueRange(offsets []int, i int, today time.Time) (from, to time.Time) {
+	to = today.AddDate(0, 0, -offsets[i])
+	from = to.AddDate(0, 0, -MaxReminderDelay)
+	if i+1 < len(offsets) {
+		if next := today.AddDate(0, 0, 1-offsets[i+1]); next.After(from) {
+			from = next
+		}
+	}
+	return from, to
+}
+
+// ReminderNotifications builds the reminder of an invoice for every channel its customer can be reached on
+func ReminderNotifications(inv *Invoice, today time.Time) []Notification {
+	due := inv.DueDate.Format(time.DateOnly)
+	subject := fmt.Sprintf("Payment reminder for invoice %s", inv.Number)
+	state := "is due on " + due
+	if CalendarDate(inv.DueDate).Before(today) {
+		state = "was due on " + due
+	} else if CalendarDate(inv.DueDate).Equal(today) {
+		state = "is due today"
+	}
+	body := fmt.Sprintf("Dear %s,\n\nThis is a friendly reminder that invoice %s from %s %s. The outstanding balance is %s.\n\nPlease disregard this message if you have already paid.\n\n%s",
+		inv.Customer, inv.Number, inv.Issuer, state, FormatMoney(inv.Balance(), inv.Currency), inv.Issuer)
+
+	var res []Notification
+	if inv.CustomerEmail != "" {
+		res = append(res, Notification{Channel: NotificationEmail, Recipient: inv.CustomerEmail, Subject: subject, Body: body})
+	}
+	if inv.CustomerPhone != "" {
+		res = append(res, Notification{Channel: NotificationWhatsApp, Recipient: inv.CustomerPhone, Subject: subject, Body: body})
+	}
+	return res
+}
+
+func (r *InvoiceReminder) Response() InvoiceReminderResponse {
+	return InvoiceReminderResponse{
+		ID:         r.ID,
+		OffsetDays: r.OffsetDays,
+		Channel:    r.Channel,
+		Recipient:  r.Recipient,
+		Status:     r.Status,
+		Error:      r.Error,
+		SentAt:     r.SentAt,
+		CreatedAt:  r.CreatedAt,
+	}
+}
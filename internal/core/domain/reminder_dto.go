@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// InvoiceReminderResponse represents a reminder sent for an invoice
+type InvoiceReminderResponse struct {
+	ID         uint                `json:"id"`
+	OffsetDays int                 `json:"offset_days"`
+	Channel    NotificationChannel `json:"channel"`
+	Recipient  string              `json:"recipient"`
+	Status     ReminderStatus      `json:"status"`
+	Error      string              `json:"error,omitempty"`
+	SentAt     *time.Time          `json:"sent_at"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
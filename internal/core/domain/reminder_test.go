@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReminderDueRange(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	offsets := []int{-3, 0, 7}
+	cases := []struct {
+		offset   int
+		from, to string
+	}{
+		// Stops the day before the due date, when the reminder at offset 0 takes over
+		{-3, "2026-03-11", "2026-03-13"},
+		{0, "2026-03-07", "2026-03-10"},
+		{7, "2026-02-28", "2026-03-03"},
+	}
+	for i, c := range cases {
+		from, to := ReminderDueRange(offsets, i, today)
+		if from.Format(time.DateOnly) != c.from || to.Format(time.DateOnly) != c.to {
+			t.Errorf("offset %d: expected %s..%s, got %s..%s", c.offset, c.from, c.to, from.Format(time.DateOnly), to.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestReminderNotifications(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	inv := &Invoice{
+		Number:        "INV/2026/03/0001",
+		Customer:      "Acme",
+		CustomerEmail: "billing@acme.test",
+		DueDate:       today,
+		Currency:      "IDR",
+	}
+	res := ReminderNotifications(inv, today)
+	if len(res) != 1 || res[0].Channel != NotificationEmail || res[0].Recipient != inv.CustomerEmail {
+		t.Fatalf("expected one email notification, got %+v", res)
+	}
+
+	inv.CustomerPhone = "+628123456789"
+	res = ReminderNotifications(inv, today)
+	if len(res) != 2 || res[1].Channel != NotificationWhatsApp || res[1].Recipient != inv.CustomerPhone {
+		t.Fatalf("expected email and whatsapp notifications, got %+v", res)
+	}
+
+	inv.CustomerEmail, inv.CustomerPhone = "", ""
+	if res = ReminderNotifications(inv, today); len(res) != 0 {
+		t.Errorf("expected no notifications without contact details, got %+v", res)
+	}
+}
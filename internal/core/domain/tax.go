@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// basisPoints is the scale tax rates are stored in, 1100 means 11%
+const basisPoints = 10000
+
+type TaxMode string
+
+const (
+	TaxModeExclusive TaxMode = "exclusive"
+	TaxModeInclusive TaxMode = "inclusive"
+)
+
+// TaxRate is a reusable tax such as PPN 11%, with Rate in basis points
+type TaxRate struct {
+	ID       uint
+	AuthorID uint
+	Name     string
+	Rate     int
+	IsActive bool
+	Timestamp
+}
+
+func (TaxRate) TableName() string {
+	return "app.tax_rates"
+}
+
+func (t *TaxRate) Response() TaxRateResponse {
+	return TaxRateResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Rate:      t.Rate,
+		Label:     FormatTaxRate(t.Rate),
+		IsActive:  t.IsActive,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// InvoiceTax is the persisted tax breakdown of an invoice, one row per applied rate
+type InvoiceTax struct {
+	InvoiceID     int64
+	ID            uint
+	AuthorID      uint
+	TaxRateID     *uint
+	Name          string
+	Rate          int
+	TaxableAmount int
+	TaxAmount     int
+}
+
+func (InvoiceTax) TableName() string {
+	return "app.invoice_taxes"
+}
+
+// Label returns the tax name with its rate, e.g. "PPN 11%"
+func (t *InvoiceTax) Label() string {
+	return fmt.Sprintf("%s %s", t.Name, FormatTaxRate(t.Rate))
+}
+
+func (t *InvoiceTax) Response() InvoiceTaxResponse {
+	return InvoiceTaxResponse{
+		TaxRateID:     t.TaxRateID,
+		Name:          t.Name,
+		Rate:          t.Rate,
+		Label:         t.Label(),
+		TaxableAmount: t.TaxableAmount,
+		TaxAmount:     t.TaxAmount,
+	}
+}
+
+// FormatTaxRate renders a basis point rate as a percentage, e.g. 1100 => "11%", 1250 => "12.5%"
+func FormatTaxRate(rate int) string {
+	whole := rate / 100
+	fraction := rate % 100
+	if fraction == 0 {
+		return fmt.Sprintf("%d%%", whole)
+	}
+	return strings.TrimRight(fmt.Sprintf("%d.%02d", whole, fraction), "0") + "%"
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// TaxRateRequest represents tax rate input, Rate is in basis points (1100 = 11%)
+type TaxRateRequest struct {
+	ID       uint   `json:"-"`
+	Name     string `json:"name" validate:"required,min=1,max=100"`
+	Rate     int    `json:"rate" validate:"min=0,max=10000"`
+	IsActive *bool  `json:"is_active"`
+	UserID   uint   `json:"-"`
+}
+
+// TaxRateResponse represents tax rate output
+type TaxRateResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Rate      int       `json:"rate"`
+	Label     string    `json:"label"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// InvoiceTaxResponse represents one line of an invoice tax breakdown
+type InvoiceTaxResponse struct {
+	TaxRateID     *uint  `json:"tax_rate_id"`
+	Name          string `json:"name"`
+	Rate          int    `json:"rate"`
+	Label         string `json:"label"`
+	TaxableAmount int    `json:"taxable_amount"`
+	TaxAmount     int    `json:"tax_amount"`
+}
@@ -1,11 +1,15 @@
 package domain
 
 type User struct {
-	ID       uint
-	Name     string
-	Email    *string
-	Phone    string
-	Password string
+	ID           uint
+	Name         string
+	Email        *string
+	Phone        string
+	Password     string
+	BaseCurrency string
+	PDFTemplate  string
+	PDFLocale    string
+	BrandColor   string
 	Timestamp
 }
 
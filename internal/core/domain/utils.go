@@ -7,5 +7,5 @@ import (
 type Timestamp struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	DeletedAt time.Time
+	DeletedAt *time.Time
 }
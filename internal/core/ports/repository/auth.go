@@ -15,4 +15,6 @@ type UserRepository interface {
 	FindByID(ctx context.Context, id uint) (*domain.User, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	ExistsByPhone(ctx context.Context, phone string) (bool, error)
+	UpdateBaseCurrency(ctx context.Context, id uint, currency string) error
+	UpdatePDFSettings(ctx context.Context, id uint, template, locale, brandColor string) error
 }
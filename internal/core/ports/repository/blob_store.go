@@ -0,0 +1,13 @@
+package portRepository
+
+import "context"
+
+// BlobStore keeps files by key.
+// Implementations may write them to the local disk or to an S3 compatible bucket.
+type BlobStore interface {
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	// Get returns an error matching fs.ErrNotExist when nothing is stored under key
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete succeeds when nothing is stored under key
+	Delete(ctx context.Context, key string) error
+}
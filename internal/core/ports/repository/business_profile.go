@@ -0,0 +1,20 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type BusinessProfileRepository interface {
+	GetByAuthorID(ctx context.Context, authorID uint) ([]domain.BusinessProfile, error)
+	GetByID(ctx context.Context, authorID, id uint) (*domain.BusinessProfile, error)
+	GetDefault(ctx context.Context, authorID uint) (*domain.BusinessProfile, error)
+	GetBankAccounts(ctx context.Context, profileIDs []uint) ([]domain.BankAccount, error)
+	Create(ctx context.Context, tx Transaction, data *domain.BusinessProfile) error
+	Update(ctx context.Context, tx Transaction, data *domain.BusinessProfile) error
+	UpdateLogo(ctx context.Context, data *domain.BusinessProfile) error
+	ClearDefault(ctx context.Context, tx Transaction, authorID, exceptID uint) error
+	ReplaceBankAccounts(ctx context.Context, tx Transaction, profileID uint, data []domain.BankAccount) error
+	SoftDelete(ctx context.Context, data *domain.BusinessProfile) error
+}
@@ -0,0 +1,22 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type CreditNoteRepository interface {
+	Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
+	GetByID(ctx context.Context, authorID, id uint) (*domain.CreditNote, error)
+	LockByID(ctx context.Context, tx Transaction, authorID, id uint) (*domain.CreditNote, error)
+	GetItems(ctx context.Context, creditNoteID uint) ([]domain.CreditNoteItem, error)
+	GetAllocations(ctx context.Context, creditNoteID uint) ([]domain.CreditAllocation, error)
+	GetCreditedItems(ctx context.Context, tx Transaction, authorID uint, invoiceID int64) ([]domain.CreditedItem, error)
+	SumAllocations(ctx context.Context, tx Transaction, authorID uint, invoiceID int64) (int, error)
+	NumberExists(ctx context.Context, tx Transaction, authorID uint, number string) (bool, error)
+	Create(ctx context.Context, tx Transaction, data *domain.CreditNote) error
+	CreateItems(ctx context.Context, tx Transaction, data []domain.CreditNoteItem) error
+	CreateAllocation(ctx context.Context, tx Transaction, data *domain.CreditAllocation) error
+	UpdateBalance(ctx context.Context, tx Transaction, data *domain.CreditNote) error
+}
@@ -0,0 +1,16 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type CustomerRepository interface {
+	Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
+	GetByID(ctx context.Context, authorID, id uint) (*domain.Customer, error)
+	GetByAuthorID(ctx context.Context, authorID uint) ([]domain.Customer, error)
+	Create(ctx context.Context, tx Transaction, data *domain.Customer) error
+	Update(ctx context.Context, data *domain.Customer) error
+	SoftDelete(ctx context.Context, data *domain.Customer) error
+}
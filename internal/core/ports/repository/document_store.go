@@ -0,0 +1,19 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+// DocumentStore keeps rendered documents such as invoice PDFs by key, it is a cache shared by every replica.
+// Implementations may write them to the local disk or to an S3 compatible bucket.
+type DocumentStore interface {
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	// Get returns an error matching fs.ErrNotExist when nothing is stored under key
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete succeeds when nothing is stored under key
+	Delete(ctx context.Context, key string) error
+	// List returns the documents whose key starts with prefix, an empty prefix lists all of them
+	List(ctx context.Context, prefix string) ([]domain.StoredDocument, error)
+}
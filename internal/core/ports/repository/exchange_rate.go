@@ -0,0 +1,20 @@
+package portRepository
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+// ExchangeRateProvider resolves the rate converting currency into base as of a date.
+// Implementations may read stored rates or call an external feed.
+type ExchangeRateProvider interface {
+	GetRate(ctx context.Context, authorID uint, currency, base string, date time.Time) (*domain.ExchangeRate, error)
+}
+
+// ExchangeRateRepository manages the manually entered rates of an account
+type ExchangeRateRepository interface {
+	GetByAuthorID(ctx context.Context, authorID uint) ([]domain.ExchangeRate, error)
+	Save(ctx context.Context, data *domain.ExchangeRate) error
+}
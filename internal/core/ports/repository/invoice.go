@@ -8,13 +8,37 @@ import (
 )
 
 type InvoiceRepository interface {
-	Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
+	Get(ctx context.Context, req *domain.InvoicePageReq) (*domain.PaginationResponse, error)
+	GetDeleted(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
+	Export(ctx context.Context, req *domain.InvoicePageReq, size uint32, fn func([]domain.Invoice) error) error
+	Count(ctx context.Context, req *domain.InvoicePageReq) (int64, error)
 	GenerateInvoiceID(ctx context.Context, tx Transaction, userID uint, date time.Time) (int64, error)
-	GetByID(ctx context.Context, id int64) (*domain.Invoice, error)
-	GetItems(ctx context.Context, invoiceID []int64) ([]domain.InvoiceItem, error)
-	GetItemsByInvoiceID(ctx context.Context, invoiceID int64) ([]domain.InvoiceItem, error)
+	GetByPublicID(ctx context.Context, authorID uint, publicID string) (*domain.Invoice, error)
+	LockByPublicID(ctx context.Context, tx Transaction, authorID uint, publicID string) (*domain.Invoice, error)
+	LockDeletedByPublicID(ctx context.Context, tx Transaction, authorID uint, publicID string) (*domain.Invoice, error)
+	GetByIDs(ctx context.Context, authorID uint, ids []int64) ([]domain.Invoice, error)
+	GetItems(ctx context.Context, authorID uint, invoiceID []int64) ([]domain.InvoiceItem, error)
+	GetItemsByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceItem, error)
+	GetTaxesByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceTax, error)
+	HasCreditNotes(ctx context.Context, tx Transaction, authorID uint, invoiceID int64) (bool, error)
+	HasRecurringRun(ctx context.Context, tx Transaction, recurringID uint, runDate string) (bool, error)
+	HasImportRef(ctx context.Context, tx Transaction, authorID uint, ref string) (bool, error)
+	GetImportRefs(ctx context.Context, authorID uint, refs []string) ([]string, error)
 	Create(ctx context.Context, tx Transaction, data *domain.Invoice) error
 	CreateItem(ctx context.Context, tx Transaction, data []domain.InvoiceItem) error
+	CreateTaxes(ctx context.Context, tx Transaction, data []domain.InvoiceTax) error
 	Update(ctx context.Context, tx Transaction, data *domain.Invoice) error
-	DeleteItemsByInvoiceID(ctx context.Context, tx Transaction, invoiceID int64) error
+	UpdateStatus(ctx context.Context, tx Transaction, data *domain.Invoice) error
+	MarkOverdue(ctx context.Context, now time.Time) (int64, error)
+	AssignNumber(ctx context.Context, tx Transaction, data *domain.Invoice) error
+	NumberExists(ctx context.Context, tx Transaction, authorID uint, number string) (bool, error)
+	UpdateSettlement(ctx context.Context, tx Transaction, data *domain.Invoice) error
+	DeleteItemsByInvoiceID(ctx context.Context, tx Transaction, authorID uint, invoiceID int64) error
+	DeleteTaxesByInvoiceID(ctx context.Context, tx Transaction, authorID uint, invoiceID int64) error
+	UpdateCustomer(ctx context.Context, tx Transaction, data *domain.Invoice) error
+	GetUnlinkedCustomers(ctx context.Context) ([]domain.UnlinkedCustomer, error)
+	LinkCustomer(ctx context.Context, tx Transaction, authorID uint, names []string, customerID uint) (int64, error)
+	SoftDelete(ctx context.Context, tx Transaction, data *domain.Invoice) error
+	Restore(ctx context.Context, tx Transaction, data *domain.Invoice) error
+	Purge(ctx context.Context, tx Transaction, data *domain.Invoice) error
 }
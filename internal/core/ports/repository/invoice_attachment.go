@@ -0,0 +1,16 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type InvoiceAttachmentRepository interface {
+	// GetByInvoiceID lists the attachments of an invoice oldest first
+	GetByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceAttachment, error)
+	GetByID(ctx context.Context, authorID uint, invoiceID int64, id uint) (*domain.InvoiceAttachment, error)
+	Count(ctx context.Context, tx Transaction, authorID uint, invoiceID int64) (int64, error)
+	Create(ctx context.Context, tx Transaction, data *domain.InvoiceAttachment) error
+	Delete(ctx context.Context, data *domain.InvoiceAttachment) error
+}
@@ -0,0 +1,20 @@
+package portRepository
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type InvoiceExportRepository interface {
+	GetByID(ctx context.Context, authorID, id uint) (*domain.InvoiceExport, error)
+	Create(ctx context.Context, data *domain.InvoiceExport) error
+	// Claim marks the oldest pending export as running and returns it, or nil when none is waiting.
+	// Exports left running since before staleBefore, e.g. by a crashed process, are claimed again.
+	Claim(ctx context.Context, now, staleBefore time.Time) (*domain.InvoiceExport, error)
+	Finish(ctx context.Context, data *domain.InvoiceExport) error
+	// GetExpired lists exports whose file is kept past its expiry
+	GetExpired(ctx context.Context, now time.Time, limit int) ([]domain.InvoiceExport, error)
+	ClearFile(ctx context.Context, id uint) error
+}
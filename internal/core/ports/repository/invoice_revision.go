@@ -0,0 +1,16 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type InvoiceRevisionRepository interface {
+	// GetByInvoiceID lists the revisions of an invoice newest first, without their snapshots
+	GetByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceRevision, error)
+	GetByRevision(ctx context.Context, authorID uint, invoiceID int64, revision int) (*domain.InvoiceRevision, error)
+	GetLatest(ctx context.Context, authorID uint, invoiceID int64) (*domain.InvoiceRevision, error)
+	// Create numbers the revision after the last one of its invoice, the invoice must be locked by tx
+	Create(ctx context.Context, tx Transaction, data *domain.InvoiceRevision) error
+}
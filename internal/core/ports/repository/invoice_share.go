@@ -0,0 +1,18 @@
+package portRepository
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type InvoiceShareRepository interface {
+	GetByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceShare, error)
+	GetByID(ctx context.Context, authorID uint, invoiceID int64, id uint) (*domain.InvoiceShare, error)
+	// GetByTokenID looks a share up by the ID of a verified token, for the public views only
+	GetByTokenID(ctx context.Context, id uint) (*domain.InvoiceShare, error)
+	Create(ctx context.Context, data *domain.InvoiceShare) error
+	Revoke(ctx context.Context, data *domain.InvoiceShare) error
+	RecordView(ctx context.Context, id uint, viewedAt time.Time) error
+}
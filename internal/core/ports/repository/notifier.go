@@ -0,0 +1,15 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+// Notifier delivers messages to customers.
+// Implementations may send them over email or WhatsApp, or only write them to a log.
+type Notifier interface {
+	// Supports reports whether messages can be delivered on a channel
+	Supports(channel domain.NotificationChannel) bool
+	Send(ctx context.Context, n *domain.Notification) error
+}
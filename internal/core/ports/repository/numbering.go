@@ -0,0 +1,14 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type NumberingRepository interface {
+	GetScheme(ctx context.Context, authorID uint, document domain.DocumentType) (*domain.NumberingScheme, error)
+	SaveScheme(ctx context.Context, data *domain.NumberingScheme) error
+	NextSequence(ctx context.Context, tx Transaction, authorID uint, document domain.DocumentType, period string) (int64, error)
+	CurrentSequence(ctx context.Context, authorID uint, document domain.DocumentType, period string) (int64, error)
+}
@@ -0,0 +1,15 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type PaymentRepository interface {
+	GetByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.Payment, error)
+	LockByID(ctx context.Context, tx Transaction, authorID uint, id int64) (*domain.Payment, error)
+	SumByInvoiceID(ctx context.Context, tx Transaction, authorID uint, invoiceID int64) (int, error)
+	Create(ctx context.Context, tx Transaction, data *domain.Payment) error
+	Reverse(ctx context.Context, tx Transaction, data *domain.Payment) error
+}
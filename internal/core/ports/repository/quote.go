@@ -0,0 +1,22 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type QuoteRepository interface {
+	Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
+	GetByID(ctx context.Context, authorID, id uint) (*domain.Quote, error)
+	LockByID(ctx context.Context, tx Transaction, authorID, id uint) (*domain.Quote, error)
+	GetItems(ctx context.Context, quoteID uint) ([]domain.QuoteItem, error)
+	NumberExists(ctx context.Context, tx Transaction, authorID uint, number string) (bool, error)
+	Create(ctx context.Context, tx Transaction, data *domain.Quote) error
+	CreateItems(ctx context.Context, tx Transaction, data []domain.QuoteItem) error
+	Update(ctx context.Context, tx Transaction, data *domain.Quote) error
+	UpdateStatus(ctx context.Context, tx Transaction, data *domain.Quote) error
+	MarkConverted(ctx context.Context, tx Transaction, quoteID uint, itemIDs []uint, invoiceID string) error
+	DeleteItems(ctx context.Context, tx Transaction, quoteID uint) error
+	SoftDelete(ctx context.Context, tx Transaction, data *domain.Quote) error
+}
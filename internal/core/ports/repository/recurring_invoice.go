@@ -0,0 +1,22 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type RecurringInvoiceRepository interface {
+	Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
+	GetByID(ctx context.Context, authorID, id uint) (*domain.RecurringInvoice, error)
+	LockByID(ctx context.Context, tx Transaction, authorID, id uint) (*domain.RecurringInvoice, error)
+	GetItems(ctx context.Context, recurringID uint) ([]domain.RecurringInvoiceItem, error)
+	GetDueIDs(ctx context.Context, date string, limit int) ([]uint, error)
+	LockDue(ctx context.Context, tx Transaction, id uint, date string) (*domain.RecurringInvoice, error)
+	Create(ctx context.Context, tx Transaction, data *domain.RecurringInvoice) error
+	CreateItems(ctx context.Context, tx Transaction, data []domain.RecurringInvoiceItem) error
+	Update(ctx context.Context, tx Transaction, data *domain.RecurringInvoice) error
+	UpdateSchedule(ctx context.Context, tx Transaction, data *domain.RecurringInvoice) error
+	DeleteItems(ctx context.Context, tx Transaction, recurringID uint) error
+	SoftDelete(ctx context.Context, data *domain.RecurringInvoice) error
+}
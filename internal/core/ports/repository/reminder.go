@@ -0,0 +1,17 @@
+package portRepository
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type ReminderRepository interface {
+	// GetDue lists open invoices due between from and to that got no reminder at offset days yet
+	GetDue(ctx context.Context, offset int, from, to time.Time, limit int) ([]domain.Invoice, error)
+	GetByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceReminder, error)
+	// Claim stores a reminder unless one exists for its invoice, offset and channel, and reports whether it did
+	Claim(ctx context.Context, data *domain.InvoiceReminder) (bool, error)
+	Finish(ctx context.Context, data *domain.InvoiceReminder) error
+}
@@ -0,0 +1,15 @@
+package portRepository
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type TaxRateRepository interface {
+	GetByAuthorID(ctx context.Context, authorID uint) ([]domain.TaxRate, error)
+	GetByID(ctx context.Context, authorID, id uint) (*domain.TaxRate, error)
+	GetByIDs(ctx context.Context, authorID uint, ids []uint) ([]domain.TaxRate, error)
+	Create(ctx context.Context, data *domain.TaxRate) error
+	Update(ctx context.Context, data *domain.TaxRate) error
+}
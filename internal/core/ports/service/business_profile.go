@@ -0,0 +1,16 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type BusinessProfileService interface {
+	Get(ctx context.Context, userID uint) ([]domain.BusinessProfileResponse, error)
+	GetByID(ctx context.Context, id, userID uint) (*domain.BusinessProfileResponse, error)
+	Create(ctx context.Context, req *domain.BusinessProfileRequest) (*domain.BusinessProfileResponse, error)
+	Update(ctx context.Context, req *domain.BusinessProfileRequest) error
+	UploadLogo(ctx context.Context, req *domain.BusinessProfileLogoRequest) error
+	Delete(ctx context.Context, id, userID uint) error
+}
@@ -0,0 +1,16 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type CreditNoteService interface {
+	Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
+	GetByID(ctx context.Context, id, userID uint) (*domain.CreditNoteResponse, error)
+	Create(ctx context.Context, req *domain.CreditNoteRequest) (*domain.CreditNoteResponse, error)
+	Apply(ctx context.Context, req *domain.ApplyCreditRequest) (*domain.CreditNoteResponse, error)
+	Refund(ctx context.Context, req *domain.RefundCreditRequest) (*domain.CreditNoteResponse, error)
+	GetPDF(ctx context.Context, id, userID uint) ([]byte, error)
+}
@@ -0,0 +1,16 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type CustomerService interface {
+	Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
+	GetByID(ctx context.Context, id, userID uint) (*domain.CustomerResponse, error)
+	Create(ctx context.Context, req *domain.CustomerRequest) (*domain.CustomerResponse, error)
+	Update(ctx context.Context, req *domain.CustomerRequest) error
+	Delete(ctx context.Context, id, userID uint) error
+	DedupeFromInvoices(ctx context.Context, dryRun bool) (*domain.CustomerDedupeResult, error)
+}
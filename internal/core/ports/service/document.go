@@ -0,0 +1,12 @@
+package portService
+
+import (
+	"context"
+	"time"
+)
+
+type DocumentService interface {
+	// Cleanup removes cached renderings that can no longer be served or are past their retention,
+	// it returns how many were removed
+	Cleanup(ctx context.Context, now time.Time) (int, error)
+}
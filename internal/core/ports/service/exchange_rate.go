@@ -0,0 +1,14 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type ExchangeRateService interface {
+	GetCurrencies(ctx context.Context) []domain.CurrencyResponse
+	SetBaseCurrency(ctx context.Context, req *domain.BaseCurrencyRequest) error
+	Get(ctx context.Context, userID uint) ([]domain.ExchangeRateResponse, error)
+	Save(ctx context.Context, req *domain.ExchangeRateRequest) (*domain.ExchangeRateResponse, error)
+}
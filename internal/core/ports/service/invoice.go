@@ -4,12 +4,25 @@ import (
 	"context"
 
 	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
 )
 
 type InvoiceService interface {
-	Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
-	GetByID(ctx context.Context, invoiceID int64, userID uint) (*domain.InvoiceResponse, error)
-	Create(ctx context.Context, req *domain.InvoiceRequest) error
-	Update(ctx context.Context, req *domain.InvoiceRequest) error
-	GetPDF(ctx context.Context, invoiceID int64, userID uint) ([]byte, error)
+	Get(ctx context.Context, req *domain.InvoicePageReq) (*domain.PaginationResponse, error)
+	GetByID(ctx context.Context, invoiceID string, userID uint) (*domain.InvoiceResponse, error)
+	Create(ctx context.Context, req *domain.InvoiceRequest) (string, error)
+	// CreateTx creates an invoice in the transaction of the caller, e.g. together with the quote lines it invoices
+	CreateTx(ctx context.Context, tx portRepository.Transaction, req *domain.InvoiceRequest) (string, error)
+	// Check reports the error Create would return for the request without creating anything
+	Check(ctx context.Context, req *domain.InvoiceRequest) error
+	// Update returns the version the invoice has after the change
+	Update(ctx context.Context, req *domain.InvoiceRequest) (int, error)
+	Send(ctx context.Context, invoiceID string, userID uint) error
+	Void(ctx context.Context, invoiceID string, userID uint) error
+	MarkPaid(ctx context.Context, invoiceID string, userID uint) error
+	GetPDF(ctx context.Context, invoiceID string, userID uint) ([]byte, error)
+	GetDeleted(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
+	Delete(ctx context.Context, invoiceID string, userID uint) error
+	Restore(ctx context.Context, invoiceID string, userID uint) error
+	Purge(ctx context.Context, invoiceID string, userID uint) error
 }
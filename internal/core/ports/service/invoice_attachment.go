@@ -0,0 +1,15 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type InvoiceAttachmentService interface {
+	Upload(ctx context.Context, req *domain.InvoiceAttachmentRequest) (*domain.InvoiceAttachmentResponse, error)
+	Get(ctx context.Context, invoiceID string, userID uint) ([]domain.InvoiceAttachmentResponse, error)
+	// Download returns an attachment with the content of its file
+	Download(ctx context.Context, invoiceID string, id, userID uint) (*domain.InvoiceAttachment, []byte, error)
+	Delete(ctx context.Context, invoiceID string, id, userID uint) error
+}
@@ -0,0 +1,23 @@
+package portService
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type InvoiceExportService interface {
+	// Prepare fills the defaults of an export and checks its filters before anything is written.
+	// An export too large to stream is queued as a background export, which is returned.
+	Prepare(ctx context.Context, req *domain.InvoiceExportRequest) (*domain.InvoiceExportResponse, error)
+	// Write streams a prepared export to w and returns how many rows it wrote
+	Write(ctx context.Context, req *domain.InvoiceExportRequest, w io.Writer) (int, error)
+	Create(ctx context.Context, req *domain.InvoiceExportRequest) (*domain.InvoiceExportResponse, error)
+	GetByID(ctx context.Context, id, userID uint) (*domain.InvoiceExportResponse, error)
+	// Download returns the file of a finished background export
+	Download(ctx context.Context, id, userID uint) (*domain.InvoiceExport, []byte, error)
+	// RunPending runs the background exports waiting on now and removes expired files, it returns how many ran
+	RunPending(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,11 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type InvoiceImportService interface {
+	Import(ctx context.Context, req *domain.InvoiceImportRequest) (*domain.InvoiceImportResponse, error)
+}
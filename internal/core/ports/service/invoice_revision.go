@@ -0,0 +1,13 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type InvoiceRevisionService interface {
+	Get(ctx context.Context, invoiceID string, userID uint) ([]domain.InvoiceRevisionResponse, error)
+	GetByRevision(ctx context.Context, invoiceID string, revision int, userID uint) (*domain.InvoiceRevisionResponse, error)
+	Diff(ctx context.Context, invoiceID string, req *domain.InvoiceRevisionDiffRequest, userID uint) (*domain.InvoiceRevisionDiffResponse, error)
+}
@@ -0,0 +1,15 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type InvoiceShareService interface {
+	Get(ctx context.Context, invoiceID string, userID uint) ([]domain.InvoiceShareResponse, error)
+	Create(ctx context.Context, req *domain.InvoiceShareRequest) (*domain.InvoiceShareResponse, error)
+	Revoke(ctx context.Context, invoiceID string, shareID, userID uint) error
+	View(ctx context.Context, token string) (*domain.InvoiceResponse, error)
+	ViewPDF(ctx context.Context, token string) ([]byte, error)
+}
@@ -0,0 +1,12 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type NumberingService interface {
+	Get(ctx context.Context, userID uint) ([]domain.NumberingSchemeResponse, error)
+	Save(ctx context.Context, req *domain.NumberingSchemeRequest) (*domain.NumberingSchemeResponse, error)
+}
@@ -0,0 +1,13 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type PaymentService interface {
+	Get(ctx context.Context, invoiceID string, userID uint) ([]domain.PaymentResponse, error)
+	Create(ctx context.Context, req *domain.PaymentRequest) (*domain.PaymentResponse, error)
+	Reverse(ctx context.Context, req *domain.ReversePaymentRequest) error
+}
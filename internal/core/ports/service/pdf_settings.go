@@ -0,0 +1,12 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type PDFSettingsService interface {
+	Get(ctx context.Context, userID uint) (*domain.PDFSettingsResponse, error)
+	Update(ctx context.Context, req *domain.PDFSettingsRequest) (*domain.PDFSettingsResponse, error)
+}
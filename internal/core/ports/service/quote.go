@@ -0,0 +1,20 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type QuoteService interface {
+	Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
+	GetByID(ctx context.Context, id, userID uint) (*domain.QuoteResponse, error)
+	Create(ctx context.Context, req *domain.QuoteRequest) (*domain.QuoteResponse, error)
+	Update(ctx context.Context, req *domain.QuoteRequest) error
+	Delete(ctx context.Context, id, userID uint) error
+	Send(ctx context.Context, id, userID uint) error
+	Accept(ctx context.Context, id, userID uint) error
+	Decline(ctx context.Context, id, userID uint) error
+	Convert(ctx context.Context, req *domain.QuoteConvertRequest) (*domain.InvoiceCreateResponse, error)
+	GetPDF(ctx context.Context, id, userID uint) ([]byte, error)
+}
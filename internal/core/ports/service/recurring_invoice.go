@@ -0,0 +1,20 @@
+package portService
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type RecurringInvoiceService interface {
+	Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error)
+	GetByID(ctx context.Context, id, userID uint) (*domain.RecurringInvoiceResponse, error)
+	Create(ctx context.Context, req *domain.RecurringInvoiceRequest) (*domain.RecurringInvoiceResponse, error)
+	Update(ctx context.Context, req *domain.RecurringInvoiceRequest) error
+	Delete(ctx context.Context, id, userID uint) error
+	Pause(ctx context.Context, id, userID uint) error
+	Resume(ctx context.Context, id, userID uint) error
+	Preview(ctx context.Context, id, userID uint, count int) ([]string, error)
+	RunDue(ctx context.Context, now time.Time) (int, error)
+}
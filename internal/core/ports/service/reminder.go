@@ -0,0 +1,14 @@
+package portService
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type ReminderService interface {
+	Get(ctx context.Context, invoiceID string, userID uint) ([]domain.InvoiceReminderResponse, error)
+	// RunDue marks invoices overdue and sends the reminders due on the date of now, it returns how many were sent
+	RunDue(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,13 @@
+package portService
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+type TaxRateService interface {
+	Get(ctx context.Context, userID uint) ([]domain.TaxRateResponse, error)
+	Create(ctx context.Context, req *domain.TaxRateRequest) (*domain.TaxRateResponse, error)
+	Update(ctx context.Context, req *domain.TaxRateRequest) error
+}
@@ -1,6 +1,8 @@
 package portService
 
 import (
+	"time"
+
 	"app/xonvera-core/internal/core/domain"
 )
 
@@ -9,4 +11,7 @@ type TokenService interface {
 	GenerateTokenPair(userID uint) (*domain.TokenPair, error)
 	ValidateToken(token string) (uint, error)
 	ValidateRefreshToken(token string) (uint, error)
+	ShareExpiry(now time.Time) time.Time
+	GenerateShareToken(shareID uint, expiresAt time.Time) (string, error)
+	ValidateShareToken(token string) (uint, error)
 }
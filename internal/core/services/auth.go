@@ -58,9 +58,10 @@ func (s *authService) Register(ctx context.Context, req *domain.RegisterRequest)
 
 	// Create new user
 	user := &domain.User{
-		Name:     req.Name,
-		Phone:    req.Phone,
-		Password: string(hashedPassword),
+		Name:         req.Name,
+		Phone:        req.Phone,
+		Password:     string(hashedPassword),
+		BaseCurrency: domain.DefaultCurrency,
 	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+type businessProfileService struct {
+	repo  portRepository.BusinessProfileRepository
+	blobs portRepository.BlobStore
+	tx    portRepository.TxRepository
+}
+
+func NewBusinessProfileService(
+	repo portRepository.BusinessProfileRepository,
+	blobs portRepository.BlobStore,
+	tx portRepository.TxRepository,
+) portService.BusinessProfileService {
+	return &businessProfileService{
+		repo:  repo,
+		blobs: blobs,
+		tx:    tx,
+	}
+}
+
+func (s *businessProfileService) Get(ctx context.Context, userID uint) ([]domain.BusinessProfileResponse, error) {
+	profiles, err := s.repo.GetByAuthorID(ctx, userID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get business profiles", zap.Error(err))
+		return nil, err
+	}
+
+	ids := make([]uint, len(profiles))
+	for i, v := range profiles {
+		ids[i] = v.ID
+	}
+	accounts, err := s.repo.GetBankAccounts(ctx, ids)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get bank accounts", zap.Error(err))
+		return nil, err
+	}
+
+	accountsByProfile := make(map[uint][]domain.BankAccount, len(profiles))
+	for _, v := range accounts {
+		accountsByProfile[v.ProfileID] = append(accountsByProfile[v.ProfileID], v)
+	}
+
+	res := make([]domain.BusinessProfileResponse, len(profiles))
+	for i, v := range profiles {
+		res[i] = v.Response(accountsByProfile[v.ID])
+	}
+	return res, nil
+}
+
+func (s *businessProfileService) GetByID(ctx context.Context, id, userID uint) (*domain.BusinessProfileResponse, error) {
+	profile, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.repo.GetBankAccounts(ctx, []uint{profile.ID})
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get bank accounts", zap.Error(err), zap.Uint("profile_id", id))
+		return nil, err
+	}
+
+	res := profile.Response(accounts)
+	return &res, nil
+}
+
+// Create adds a business profile, the first profile of a user always becomes the default
+func (s *businessProfileService) Create(ctx context.Context, req *domain.BusinessProfileRequest) (*domain.BusinessProfileResponse, error) {
+	current, err := s.repo.GetDefault(ctx, req.UserID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get default business profile", zap.Error(err))
+		return nil, err
+	}
+
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	t := time.Now()
+	data := domain.BusinessProfile{
+		AuthorID:        req.UserID,
+		Name:            req.Name,
+		LegalName:       req.LegalName,
+		Address:         req.Address,
+		TaxID:           req.TaxID,
+		Email:           req.Email,
+		Phone:           req.Phone,
+		PaymentTermDays: req.PaymentTermDays,
+		FooterText:      req.FooterText,
+		IsDefault:       req.IsDefault || current == nil,
+		Timestamp:       domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+
+	if err = s.clearDefault(ctx, tx, &data); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.Create(ctx, tx, &data); err != nil {
+		logger.StdContextError(ctx, "failed to create business profile", zap.Error(err))
+		return nil, err
+	}
+
+	accounts, err := s.saveBankAccounts(ctx, tx, &data, req.BankAccounts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "business profile created successfully", zap.Uint("profile_id", data.ID))
+	res := data.Response(accounts)
+	return &res, nil
+}
+
+// Update changes a business profile. Invoices print the profile as it is when their PDF is generated.
+func (s *businessProfileService) Update(ctx context.Context, req *domain.BusinessProfileRequest) error {
+	profile, err := s.repo.GetByID(ctx, req.UserID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	profile.Name = req.Name
+	profile.LegalName = req.LegalName
+	profile.Address = req.Address
+	profile.TaxID = req.TaxID
+	profile.Email = req.Email
+	profile.Phone = req.Phone
+	profile.PaymentTermDays = req.PaymentTermDays
+	profile.FooterText = req.FooterText
+	// The default can only move to another profile, never be unset
+	profile.IsDefault = profile.IsDefault || req.IsDefault
+	profile.UpdatedAt = time.Now()
+
+	if err = s.clearDefault(ctx, tx, profile); err != nil {
+		return err
+	}
+
+	if err = s.repo.Update(ctx, tx, profile); err != nil {
+		logger.StdContextError(ctx, "failed to update business profile", zap.Error(err), zap.Uint("profile_id", req.ID))
+		return err
+	}
+
+	if _, err = s.saveBankAccounts(ctx, tx, profile, req.BankAccounts); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "business profile updated successfully", zap.Uint("profile_id", req.ID))
+	return nil
+}
+
+// clearDefault unsets the previous default before a profile becomes the default, only one default is allowed
+func (s *businessProfileService) clearDefault(ctx context.Context, tx portRepository.Transaction, profile *domain.BusinessProfile) error {
+	if !profile.IsDefault {
+		return nil
+	}
+	if err := s.repo.ClearDefault(ctx, tx, profile.AuthorID, profile.ID); err != nil {
+		logger.StdContextError(ctx, "failed to clear default business profile", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// saveBankAccounts replaces the bank accounts of a profile
+func (s *businessProfileService) saveBankAccounts(ctx context.Context, tx portRepository.Transaction, profile *domain.BusinessProfile, req []domain.BankAccountRequest) ([]domain.BankAccount, error) {
+	accounts := make([]domain.BankAccount, len(req))
+	for i, v := range req {
+		accounts[i] = domain.BankAccount{
+			ProfileID:     profile.ID,
+			BankName:      v.BankName,
+			AccountName:   v.AccountName,
+			AccountNumber: v.AccountNumber,
+			Timestamp:     domain.Timestamp{CreatedAt: profile.UpdatedAt, UpdatedAt: profile.UpdatedAt},
+		}
+	}
+
+	if err := s.repo.ReplaceBankAccounts(ctx, tx, profile.ID, accounts); err != nil {
+		logger.StdContextError(ctx, "failed to save bank accounts", zap.Error(err), zap.Uint("profile_id", profile.ID))
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// UploadLogo stores a png or jpeg logo for a profile in the blob store, replacing the previous one
+func (s *businessProfileService) UploadLogo(ctx context.Context, req *domain.BusinessProfileLogoRequest) error {
+	ext, ok := domain.LogoExtensions[req.ContentType]
+	if !ok || len(req.Content) == 0 || len(req.Content) > domain.MaxLogoSize {
+		return fmt.Errorf(domain.ErrInvalidLogo)
+	}
+
+	profile, err := s.repo.GetByID(ctx, req.UserID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("logos/%d/profile_%d.%s", profile.AuthorID, profile.ID, ext)
+	if err = s.blobs.Put(ctx, key, req.Content, req.ContentType); err != nil {
+		logger.StdContextError(ctx, "failed to save logo", zap.Error(err), zap.Uint("profile_id", profile.ID))
+		return err
+	}
+
+	// A logo of the other image type is left behind when the type changes
+	if profile.LogoPath != "" && profile.LogoPath != key {
+		if err = s.blobs.Delete(ctx, profile.LogoPath); err != nil {
+			logger.StdContextWarn(ctx, "failed to remove previous logo", zap.Error(err), zap.Uint("profile_id", profile.ID))
+		}
+	}
+
+	profile.LogoPath = key
+	profile.UpdatedAt = time.Now()
+	if err = s.repo.UpdateLogo(ctx, profile); err != nil {
+		logger.StdContextError(ctx, "failed to update logo", zap.Error(err), zap.Uint("profile_id", profile.ID))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "business profile logo uploaded successfully", zap.Uint("profile_id", profile.ID))
+	return nil
+}
+
+// Delete removes a profile, invoices issued with it keep their issuer name
+func (s *businessProfileService) Delete(ctx context.Context, id, userID uint) error {
+	profile, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	t := time.Now()
+	profile.DeletedAt = &t
+	profile.UpdatedAt = t
+
+	if err = s.repo.SoftDelete(ctx, profile); err != nil {
+		logger.StdContextError(ctx, "failed to delete business profile", zap.Error(err), zap.Uint("profile_id", id))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "business profile deleted successfully", zap.Uint("profile_id", id))
+	return nil
+}
@@ -0,0 +1,479 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/config"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"go.uber.org/zap"
+)
+
+type creditNoteService struct {
+	cfg           *config.AppConfig
+	repo          portRepository.CreditNoteRepository
+	invoiceRepo   portRepository.InvoiceRepository
+	userRepo      portRepository.UserRepository
+	profileRepo   portRepository.BusinessProfileRepository
+	numberingRepo portRepository.NumberingRepository
+	blobs         portRepository.BlobStore
+	documents     portRepository.DocumentStore
+	tx            portRepository.TxRepository
+}
+
+func NewCreditNoteService(
+	cfg *config.AppConfig,
+	repo portRepository.CreditNoteRepository,
+	invoiceRepo portRepository.InvoiceRepository,
+	userRepo portRepository.UserRepository,
+	profileRepo portRepository.BusinessProfileRepository,
+	numberingRepo portRepository.NumberingRepository,
+	blobs portRepository.BlobStore,
+	documents portRepository.DocumentStore,
+	tx portRepository.TxRepository,
+) portService.CreditNoteService {
+	return &creditNoteService{
+		cfg:           cfg,
+		repo:          repo,
+		invoiceRepo:   invoiceRepo,
+		userRepo:      userRepo,
+		profileRepo:   profileRepo,
+		numberingRepo: numberingRepo,
+		blobs:         blobs,
+		documents:     documents,
+		tx:            tx,
+	}
+}
+
+func (s *creditNoteService) Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
+	res, err := s.repo.Get(ctx, req)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get credit notes", zap.Error(err))
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetByID retrieves a credit note with its items and where its credit went
+func (s *creditNoteService) GetByID(ctx context.Context, id, userID uint) (*domain.CreditNoteResponse, error) {
+	note, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(ctx, note)
+}
+
+// detail builds the full credit note response, allocations refer to invoices by public ID
+func (s *creditNoteService) detail(ctx context.Context, note *domain.CreditNote) (*domain.CreditNoteResponse, error) {
+	items, err := s.repo.GetItems(ctx, note.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get credit note items", zap.Error(err), zap.Uint("credit_note_id", note.ID))
+		return nil, err
+	}
+
+	allocations, err := s.repo.GetAllocations(ctx, note.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get credit allocations", zap.Error(err), zap.Uint("credit_note_id", note.ID))
+		return nil, err
+	}
+
+	ids := []int64{note.InvoiceID}
+	for _, v := range allocations {
+		if v.InvoiceID != nil {
+			ids = append(ids, *v.InvoiceID)
+		}
+	}
+	invoices, err := s.invoiceRepo.GetByIDs(ctx, note.AuthorID, ids)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get credited invoices", zap.Error(err), zap.Uint("credit_note_id", note.ID))
+		return nil, err
+	}
+	refs := make(map[int64]domain.InvoiceRef, len(invoices))
+	for _, v := range invoices {
+		refs[v.ID] = domain.InvoiceRef{ID: v.PublicID, Number: v.Number}
+	}
+
+	res := note.Response(refs, items, allocations)
+	return &res, nil
+}
+
+// Create issues a credit note for items of a sent invoice.
+// Its total is applied to the balance of that invoice, what is left stays available as credit.
+func (s *creditNoteService) Create(ctx context.Context, req *domain.CreditNoteRequest) (*domain.CreditNoteResponse, error) {
+	issueDate, err := time.ParseInLocation(time.DateOnly, req.IssueDate, time.Local)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to parse issue date", zap.Error(err))
+		return nil, err
+	}
+
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	inv, err := s.invoiceRepo.LockByPublicID(ctx, tx, req.UserID, req.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status == domain.InvoiceStatusDraft || inv.Status == domain.InvoiceStatusVoid {
+		return nil, fmt.Errorf(domain.ErrInvoiceNotIssued)
+	}
+
+	items, err := s.creditItems(ctx, tx, inv, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	note := domain.NewCreditNote(inv)
+	note.IssueDate = issueDate.Format(time.DateOnly)
+	note.Reason = req.Reason
+	note.Timestamp = domain.Timestamp{CreatedAt: t, UpdatedAt: t}
+	note.SetTotals(items)
+
+	if note.Number, err = s.nextNumber(ctx, tx, req.UserID, issueDate); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.Create(ctx, tx, &note); err != nil {
+		logger.StdContextError(ctx, "failed to create credit note", zap.Error(err))
+		return nil, err
+	}
+
+	for i := range items {
+		items[i].CreditNoteID = note.ID
+		items[i].Timestamp = note.Timestamp
+	}
+	if err = s.repo.CreateItems(ctx, tx, items); err != nil {
+		logger.StdContextError(ctx, "failed to create credit note items", zap.Error(err))
+		return nil, err
+	}
+
+	// An invoice that is paid already keeps the whole credit for other invoices or a refund
+	if amount := min(note.Total, inv.Balance()); amount > 0 && inv.Status.AcceptsPayment() {
+		if err = s.allocate(ctx, tx, &note, inv, amount, t); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "credit note issued successfully",
+		zap.Uint("credit_note_id", note.ID),
+		zap.String("invoice_id", inv.PublicID),
+		zap.Int("total", note.Total),
+		zap.Int("applied", note.AmountApplied),
+	)
+	return s.detail(ctx, &note)
+}
+
+// creditItems prices the requested lines against what earlier credit notes of the invoice credited
+func (s *creditNoteService) creditItems(ctx context.Context, tx portRepository.Transaction, inv *domain.Invoice, req []domain.CreditNoteItemRequest) ([]domain.CreditNoteItem, error) {
+	invoiceItems, err := s.invoiceRepo.GetItemsByInvoiceID(ctx, inv.AuthorID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get invoice items", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return nil, err
+	}
+	itemByID := make(map[uint]domain.InvoiceItem, len(invoiceItems))
+	for _, v := range invoiceItems {
+		itemByID[v.ID] = v
+	}
+
+	credited, err := s.repo.GetCreditedItems(ctx, tx, inv.AuthorID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get credited items", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return nil, err
+	}
+	creditedByID := make(map[uint]domain.CreditedItem, len(credited))
+	for _, v := range credited {
+		creditedByID[v.InvoiceItemID] = v
+	}
+
+	items := make([]domain.CreditNoteItem, 0, len(req))
+	seen := make(map[uint]bool, len(req))
+	for _, v := range req {
+		item, ok := itemByID[v.ItemID]
+		if !ok || seen[v.ItemID] {
+			return nil, fmt.Errorf(domain.ErrInvalidCreditItem)
+		}
+		seen[v.ItemID] = true
+
+		line, ok := domain.CreditItem(item, creditedByID[v.ItemID], v.Qty)
+		if !ok {
+			return nil, fmt.Errorf(domain.ErrInvalidCreditItem)
+		}
+		items = append(items, line)
+	}
+	return items, nil
+}
+
+// nextNumber takes the credit note number of the issue date period
+func (s *creditNoteService) nextNumber(ctx context.Context, tx portRepository.Transaction, authorID uint, issueDate time.Time) (string, error) {
+	number, err := nextNumber(ctx, s.numberingRepo, tx, authorID, domain.DocumentCreditNote, issueDate)
+	if err != nil {
+		return "", err
+	}
+
+	// A template changed mid period can render a number issued before, the sequence is rolled back with tx
+	exists, err := s.repo.NumberExists(ctx, tx, authorID, number)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to check credit note number", zap.Error(err))
+		return "", err
+	}
+	if exists {
+		return "", fmt.Errorf(domain.ErrCreditNoteNumberExists)
+	}
+	return number, nil
+}
+
+// Apply moves remaining credit onto another open invoice of the same customer
+func (s *creditNoteService) Apply(ctx context.Context, req *domain.ApplyCreditRequest) (*domain.CreditNoteResponse, error) {
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	note, err := s.repo.LockByID(ctx, tx, req.UserID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount > note.Remaining() {
+		return nil, fmt.Errorf(domain.ErrCreditExceedsRemaining)
+	}
+
+	inv, err := s.invoiceRepo.LockByPublicID(ctx, tx, req.UserID, req.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !inv.Status.AcceptsPayment() || !note.SameCustomer(inv) || inv.Currency != note.Currency {
+		return nil, fmt.Errorf(domain.ErrCreditNotApplicable)
+	}
+	if req.Amount > inv.Balance() {
+		return nil, fmt.Errorf(domain.ErrCreditExceedsBalance)
+	}
+
+	if err = s.allocate(ctx, tx, note, inv, req.Amount, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "credit applied successfully",
+		zap.Uint("credit_note_id", note.ID),
+		zap.String("invoice_id", inv.PublicID),
+		zap.Int("amount", req.Amount),
+		zap.String("status", string(inv.Status)),
+	)
+	return s.detail(ctx, note)
+}
+
+// Refund records remaining credit paid back to the customer
+func (s *creditNoteService) Refund(ctx context.Context, req *domain.RefundCreditRequest) (*domain.CreditNoteResponse, error) {
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	note, err := s.repo.LockByID(ctx, tx, req.UserID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount > note.Remaining() {
+		return nil, fmt.Errorf(domain.ErrCreditExceedsRemaining)
+	}
+
+	t := time.Now()
+	allocation := domain.CreditAllocation{
+		AuthorID:     note.AuthorID,
+		CreditNoteID: note.ID,
+		Amount:       req.Amount,
+		Note:         req.Note,
+		Timestamp:    domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+	if err = s.repo.CreateAllocation(ctx, tx, &allocation); err != nil {
+		logger.StdContextError(ctx, "failed to create credit refund", zap.Error(err), zap.Uint("credit_note_id", note.ID))
+		return nil, err
+	}
+
+	note.AmountRefunded += req.Amount
+	note.UpdatedAt = t
+	if err = s.repo.UpdateBalance(ctx, tx, note); err != nil {
+		logger.StdContextError(ctx, "failed to update credit note balance", zap.Error(err), zap.Uint("credit_note_id", note.ID))
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "credit refunded successfully", zap.Uint("credit_note_id", note.ID), zap.Int("amount", req.Amount))
+	return s.detail(ctx, note)
+}
+
+// allocate applies credit to an invoice and rolls its status the way a payment would
+func (s *creditNoteService) allocate(ctx context.Context, tx portRepository.Transaction, note *domain.CreditNote, inv *domain.Invoice, amount int, t time.Time) error {
+	allocation := domain.CreditAllocation{
+		AuthorID:     note.AuthorID,
+		CreditNoteID: note.ID,
+		InvoiceID:    &inv.ID,
+		Amount:       amount,
+		Timestamp:    domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+	if err := s.repo.CreateAllocation(ctx, tx, &allocation); err != nil {
+		logger.StdContextError(ctx, "failed to create credit allocation", zap.Error(err), zap.Uint("credit_note_id", note.ID))
+		return err
+	}
+
+	note.AmountApplied += amount
+	note.UpdatedAt = t
+	if err := s.repo.UpdateBalance(ctx, tx, note); err != nil {
+		logger.StdContextError(ctx, "failed to update credit note balance", zap.Error(err), zap.Uint("credit_note_id", note.ID))
+		return err
+	}
+
+	credited, err := s.repo.SumAllocations(ctx, tx, inv.AuthorID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to sum invoice credit", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return err
+	}
+
+	inv.AmountCredited = credited
+	inv.Status = inv.SettledStatus(t)
+	inv.Version++
+	inv.UpdatedAt = t
+	if err = s.invoiceRepo.UpdateSettlement(ctx, tx, inv); err != nil {
+		logger.StdContextError(ctx, "failed to update invoice settlement", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return err
+	}
+	return nil
+}
+
+func (s *creditNoteService) GetPDF(ctx context.Context, id, userID uint) ([]byte, error) {
+	note, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := loadPDFSettings(ctx, s.userRepo, note.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail, err := s.detail(ctx, note)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer, err := loadPDFIssuer(ctx, s.profileRepo, s.blobs, note.AuthorID, note.ProfileID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetItems(ctx, note.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get credit note items", zap.Error(err), zap.Uint("credit_note_id", note.ID))
+		return nil, err
+	}
+
+	// Credit notes never change once issued, the key still follows the issuer and the settings of the account
+	key, err := domain.DocumentKey(domain.DocumentCreditNote, strconv.FormatUint(uint64(note.ID), 10), pdfLayoutVersion, settings, detail, items, issuer.content())
+	if err != nil {
+		logger.StdContextError(ctx, "failed to build pdf key", zap.Error(err), zap.Uint("credit_note_id", note.ID))
+		return nil, err
+	}
+	if pdfBytes, ok := cachedPDF(ctx, s.documents, key); ok {
+		return pdfBytes, nil
+	}
+
+	m := s.generatePDF(*detail, note.Taxes(items), issuer, settings)
+	doc, err := m.Generate()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to generate pdf", zap.Error(err), zap.Uint("credit_note_id", note.ID))
+		return nil, err
+	}
+
+	pdfBytes := doc.GetBytes()
+	storePDF(ctx, s.documents, key, pdfBytes)
+
+	logger.StdContextInfo(ctx, "pdf generated successfully", zap.Uint("credit_note_id", note.ID), zap.Int("size_bytes", len(pdfBytes)))
+	return pdfBytes, nil
+}
+
+func (s *creditNoteService) generatePDF(data domain.CreditNoteResponse, taxes []domain.InvoiceTax, issuer *pdfIssuer, settings domain.PDFSettings) core.Maroto {
+	d := newPDFDocument(settings, s.cfg.Env == "development")
+
+	d.header(issuer, d.labels.CreditNote, data.Number, d.dateLine(d.labels.IssueDate, data.IssueDate))
+	d.line(d.labels.InvoiceRef+" "+data.Invoice.Number, fontstyle.Normal)
+	d.line(data.Reason, fontstyle.Italic)
+	d.space()
+
+	d.parties(issuer, data.Issuer, pdfParty{
+		Name:    data.Customer,
+		Company: data.CustomerCompany,
+		Address: data.CustomerAddress,
+		Email:   data.CustomerEmail,
+		Phone:   data.CustomerPhone,
+		TaxID:   data.CustomerTaxID,
+	})
+
+	d.space()
+
+	// Credited amounts are net of the discounts the invoice gave, in the pricing basis of the invoice
+	items := make([]pdfItem, len(data.Items))
+	for i, item := range data.Items {
+		items[i] = pdfItem{
+			Description: item.Description,
+			Qty:         item.Qty,
+			Price:       item.Price,
+			Amount:      item.Subtotal,
+		}
+		if data.TaxMode == domain.TaxModeInclusive {
+			items[i].Amount = item.Total
+		}
+		if item.TaxRateID != nil {
+			items[i].TaxRate = &item.TaxRate
+		}
+	}
+	d.items(items, data.Currency)
+
+	subtotal := data.Subtotal
+	if data.TaxMode == domain.TaxModeInclusive {
+		subtotal = data.Total
+	}
+	taxResponses := make([]domain.InvoiceTaxResponse, len(taxes))
+	for i := range taxes {
+		taxResponses[i] = taxes[i].Response()
+	}
+	d.totals(pdfTotals{
+		Subtotal:   subtotal,
+		Taxes:      taxResponses,
+		TaxMode:    data.TaxMode,
+		Total:      data.Total,
+		Currency:   data.Currency,
+		TotalLabel: d.labels.TotalCredit,
+	})
+
+	d.footer(issuer, false)
+	return d.m
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+type customerService struct {
+	repo        portRepository.CustomerRepository
+	invoiceRepo portRepository.InvoiceRepository
+	tx          portRepository.TxRepository
+}
+
+func NewCustomerService(
+	repo portRepository.CustomerRepository,
+	invoiceRepo portRepository.InvoiceRepository,
+	tx portRepository.TxRepository,
+) portService.CustomerService {
+	return &customerService{
+		repo:        repo,
+		invoiceRepo: invoiceRepo,
+		tx:          tx,
+	}
+}
+
+func (s *customerService) Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
+	res, err := s.repo.Get(ctx, req)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get customers", zap.Error(err))
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *customerService) GetByID(ctx context.Context, id, userID uint) (*domain.CustomerResponse, error) {
+	customer, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	res := customer.Response()
+	return &res, nil
+}
+
+func (s *customerService) Create(ctx context.Context, req *domain.CustomerRequest) (*domain.CustomerResponse, error) {
+	t := time.Now()
+	data := domain.Customer{
+		AuthorID:  req.UserID,
+		Name:      req.Name,
+		Company:   req.Company,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Address:   req.Address,
+		TaxID:     req.TaxID,
+		Notes:     req.Notes,
+		Timestamp: domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+
+	if err := s.repo.Create(ctx, nil, &data); err != nil {
+		logger.StdContextError(ctx, "failed to create customer", zap.Error(err))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "customer created successfully", zap.Uint("customer_id", data.ID))
+	res := data.Response()
+	return &res, nil
+}
+
+// Update changes a customer. Issued invoices keep the details they were snapshotted with.
+func (s *customerService) Update(ctx context.Context, req *domain.CustomerRequest) error {
+	customer, err := s.repo.GetByID(ctx, req.UserID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	customer.Name = req.Name
+	customer.Company = req.Company
+	customer.Email = req.Email
+	customer.Phone = req.Phone
+	customer.Address = req.Address
+	customer.TaxID = req.TaxID
+	customer.Notes = req.Notes
+	customer.UpdatedAt = time.Now()
+
+	if err = s.repo.Update(ctx, customer); err != nil {
+		logger.StdContextError(ctx, "failed to update customer", zap.Error(err), zap.Uint("customer_id", req.ID))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "customer updated successfully", zap.Uint("customer_id", req.ID))
+	return nil
+}
+
+func (s *customerService) Delete(ctx context.Context, id, userID uint) error {
+	customer, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	t := time.Now()
+	customer.DeletedAt = &t
+	customer.UpdatedAt = t
+
+	if err = s.repo.SoftDelete(ctx, customer); err != nil {
+		logger.StdContextError(ctx, "failed to delete customer", zap.Error(err), zap.Uint("customer_id", id))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "customer deleted successfully", zap.Uint("customer_id", id))
+	return nil
+}
+
+// DedupeFromInvoices turns the free text customers of unlinked invoices into customers.
+// Spellings that normalize to the same name share one customer, named after the most used spelling,
+// and an existing customer with a matching name is reused. With dryRun nothing is written.
+func (s *customerService) DedupeFromInvoices(ctx context.Context, dryRun bool) (*domain.CustomerDedupeResult, error) {
+	spellings, err := s.invoiceRepo.GetUnlinkedCustomers(ctx)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get unlinked invoice customers", zap.Error(err))
+		return nil, err
+	}
+
+	// spellings are ordered by author, most used spelling first
+	var result domain.CustomerDedupeResult
+	for start := 0; start < len(spellings); {
+		end := start
+		for end < len(spellings) && spellings[end].AuthorID == spellings[start].AuthorID {
+			end++
+		}
+
+		if err = s.dedupeAuthor(ctx, spellings[start:end], dryRun, &result); err != nil {
+			return nil, err
+		}
+		start = end
+	}
+
+	logger.StdContextInfo(ctx, "customer dedupe finished",
+		zap.Bool("dry_run", dryRun),
+		zap.Int("customers_created", result.CustomersCreated),
+		zap.Int("invoices_linked", result.InvoicesLinked),
+	)
+	return &result, nil
+}
+
+func (s *customerService) dedupeAuthor(ctx context.Context, spellings []domain.UnlinkedCustomer, dryRun bool, result *domain.CustomerDedupeResult) error {
+	authorID := spellings[0].AuthorID
+
+	existing, err := s.repo.GetByAuthorID(ctx, authorID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get customers", zap.Error(err), zap.Uint("author_id", authorID))
+		return err
+	}
+	customerByKey := make(map[string]domain.Customer, len(existing))
+	for _, v := range existing {
+		key := domain.NormalizeCustomerName(v.Name)
+		if _, ok := customerByKey[key]; !ok {
+			customerByKey[key] = v
+		}
+	}
+
+	var keys []string
+	groups := make(map[string][]domain.UnlinkedCustomer)
+	for _, v := range spellings {
+		key := domain.NormalizeCustomerName(v.Customer)
+		if key == "" {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], v)
+	}
+
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, key := range keys {
+		group := groups[key]
+		names := make([]string, len(group))
+		invoices := 0
+		for i, v := range group {
+			names[i] = v.Customer
+			invoices += v.Total
+		}
+
+		customer, ok := customerByKey[key]
+		if !ok {
+			result.CustomersCreated++
+		}
+		if dryRun {
+			result.InvoicesLinked += invoices
+			continue
+		}
+
+		if !ok {
+			t := time.Now()
+			customer = domain.Customer{
+				AuthorID:  authorID,
+				Name:      strings.TrimSpace(group[0].Customer),
+				Timestamp: domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+			}
+			if err = s.repo.Create(ctx, tx, &customer); err != nil {
+				logger.StdContextError(ctx, "failed to create customer", zap.Error(err), zap.Uint("author_id", authorID))
+				return err
+			}
+		}
+
+		linked, err := s.invoiceRepo.LinkCustomer(ctx, tx, authorID, names, customer.ID)
+		if err != nil {
+			logger.StdContextError(ctx, "failed to link invoices to customer", zap.Error(err), zap.Uint("customer_id", customer.ID))
+			return err
+		}
+		result.InvoicesLinked += int(linked)
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return err
+	}
+	return nil
+}
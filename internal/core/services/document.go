@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/config"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+type documentService struct {
+	store     portRepository.DocumentStore
+	retention time.Duration
+}
+
+func NewDocumentService(cfg *config.DocumentConfig, store portRepository.DocumentStore) portService.DocumentService {
+	return &documentService{
+		store:     store,
+		retention: cfg.Retention,
+	}
+}
+
+// Cleanup removes renderings superseded by a newer one of the same document, renderings older than the
+// retention and files outside of a document folder such as caches of older releases.
+// Cached PDFs are keyed by their content, so a removed one is only rendered again when it is downloaded.
+func (s *documentService) Cleanup(ctx context.Context, now time.Time) (int, error) {
+	docs, err := s.store.List(ctx, "")
+	if err != nil {
+		logger.StdContextError(ctx, "failed to list cached documents", zap.Error(err))
+		return 0, err
+	}
+
+	var removed int
+	for _, v := range domain.StaleDocuments(docs, now, s.retention) {
+		if err = s.store.Delete(ctx, v.Key); err != nil {
+			logger.StdContextWarn(ctx, "failed to remove cached document", zap.Error(err), zap.String("document_key", v.Key))
+			continue
+		}
+		removed++
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if removed > 0 {
+		logger.StdContextInfo(ctx, "cached documents removed", zap.Int("removed", removed), zap.Int("stored", len(docs)))
+	}
+	return removed, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+type exchangeRateService struct {
+	repo     portRepository.ExchangeRateRepository
+	userRepo portRepository.UserRepository
+}
+
+func NewExchangeRateService(repo portRepository.ExchangeRateRepository, userRepo portRepository.UserRepository) portService.ExchangeRateService {
+	return &exchangeRateService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *exchangeRateService) GetCurrencies(ctx context.Context) []domain.CurrencyResponse {
+	currencies := domain.Currencies()
+	res := make([]domain.CurrencyResponse, len(currencies))
+	for i, v := range currencies {
+		res[i] = v.Response()
+	}
+	return res
+}
+
+// SetBaseCurrency changes the currency reports are aggregated in.
+// Issued invoices keep the base currency and rate they were stored with.
+func (s *exchangeRateService) SetBaseCurrency(ctx context.Context, req *domain.BaseCurrencyRequest) error {
+	currency, ok := domain.LookupCurrency(req.Currency)
+	if !ok {
+		return fmt.Errorf(domain.ErrInvalidCurrency)
+	}
+
+	if err := s.userRepo.UpdateBaseCurrency(ctx, req.UserID, currency.Code); err != nil {
+		logger.StdContextError(ctx, "failed to update base currency", zap.Error(err), zap.Uint("user_id", req.UserID))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "base currency updated successfully", zap.Uint("user_id", req.UserID), zap.String("currency", currency.Code))
+	return nil
+}
+
+func (s *exchangeRateService) Get(ctx context.Context, userID uint) ([]domain.ExchangeRateResponse, error) {
+	rates, err := s.repo.GetByAuthorID(ctx, userID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get exchange rates", zap.Error(err))
+		return nil, err
+	}
+
+	res := make([]domain.ExchangeRateResponse, len(rates))
+	for i, v := range rates {
+		res[i] = v.Response()
+	}
+	return res, nil
+}
+
+// Save stores a manual rate against the account base currency
+func (s *exchangeRateService) Save(ctx context.Context, req *domain.ExchangeRateRequest) (*domain.ExchangeRateResponse, error) {
+	currency, ok := domain.LookupCurrency(req.Currency)
+	if !ok {
+		return nil, fmt.Errorf(domain.ErrInvalidCurrency)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get user", zap.Error(err), zap.Uint("user_id", req.UserID))
+		return nil, err
+	}
+	if strings.EqualFold(currency.Code, user.BaseCurrency) {
+		return nil, fmt.Errorf(domain.ErrInvalidCurrency)
+	}
+
+	rate, err := domain.ParseExchangeRate(req.Rate)
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	data := domain.ExchangeRate{
+		AuthorID:      req.UserID,
+		Currency:      currency.Code,
+		BaseCurrency:  user.BaseCurrency,
+		Rate:          rate,
+		EffectiveDate: req.EffectiveDate,
+		Timestamp:     domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+
+	if err = s.repo.Save(ctx, &data); err != nil {
+		logger.StdContextError(ctx, "failed to save exchange rate", zap.Error(err))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "exchange rate saved successfully", zap.String("currency", data.Currency), zap.String("base_currency", data.BaseCurrency))
+	res := data.Response()
+	return &res, nil
+}
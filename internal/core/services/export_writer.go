@@ -0,0 +1,227 @@
+package services
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+)
+
+// sheetWriter writes the rows of an export one by one, nothing is kept after a row is written
+type sheetWriter interface {
+	WriteHeader(names []string) error
+	WriteRow(cells []domain.ExportCell) error
+	Close() error
+}
+
+func newSheetWriter(format domain.ExportFormat, locale domain.Locale, w io.Writer) (sheetWriter, error) {
+	if format == domain.ExportXLSX {
+		return newXLSXWriter(locale, w)
+	}
+	return newCSVWriter(locale, w)
+}
+
+type csvSheetWriter struct {
+	w      *csv.Writer
+	locale domain.Locale
+}
+
+func newCSVWriter(locale domain.Locale, w io.Writer) (*csvSheetWriter, error) {
+	// The byte order mark makes spreadsheets read the file as UTF-8
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return nil, err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = locale.ListSeparator()
+	return &csvSheetWriter{w: cw, locale: locale}, nil
+}
+
+func (s *csvSheetWriter) WriteHeader(names []string) error {
+	return s.w.Write(names)
+}
+
+func (s *csvSheetWriter) WriteRow(cells []domain.ExportCell) error {
+	record := make([]string, len(cells))
+	for i, c := range cells {
+		record[i] = c.Format(s.locale)
+		// Text starting like a formula is kept as text when the file is opened in a spreadsheet
+		if c.Kind == domain.ExportText && record[i] != "" && strings.ContainsRune("=+-@\t\r", rune(record[i][0])) {
+			record[i] = "'" + record[i]
+		}
+	}
+	return s.w.Write(record)
+}
+
+func (s *csvSheetWriter) Close() error {
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// Cell styles of xlsxStyles
+const (
+	xlsxStyleText = iota
+	xlsxStyleHeader
+	xlsxStyleDate
+	xlsxStyleInteger
+	xlsxStyleDecimal
+)
+
+const (
+	xlsxMain = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	xlsxRels = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	xlsxPkg  = "http://schemas.openxmlformats.org/package/2006/relationships"
+)
+
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="` + xlsxPkg + `">` +
+		`<Relationship Id="rId1" Type="` + xlsxRels + `/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="` + xlsxMain + `" xmlns:r="` + xlsxRels + `">` +
+		`<sheets><sheet name="Invoices" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="` + xlsxPkg + `">` +
+		`<Relationship Id="rId1" Type="` + xlsxRels + `/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="` + xlsxRels + `/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+}
+
+// xlsxStyles has the cell styles in the order of the xlsxStyle constants, the date format follows the locale
+func xlsxStyles(locale domain.Locale) string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="` + xlsxMain + `">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="` + locale.DateLayout() + `"/></numFmts>` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="5">` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="3" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`</cellXfs>` +
+		`</styleSheet>`
+}
+
+// xlsxEpoch is day zero of spreadsheet date serials
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// xlsxSheetWriter streams a single sheet workbook, numbers and dates are written as values
+// so spreadsheets show them in the format of their own locale
+type xlsxSheetWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+func newXLSXWriter(locale domain.Locale, w io.Writer) (*xlsxSheetWriter, error) {
+	zw := zip.NewWriter(w)
+	for _, p := range xlsxParts {
+		if err := writeZipPart(zw, p.name, p.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeZipPart(zw, "xl/styles.xml", xlsxStyles(locale)); err != nil {
+		return nil, err
+	}
+
+	// The sheet is the last part of the archive, its rows go straight into it
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	if _, err = sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" + `<worksheet xmlns="` + xlsxMain + `"><sheetData>`); err != nil {
+		return nil, err
+	}
+	return &xlsxSheetWriter{zw: zw, sheet: sheet}, nil
+}
+
+func (s *xlsxSheetWriter) WriteHeader(names []string) error {
+	cells := make([]domain.ExportCell, len(names))
+	for i, v := range names {
+		cells[i] = domain.ExportCell{Kind: domain.ExportText, Text: v}
+	}
+	return s.write(cells, xlsxStyleHeader)
+}
+
+func (s *xlsxSheetWriter) WriteRow(cells []domain.ExportCell) error {
+	return s.write(cells, xlsxStyleText)
+}
+
+func (s *xlsxSheetWriter) write(cells []domain.ExportCell, textStyle int) error {
+	s.row++
+	fmt.Fprintf(s.sheet, `<row r="%d">`, s.row)
+	for i, c := range cells {
+		ref := xlsxColumn(i) + strconv.Itoa(s.row)
+		switch {
+		case c.Kind == domain.ExportNumber:
+			style := xlsxStyleInteger
+			if c.MinorUnits > 0 {
+				style = xlsxStyleDecimal
+			}
+			fmt.Fprintf(s.sheet, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, c.Decimal())
+		case c.Kind == domain.ExportDate && !c.Date.IsZero():
+			date := time.Date(c.Date.Year(), c.Date.Month(), c.Date.Day(), 0, 0, 0, 0, time.UTC)
+			fmt.Fprintf(s.sheet, `<c r="%s" s="%d"><v>%d</v></c>`, ref, xlsxStyleDate, int(date.Sub(xlsxEpoch).Hours()/24))
+		case c.Kind == domain.ExportText && c.Text != "":
+			fmt.Fprintf(s.sheet, `<c r="%s" t="inlineStr" s="%d"><is><t xml:space="preserve">`, ref, textStyle)
+			if err := xml.EscapeText(s.sheet, []byte(c.Text)); err != nil {
+				return err
+			}
+			s.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := s.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (s *xlsxSheetWriter) Close() error {
+	if _, err := s.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := s.sheet.Flush(); err != nil {
+		return err
+	}
+	return s.zw.Close()
+}
+
+func writeZipPart(zw *zip.Writer, name, content string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+// xlsxColumn returns the letters of a zero based column index, e.g. 0 => A, 26 => AA
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
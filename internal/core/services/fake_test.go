@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+)
+
+// fakeTx records how a transaction ended, a rollback after the commit is a no-op as with gorm
+type fakeTx struct {
+	committed  bool
+	rolledBack bool
+}
+
+func (t *fakeTx) Commit() error {
+	t.committed = true
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	if !t.committed {
+		t.rolledBack = true
+	}
+	return nil
+}
+
+// fakeTxRepository keeps every transaction it began
+type fakeTxRepository struct {
+	txs []*fakeTx
+}
+
+func (r *fakeTxRepository) Begin() (portRepository.Transaction, error) {
+	tx := &fakeTx{}
+	r.txs = append(r.txs, tx)
+	return tx, nil
+}
+
+// only returns the single transaction begun by the call under test
+func (r *fakeTxRepository) only(t *testing.T) *fakeTx {
+	t.Helper()
+	if len(r.txs) != 1 {
+		t.Fatalf("expected one transaction, got %d", len(r.txs))
+	}
+	return r.txs[0]
+}
@@ -3,7 +3,6 @@ package services
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"app/xonvera-core/internal/core/domain"
@@ -12,32 +11,67 @@ import (
 	"app/xonvera-core/internal/infrastructure/config"
 	"app/xonvera-core/internal/infrastructure/logger"
 
-	"github.com/johnfercher/maroto/v2"
-	"github.com/johnfercher/maroto/v2/pkg/components/text"
-	cfgPdf "github.com/johnfercher/maroto/v2/pkg/config"
-	"github.com/johnfercher/maroto/v2/pkg/consts/align"
-	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
-	"github.com/johnfercher/maroto/v2/pkg/consts/pagesize"
+	"github.com/google/uuid"
 	"github.com/johnfercher/maroto/v2/pkg/core"
-	"github.com/johnfercher/maroto/v2/pkg/props"
 	"go.uber.org/zap"
 )
 
 type invoiceService struct {
-	cfg  *config.AppConfig
-	repo portRepository.InvoiceRepository
-	tx   portRepository.TxRepository
+	cfg           *config.AppConfig
+	repo          portRepository.InvoiceRepository
+	revisionRepo  portRepository.InvoiceRevisionRepository
+	attachRepo    portRepository.InvoiceAttachmentRepository
+	blobs         portRepository.BlobStore
+	documents     portRepository.DocumentStore
+	taxRepo       portRepository.TaxRateRepository
+	userRepo      portRepository.UserRepository
+	customerRepo  portRepository.CustomerRepository
+	profileRepo   portRepository.BusinessProfileRepository
+	numberingRepo portRepository.NumberingRepository
+	rates         portRepository.ExchangeRateProvider
+	tx            portRepository.TxRepository
+	payment       portService.PaymentService
 }
 
-func NewInvoiceService(cfg *config.AppConfig, invoiceRepo portRepository.InvoiceRepository, tx portRepository.TxRepository) portService.InvoiceService {
+func NewInvoiceService(
+	cfg *config.AppConfig,
+	invoiceRepo portRepository.InvoiceRepository,
+	revisionRepo portRepository.InvoiceRevisionRepository,
+	attachRepo portRepository.InvoiceAttachmentRepository,
+	blobs portRepository.BlobStore,
+	documents portRepository.DocumentStore,
+	taxRepo portRepository.TaxRateRepository,
+	userRepo portRepository.UserRepository,
+	customerRepo portRepository.CustomerRepository,
+	profileRepo portRepository.BusinessProfileRepository,
+	numberingRepo portRepository.NumberingRepository,
+	rates portRepository.ExchangeRateProvider,
+	tx portRepository.TxRepository,
+	payment portService.PaymentService,
+) portService.InvoiceService {
 	return &invoiceService{
-		cfg:  cfg,
-		repo: invoiceRepo,
-		tx:   tx,
+		cfg:           cfg,
+		repo:          invoiceRepo,
+		revisionRepo:  revisionRepo,
+		attachRepo:    attachRepo,
+		blobs:         blobs,
+		documents:     documents,
+		taxRepo:       taxRepo,
+		userRepo:      userRepo,
+		customerRepo:  customerRepo,
+		profileRepo:   profileRepo,
+		numberingRepo: numberingRepo,
+		rates:         rates,
+		tx:            tx,
+		payment:       payment,
 	}
 }
 
-func (s *invoiceService) Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
+func (s *invoiceService) Get(ctx context.Context, req *domain.InvoicePageReq) (*domain.PaginationResponse, error) {
+	if err := req.Normalize(); err != nil {
+		return nil, err
+	}
+
 	res, err := s.repo.Get(ctx, req)
 	if err != nil {
 		logger.StdContextError(ctx, "failed to get all invoices", zap.Error(err))
@@ -47,112 +81,376 @@ func (s *invoiceService) Get(ctx context.Context, req *domain.PaginationRequest)
 }
 
 // GetByID retrieves a single invoice with its items by invoice ID
-func (s *invoiceService) GetByID(ctx context.Context, invoiceID int64, userID uint) (*domain.InvoiceResponse, error) {
-	invoice, err := s.repo.GetByID(ctx, invoiceID)
+func (s *invoiceService) GetByID(ctx context.Context, invoiceID string, userID uint) (*domain.InvoiceResponse, error) {
+	invoice, err := s.repo.GetByPublicID(ctx, userID, invoiceID)
 	if err != nil {
-		logger.StdContextError(ctx, "failed to get invoice by ID", zap.Error(err), zap.Int64("invoice_id", invoiceID))
+		logger.StdContextError(ctx, "failed to get invoice by ID", zap.Error(err), zap.String("invoice_id", invoiceID))
 		return nil, err
 	}
 
-	// Verify invoice belongs to user
-	if invoice.AuthorID != userID {
-		logger.StdContextWarn(ctx, "unauthorized invoice access", zap.Int64("invoice_id", invoiceID), zap.Uint("user_id", userID))
-		return nil, fmt.Errorf(domain.ErrNotFoundInvoice)
-	}
+	return s.detail(ctx, invoice)
+}
 
-	// Fetch invoice items
-	items, err := s.repo.GetItemsByInvoiceID(ctx, invoiceID)
+// detail builds the full invoice response including items and tax breakdown
+func (s *invoiceService) detail(ctx context.Context, invoice *domain.Invoice) (*domain.InvoiceResponse, error) {
+	items, err := s.repo.GetItemsByInvoiceID(ctx, invoice.AuthorID, invoice.ID)
 	if err != nil {
-		logger.StdContextError(ctx, "failed to get invoice items", zap.Error(err), zap.Int64("invoice_id", invoiceID))
+		logger.StdContextError(ctx, "failed to get invoice items", zap.Error(err), zap.String("invoice_id", invoice.PublicID))
 		return nil, err
 	}
 
-	response := invoice.Response(items)
+	taxes, err := s.repo.GetTaxesByInvoiceID(ctx, invoice.AuthorID, invoice.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get invoice taxes", zap.Error(err), zap.String("invoice_id", invoice.PublicID))
+		return nil, err
+	}
 
+	response := invoice.Response(items, taxes)
 	return &response, nil
 }
 
-func (s *invoiceService) Create(ctx context.Context, req *domain.InvoiceRequest) error {
+// Create adds a draft invoice and returns its public ID
+func (s *invoiceService) Create(ctx context.Context, req *domain.InvoiceRequest) (string, error) {
 	tx, err := s.tx.Begin()
 	if err != nil {
 		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
-		return err
+		return "", err
 	}
 
+	// Ensure rollback on error, commit will override this
+	defer tx.Rollback()
+
+	invoiceID, err := s.CreateTx(ctx, tx, req)
+	if err != nil {
+		return "", err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return "", err
+	}
+
+	logger.StdContextInfo(ctx, "invoice created successfully", zap.String("invoice_id", invoiceID))
+	return invoiceID, nil
+}
+
+// CreateTx creates an invoice inside the transaction of the caller, it is only there once the caller commits
+func (s *invoiceService) CreateTx(ctx context.Context, tx portRepository.Transaction, req *domain.InvoiceRequest) (string, error) {
 	issueDate, err := time.ParseInLocation("2006-01-02", req.IssueDate, time.Local)
 	if err != nil {
 		logger.StdContextError(ctx, "failed to parse issue date", zap.Error(err))
-		return err
+		return "", err
 	}
 
-	dueDate, err := time.ParseInLocation("2006-01-02 15:04:05", req.DueDate, time.Local)
-	if err != nil {
-		logger.StdContextError(ctx, "failed to parse due date", zap.Error(err))
-		return err
+	// A recurring run is invoiced once, even when the scheduler retries it after a restart
+	if req.RecurringID != nil {
+		exists, err := s.repo.HasRecurringRun(ctx, tx, *req.RecurringID, req.RecurringRunDate)
+		if err != nil {
+			logger.StdContextError(ctx, "failed to check recurring run", zap.Error(err), zap.Uint("recurring_id", *req.RecurringID))
+			return "", err
+		}
+		if exists {
+			return "", fmt.Errorf(domain.ErrRecurringRunExists)
+		}
 	}
 
-	// Ensure rollback on error, commit will override this
-	defer tx.Rollback()
+	// An import creates one invoice per reference, uploading the same file again skips it
+	if req.ImportRef != "" {
+		exists, err := s.repo.HasImportRef(ctx, tx, req.UserID, req.ImportRef)
+		if err != nil {
+			logger.StdContextError(ctx, "failed to check import reference", zap.Error(err), zap.String("import_ref", req.ImportRef))
+			return "", err
+		}
+		if exists {
+			return "", fmt.Errorf(domain.ErrInvoiceAlreadyImported)
+		}
+	}
 
 	// Generate invoice ID
 	invoiceID, err := s.repo.GenerateInvoiceID(ctx, tx, req.UserID, issueDate)
 	if err != nil {
 		logger.StdContextError(ctx, "failed to generate invoice ID", zap.Error(err))
-		return err
+		return "", err
+	}
+
+	publicID, err := uuid.NewV7()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to generate invoice public ID", zap.Error(err))
+		return "", err
 	}
 
 	t := time.Now()
+	items, totals, err := priceItems(ctx, s.taxRepo, invoiceID, req, t)
+	if err != nil {
+		return "", err
+	}
 
 	data := domain.Invoice{
-		ID:        invoiceID,
-		Issuer:    req.Issuer,
-		Customer:  req.Customer,
-		IssueDate: issueDate.Format(time.DateOnly),
-		DueDate:   dueDate,
-		Note:      req.Note,
-		AuthorID:  req.UserID,
-		Status:    "unpaid",
-		Timestamp: domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+		ID:             invoiceID,
+		PublicID:       publicID.String(),
+		Customer:       req.Customer,
+		IssueDate:      issueDate.Format(time.DateOnly),
+		Note:           req.Note,
+		AuthorID:       req.UserID,
+		Status:         domain.InvoiceStatusDraft,
+		TaxMode:        taxMode(req.TaxMode),
+		TaxRateID:      req.TaxRateID,
+		DiscountType:   req.DiscountType,
+		Discount:       req.Discount,
+		Subtotal:       totals.Subtotal,
+		DiscountAmount: totals.DiscountAmount,
+		DiscountTotal:  totals.DiscountTotal,
+		TaxTotal:       totals.TaxTotal,
+		Total:          totals.Total,
+		Version:        1,
+		Timestamp:      domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+	if req.RecurringID != nil {
+		data.RecurringID = req.RecurringID
+		data.RecurringRunDate = req.RecurringRunDate
+	}
+	data.ImportRef = req.ImportRef
+
+	if err = s.applyProfile(ctx, req, issueDate, &data); err != nil {
+		return "", err
+	}
+
+	if err = s.applyCustomer(ctx, req, nil, &data); err != nil {
+		return "", err
+	}
+
+	if err = s.convert(ctx, req, nil, issueDate, &data); err != nil {
+		return "", err
 	}
 
 	// Create invoice
 	if err = s.repo.Create(ctx, tx, &data); err != nil {
 		logger.StdContextError(ctx, "failed to create invoice", zap.Error(err))
-		return err
+		return "", err
 	}
 
 	// Create invoice items
-	items := make([]domain.InvoiceItem, 0, len(req.Items))
-	for i, v := range req.Items {
-		total := v.Qty * v.Price
-		item := domain.InvoiceItem{
-			ID:          uint(i + 1),
-			InvoiceID:   invoiceID,
-			Description: v.Description,
-			Qty:         v.Qty,
-			Price:       v.Price,
-			Total:       total,
-			Timestamp:   domain.Timestamp{CreatedAt: t, UpdatedAt: t},
-		}
-		items = append(items, item)
+	if err = s.repo.CreateItem(ctx, tx, items); err != nil {
+		logger.StdContextError(ctx, "failed to create invoice items", zap.Error(err))
+		return "", err
+	}
+
+	if err = s.repo.CreateTaxes(ctx, tx, totals.Taxes); err != nil {
+		logger.StdContextError(ctx, "failed to create invoice taxes", zap.Error(err))
+		return "", err
+	}
+
+	if err = s.addRevision(ctx, tx, &data, items, totals.Taxes, req.UserID, domain.RevisionCreated); err != nil {
+		return "", err
+	}
+
+	return data.PublicID, nil
+}
+
+// Check runs the lookups of Create without writing anything: tax rates, business profile, customer and currency.
+// An import checks every invoice of a file with it, so a dry run reports the invoices that would fail.
+func (s *invoiceService) Check(ctx context.Context, req *domain.InvoiceRequest) error {
+	issueDate, err := time.ParseInLocation("2006-01-02", req.IssueDate, time.Local)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to parse issue date", zap.Error(err))
+		return err
+	}
+
+	_, totals, err := priceItems(ctx, s.taxRepo, 0, req, time.Now())
+	if err != nil {
+		return err
+	}
+
+	data := domain.Invoice{AuthorID: req.UserID, Total: totals.Total}
+	if err = s.applyProfile(ctx, req, issueDate, &data); err != nil {
+		return err
+	}
+	if err = s.applyCustomer(ctx, req, nil, &data); err != nil {
+		return err
+	}
+	return s.convert(ctx, req, nil, issueDate, &data)
+}
+
+func (s *invoiceService) Update(ctx context.Context, req *domain.InvoiceRequest) (int, error) {
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if req.ID == "" {
+		return 0, fmt.Errorf(domain.ErrInvoiceIDRequired)
+	}
+
+	// Ensure invoice exists, belongs to user and is still editable
+	inv, err := s.repo.LockByPublicID(ctx, tx, req.UserID, req.ID)
+	if err != nil {
+		return 0, err
+	}
+	// The version is checked under the lock, so two editors can not both pass it
+	if req.Version != 0 && req.Version != inv.Version {
+		logger.StdContextWarn(ctx, "attempt to update stale invoice version", zap.String("invoice_id", req.ID), zap.Int("version", req.Version), zap.Int("current", inv.Version))
+		return 0, fmt.Errorf(domain.ErrInvoiceVersionMismatch)
+	}
+	if inv.Status.IsLocked() {
+		logger.StdContextWarn(ctx, "attempt to edit locked invoice", zap.String("invoice_id", req.ID), zap.String("status", string(inv.Status)))
+		return 0, fmt.Errorf(domain.ErrInvoiceLocked)
+	}
+
+	// Credit note lines refer to the items of the invoice they credit
+	if err = s.checkCreditNotes(ctx, tx, inv); err != nil {
+		return 0, err
+	}
+
+	issueDate, err := time.ParseInLocation(time.DateOnly, req.IssueDate, time.Local)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to parse issue date", zap.Error(err))
+		return 0, err
+	}
+
+	updatedAt := time.Now()
+	items, totals, err := priceItems(ctx, s.taxRepo, inv.ID, req, updatedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	// Payments and credit already received can not exceed the new total
+	if totals.Total < inv.Settled() {
+		return 0, fmt.Errorf(domain.ErrInvoiceTotalBelowPaid)
+	}
+
+	data := domain.Invoice{
+		ID:             inv.ID,
+		PublicID:       inv.PublicID,
+		Customer:       req.Customer,
+		IssueDate:      issueDate.Format("2006-01-02"),
+		Note:           req.Note,
+		AuthorID:       req.UserID,
+		Status:         inv.Status,
+		TaxMode:        taxMode(req.TaxMode),
+		TaxRateID:      req.TaxRateID,
+		DiscountType:   req.DiscountType,
+		Discount:       req.Discount,
+		Subtotal:       totals.Subtotal,
+		DiscountAmount: totals.DiscountAmount,
+		DiscountTotal:  totals.DiscountTotal,
+		TaxTotal:       totals.TaxTotal,
+		Total:          totals.Total,
+		AmountPaid:     inv.AmountPaid,
+		AmountCredited: inv.AmountCredited,
+		Version:        inv.Version + 1,
+		Timestamp:      domain.Timestamp{UpdatedAt: updatedAt},
+	}
+
+	if err = s.applyProfile(ctx, req, issueDate, &data); err != nil {
+		return 0, err
+	}
+
+	if err = s.applyCustomer(ctx, req, inv, &data); err != nil {
+		return 0, err
+	}
+
+	if err = s.convert(ctx, req, inv, issueDate, &data); err != nil {
+		return 0, err
+	}
+
+	// A new total or due date may change how much of an issued invoice is settled
+	if inv.Status != domain.InvoiceStatusDraft {
+		data.Status = data.SettledStatus(updatedAt)
+	}
+
+	if err = s.repo.Update(ctx, tx, &data); err != nil {
+		logger.StdContextError(ctx, "failed to update invoice", zap.Error(err))
+		return 0, err
+	}
+
+	if err = s.repo.DeleteItemsByInvoiceID(ctx, tx, inv.AuthorID, inv.ID); err != nil {
+		logger.StdContextError(ctx, "failed to delete invoice items", zap.Error(err))
+		return 0, err
+	}
+
+	if err = s.repo.DeleteTaxesByInvoiceID(ctx, tx, inv.AuthorID, inv.ID); err != nil {
+		logger.StdContextError(ctx, "failed to delete invoice taxes", zap.Error(err))
+		return 0, err
 	}
 
 	if err = s.repo.CreateItem(ctx, tx, items); err != nil {
 		logger.StdContextError(ctx, "failed to create invoice items", zap.Error(err))
+		return 0, err
+	}
+
+	if err = s.repo.CreateTaxes(ctx, tx, totals.Taxes); err != nil {
+		logger.StdContextError(ctx, "failed to create invoice taxes", zap.Error(err))
+		return 0, err
+	}
+
+	// Columns the update leaves alone are read back so the revision holds the whole invoice
+	current, err := s.repo.LockByPublicID(ctx, tx, req.UserID, req.ID)
+	if err != nil {
+		return 0, err
+	}
+	if err = s.addRevision(ctx, tx, current, items, totals.Taxes, req.UserID, domain.RevisionUpdated); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return 0, err
+	}
+
+	logger.StdContextInfo(ctx, "invoice updated successfully", zap.String("invoice_id", req.ID))
+	return data.Version, nil
+}
+
+func (s *invoiceService) GetDeleted(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
+	res, err := s.repo.GetDeleted(ctx, req)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get deleted invoices", zap.Error(err))
+		return nil, err
+	}
+	return res, nil
+}
+
+// Delete moves a draft or void invoice to the trash
+func (s *invoiceService) Delete(ctx context.Context, invoiceID string, userID uint) error {
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	inv, err := s.repo.LockByPublicID(ctx, tx, userID, invoiceID)
+	if err != nil {
+		return err
+	}
+	if !inv.Status.IsDeletable() {
+		logger.StdContextWarn(ctx, "attempt to delete issued invoice", zap.String("invoice_id", invoiceID), zap.String("status", string(inv.Status)))
+		return fmt.Errorf(domain.ErrInvoiceNotDeletable)
+	}
+
+	t := time.Now()
+	inv.DeletedAt = &t
+	inv.UpdatedAt = t
+
+	if err = s.repo.SoftDelete(ctx, tx, inv); err != nil {
+		logger.StdContextError(ctx, "failed to delete invoice", zap.Error(err), zap.String("invoice_id", invoiceID))
 		return err
 	}
 
-	// Commit transaction
 	if err = tx.Commit(); err != nil {
 		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
 		return err
 	}
 
-	logger.StdContextInfo(ctx, "invoice created successfully", zap.Int64("invoice_id", invoiceID))
+	logger.StdContextInfo(ctx, "invoice moved to trash", zap.String("invoice_id", invoiceID))
 	return nil
 }
 
-func (s *invoiceService) Update(ctx context.Context, req *domain.InvoiceRequest) error {
+// Restore brings an invoice back from the trash with the status it was deleted in
+func (s *invoiceService) Restore(ctx context.Context, invoiceID string, userID uint) error {
 	tx, err := s.tx.Begin()
 	if err != nil {
 		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
@@ -160,71 +458,185 @@ func (s *invoiceService) Update(ctx context.Context, req *domain.InvoiceRequest)
 	}
 	defer tx.Rollback()
 
-	if req.ID <= 0 {
-		return fmt.Errorf(domain.ErrInvoiceIDRequired)
+	inv, err := s.repo.LockDeletedByPublicID(ctx, tx, userID, invoiceID)
+	if err != nil {
+		return err
 	}
 
-	// Ensure invoice exists and belongs to user
-	inv, err := s.repo.GetByID(ctx, req.ID)
+	inv.UpdatedAt = time.Now()
+	if err = s.repo.Restore(ctx, tx, inv); err != nil {
+		logger.StdContextError(ctx, "failed to restore invoice", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "invoice restored from trash", zap.String("invoice_id", invoiceID))
+	return nil
+}
+
+// Purge permanently removes an invoice that is already in the trash
+func (s *invoiceService) Purge(ctx context.Context, invoiceID string, userID uint) error {
+	tx, err := s.tx.Begin()
 	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
 		return err
 	}
-	if inv.AuthorID != req.UserID {
-		return fmt.Errorf(domain.ErrNotFoundInvoice)
+	defer tx.Rollback()
+
+	inv, err := s.repo.LockDeletedByPublicID(ctx, tx, userID, invoiceID)
+	if err != nil {
+		return err
 	}
 
-	issueDate, err := time.ParseInLocation(time.DateOnly, req.IssueDate, time.Local)
+	attachments, err := s.attachRepo.GetByInvoiceID(ctx, userID, inv.ID)
 	if err != nil {
-		logger.StdContextError(ctx, "failed to parse issue date", zap.Error(err))
+		logger.StdContextError(ctx, "failed to get invoice attachments", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return err
+	}
+
+	if err = s.repo.Purge(ctx, tx, inv); err != nil {
+		logger.StdContextError(ctx, "failed to purge invoice", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
 		return err
 	}
 
-	dueDate, err := time.ParseInLocation("2006-01-02 15:04:05", req.DueDate, time.Local)
+	removeDocuments(ctx, s.documents, domain.DocumentInvoice, invoiceID)
+
+	// The rows are gone, a file that fails to delete is only left behind
+	for _, v := range attachments {
+		if err = s.blobs.Delete(ctx, v.BlobKey); err != nil {
+			logger.StdContextWarn(ctx, "failed to delete attachment file", zap.Error(err), zap.String("blob_key", v.BlobKey))
+		}
+	}
+
+	logger.StdContextInfo(ctx, "invoice purged", zap.String("invoice_id", invoiceID))
+	return nil
+}
+
+// Send issues a draft invoice to the customer
+func (s *invoiceService) Send(ctx context.Context, invoiceID string, userID uint) error {
+	return s.transition(ctx, invoiceID, userID, domain.InvoiceStatusSent)
+}
+
+// Void cancels an invoice so it no longer counts as receivable
+func (s *invoiceService) Void(ctx context.Context, invoiceID string, userID uint) error {
+	return s.transition(ctx, invoiceID, userID, domain.InvoiceStatusVoid)
+}
+
+// MarkPaid settles the outstanding balance of an invoice.
+// The balance is recorded as a payment so the ledger always explains the status.
+func (s *invoiceService) MarkPaid(ctx context.Context, invoiceID string, userID uint) error {
+	inv, err := s.repo.GetByPublicID(ctx, userID, invoiceID)
 	if err != nil {
-		logger.StdContextError(ctx, "failed to parse due date", zap.Error(err))
 		return err
 	}
 
-	updatedAt := time.Now()
+	// Nothing to record for an invoice without balance
+	if inv.Balance() <= 0 {
+		return s.transition(ctx, invoiceID, userID, domain.InvoiceStatusPaid)
+	}
 
-	data := domain.Invoice{
-		ID:        req.ID,
-		Issuer:    req.Issuer,
-		Customer:  req.Customer,
-		IssueDate: issueDate.Format("2006-01-02"),
-		DueDate:   dueDate,
-		Note:      req.Note,
-		AuthorID:  req.UserID,
-		Timestamp: domain.Timestamp{UpdatedAt: updatedAt},
+	if !inv.Status.AcceptsPayment() {
+		return fmt.Errorf(domain.ErrInvalidInvoiceTransition)
 	}
 
-	if err = s.repo.Update(ctx, tx, &data); err != nil {
-		logger.StdContextError(ctx, "failed to update invoice", zap.Error(err))
+	_, err = s.payment.Create(ctx, &domain.PaymentRequest{
+		Amount:    inv.Balance(),
+		PaidAt:    time.Now().Format(time.DateOnly),
+		Method:    domain.PaymentMethodOther,
+		Note:      "settled by mark as paid",
+		InvoiceID: invoiceID,
+		UserID:    userID,
+	})
+	return err
+}
+
+// transition moves an invoice to the next status when the lifecycle allows it
+func (s *invoiceService) transition(ctx context.Context, invoiceID string, userID uint, next domain.InvoiceStatus) error {
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
 		return err
 	}
+	defer tx.Rollback()
 
-	if err = s.repo.DeleteItemsByInvoiceID(ctx, tx, req.ID); err != nil {
-		logger.StdContextError(ctx, "failed to delete invoice items", zap.Error(err))
+	inv, err := s.repo.LockByPublicID(ctx, tx, userID, invoiceID)
+	if err != nil {
 		return err
 	}
 
-	items := make([]domain.InvoiceItem, 0, len(req.Items))
-	for i, v := range req.Items {
-		total := v.Qty * v.Price
-		item := domain.InvoiceItem{
-			ID:          uint(i + 1),
-			InvoiceID:   req.ID,
-			Description: v.Description,
-			Qty:         v.Qty,
-			Price:       v.Price,
-			Total:       total,
-			Timestamp:   domain.Timestamp{CreatedAt: updatedAt, UpdatedAt: updatedAt},
+	if next == domain.InvoiceStatusVoid {
+		if inv.AmountPaid > 0 {
+			return fmt.Errorf(domain.ErrInvoiceHasPayments)
+		}
+		if inv.AmountCredited > 0 {
+			return fmt.Errorf(domain.ErrInvoiceHasCreditNotes)
+		}
+		if err = s.checkCreditNotes(ctx, tx, inv); err != nil {
+			return err
 		}
-		items = append(items, item)
 	}
 
-	if err = s.repo.CreateItem(ctx, tx, items); err != nil {
-		logger.StdContextError(ctx, "failed to create invoice items", zap.Error(err))
+	if !inv.Status.CanTransitionTo(next) {
+		logger.StdContextWarn(ctx, "invalid invoice status transition",
+			zap.String("invoice_id", invoiceID),
+			zap.String("from", string(inv.Status)),
+			zap.String("to", string(next)),
+		)
+		return fmt.Errorf(domain.ErrInvalidInvoiceTransition)
+	}
+
+	// Customer details are captured as they are when the invoice is issued
+	if next == domain.InvoiceStatusSent && inv.CustomerID != nil {
+		customer, err := s.customerRepo.GetByID(ctx, userID, *inv.CustomerID)
+		if err == nil {
+			inv.SnapshotCustomer(customer)
+			if err = s.repo.UpdateCustomer(ctx, tx, inv); err != nil {
+				logger.StdContextError(ctx, "failed to snapshot invoice customer", zap.Error(err), zap.String("invoice_id", invoiceID))
+				return err
+			}
+		} else {
+			logger.StdContextWarn(ctx, "keeping previous customer snapshot", zap.Error(err), zap.String("invoice_id", invoiceID))
+		}
+	}
+
+	// Numbers are taken when an invoice is issued, drafts never hold one so deleted drafts leave no gap
+	if next == domain.InvoiceStatusSent && inv.Number == "" {
+		if err = s.assignNumber(ctx, tx, inv); err != nil {
+			return err
+		}
+	}
+
+	prev := inv.Status
+	inv.Status = next
+	inv.Version++
+	inv.UpdatedAt = time.Now()
+
+	if err = s.repo.UpdateStatus(ctx, tx, inv); err != nil {
+		logger.StdContextError(ctx, "failed to update invoice status", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return err
+	}
+
+	// Items do not change with the status, the locked invoice keeps them from being edited meanwhile
+	items, err := s.repo.GetItemsByInvoiceID(ctx, inv.AuthorID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get invoice items", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return err
+	}
+	taxes, err := s.repo.GetTaxesByInvoiceID(ctx, inv.AuthorID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get invoice taxes", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return err
+	}
+	if err = s.addRevision(ctx, tx, inv, items, taxes, userID, domain.RevisionEvent(next)); err != nil {
 		return err
 	}
 
@@ -233,127 +645,416 @@ func (s *invoiceService) Update(ctx context.Context, req *domain.InvoiceRequest)
 		return err
 	}
 
-	//remove pdf file if exists, so it will be regenerated on next request
-	filePdf := fmt.Sprintf("assets/pdf/invoice_%d.pdf", req.ID)
-	if _, err := os.Stat(filePdf); err == nil {
-		err = os.Remove(filePdf)
-		if err != nil {
-			logger.StdContextError(ctx, "failed to remove existing pdf file", zap.Error(err), zap.Int64("invoice_id", req.ID))
-		} else {
-			logger.StdContextInfo(ctx, "existing pdf file removed", zap.Int64("invoice_id", req.ID))
-		}
+	logger.StdContextInfo(ctx, "invoice status changed",
+		zap.String("invoice_id", invoiceID),
+		zap.String("from", string(prev)),
+		zap.String("to", string(next)),
+	)
+	return nil
+}
+
+// addRevision keeps a snapshot of an invoice as it is after a change, it is written in the same transaction
+func (s *invoiceService) addRevision(ctx context.Context, tx portRepository.Transaction, inv *domain.Invoice, items []domain.InvoiceItem, taxes []domain.InvoiceTax, actorID uint, event domain.RevisionEvent) error {
+	revision, err := domain.NewInvoiceRevision(inv, items, taxes, actorID, event, time.Now())
+	if err != nil {
+		logger.StdContextError(ctx, "failed to build invoice revision", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return err
+	}
+	if err = s.revisionRepo.Create(ctx, tx, &revision); err != nil {
+		logger.StdContextError(ctx, "failed to create invoice revision", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return err
+	}
+	return nil
+}
+
+// checkCreditNotes rejects changes to an invoice that credit notes were issued against
+func (s *invoiceService) checkCreditNotes(ctx context.Context, tx portRepository.Transaction, inv *domain.Invoice) error {
+	credited, err := s.repo.HasCreditNotes(ctx, tx, inv.AuthorID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to check invoice credit notes", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return err
+	}
+	if credited {
+		return fmt.Errorf(domain.ErrInvoiceHasCreditNotes)
+	}
+	return nil
+}
+
+// assignNumber gives an invoice the next number of its issue date period
+func (s *invoiceService) assignNumber(ctx context.Context, tx portRepository.Transaction, inv *domain.Invoice) error {
+	issueDate, err := time.Parse(time.DateOnly, inv.IssueDate)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to parse issue date", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return err
+	}
+
+	number, err := nextNumber(ctx, s.numberingRepo, tx, inv.AuthorID, domain.DocumentInvoice, issueDate)
+	if err != nil {
+		return err
+	}
+
+	// A template changed mid period can render a number issued before, the sequence is rolled back with tx
+	exists, err := s.repo.NumberExists(ctx, tx, inv.AuthorID, number)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to check invoice number", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return err
+	}
+	if exists {
+		return fmt.Errorf(domain.ErrInvoiceNumberExists)
 	}
 
-	logger.StdContextInfo(ctx, "invoice updated successfully", zap.Int64("invoice_id", req.ID))
+	inv.Number = number
+	inv.UpdatedAt = time.Now()
+	if err = s.repo.AssignNumber(ctx, tx, inv); err != nil {
+		logger.StdContextError(ctx, "failed to assign invoice number", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return err
+	}
 	return nil
 }
 
-func (s *invoiceService) GetPDF(ctx context.Context, invoiceID int64, userID uint) ([]byte, error) {
+func (s *invoiceService) GetPDF(ctx context.Context, invoiceID string, userID uint) ([]byte, error) {
 	// Ensure invoice exists and belongs to user
-	data, err := s.repo.GetByID(ctx, invoiceID)
+	data, err := s.repo.GetByPublicID(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail, err := s.detail(ctx, data)
 	if err != nil {
 		return nil, err
 	}
-	if data.AuthorID != userID {
-		return nil, fmt.Errorf(domain.ErrNotFoundInvoice)
+
+	settings, err := loadPDFSettings(ctx, s.userRepo, data.AuthorID)
+	if err != nil {
+		return nil, err
 	}
 
-	filePdf := fmt.Sprintf("assets/pdf/invoice_%d.pdf", invoiceID)
+	issuer, err := loadPDFIssuer(ctx, s.profileRepo, s.blobs, data.AuthorID, data.ProfileID)
+	if err != nil {
+		return nil, err
+	}
 
-	// Check if PDF already exists
-	if _, err := os.Stat(filePdf); err == nil {
-		// PDF exists, read it and return
-		pdfBytes, err := os.ReadFile(filePdf)
-		if err != nil {
-			logger.StdContextError(ctx, "failed to read existing pdf", zap.Error(err), zap.Int64("invoice_id", invoiceID))
-			return nil, err
-		}
-		return pdfBytes, nil
+	attachments, err := s.pdfAttachments(ctx, data)
+	if err != nil {
+		return nil, err
 	}
 
-	// PDF doesn't exist, generate it
-	// Fetch invoice items
-	dataItems, err := s.repo.GetItemsByInvoiceID(ctx, invoiceID)
+	// The cached PDF is named after everything printed on it, a changed invoice is never served stale
+	key, err := domain.DocumentKey(domain.DocumentInvoice, invoiceID, pdfLayoutVersion, settings, detail, issuer.content(), attachments)
 	if err != nil {
-		logger.StdContextError(ctx, "failed to get invoice items", zap.Error(err), zap.Int64("invoice_id", invoiceID))
+		logger.StdContextError(ctx, "failed to build pdf key", zap.Error(err), zap.String("invoice_id", invoiceID))
 		return nil, err
 	}
+	if pdfBytes, ok := cachedPDF(ctx, s.documents, key); ok {
+		return pdfBytes, nil
+	}
 
-	m := s.generatePDF(data.Response(dataItems))
+	m := s.generatePDF(*detail, issuer, settings)
 	doc, err := m.Generate()
 	if err != nil {
-		logger.StdContextError(ctx, "failed to generate pdf", zap.Error(err), zap.Int64("invoice_id", invoiceID))
+		logger.StdContextError(ctx, "failed to generate pdf", zap.Error(err), zap.String("invoice_id", invoiceID))
 		return nil, err
 	}
 
-	// Get PDF as binary data
+	complete := s.appendAttachments(ctx, doc, attachments)
+
+	// Get PDF as binary data, one missing an attachment is not cached so the next request tries again
 	pdfBytes := doc.GetBytes()
+	if complete {
+		storePDF(ctx, s.documents, key, pdfBytes)
+	}
 
-	// Save pdf to file for future use
-	err = doc.Save(filePdf)
+	logger.StdContextInfo(ctx, "pdf generated successfully", zap.String("invoice_id", invoiceID), zap.Int("size_bytes", len(pdfBytes)))
+	return pdfBytes, nil
+}
+
+// pdfAttachments returns the attachments of an invoice that are appended to its PDF
+func (s *invoiceService) pdfAttachments(ctx context.Context, inv *domain.Invoice) ([]domain.InvoiceAttachment, error) {
+	attachments, err := s.attachRepo.GetByInvoiceID(ctx, inv.AuthorID, inv.ID)
 	if err != nil {
-		logger.StdContextError(ctx, "failed to save pdf to file", zap.Error(err), zap.Int64("invoice_id", invoiceID))
+		logger.StdContextError(ctx, "failed to get invoice attachments", zap.Error(err), zap.String("invoice_id", inv.PublicID))
 		return nil, err
 	}
 
-	logger.StdContextInfo(ctx, "pdf generated successfully", zap.Int64("invoice_id", invoiceID), zap.Int("size_bytes", len(pdfBytes)))
-	return pdfBytes, nil
+	res := make([]domain.InvoiceAttachment, 0, len(attachments))
+	for _, v := range attachments {
+		if v.AppendToPDF {
+			res = append(res, v)
+		}
+	}
+	return res, nil
 }
 
-func (s *invoiceService) generatePDF(data domain.InvoiceResponse) core.Maroto {
-	cfg := cfgPdf.NewBuilder().
-		WithPageSize(pagesize.A4).
-		WithDebug(s.cfg.Env == "development").
-		Build()
-
-	m := maroto.New(cfg)
-
-	m.AddAutoRow(
-		text.NewCol(8, ""),
-		text.NewCol(4, fmt.Sprintf("%d", data.ID), props.Text{
-			Size:  12,
-			Style: fontstyle.Bold,
-			Align: align.Right,
-		}),
-	)
+// appendAttachments adds the pages of the pdf attachments marked for it after the invoice and reports whether all were added.
+// A file that can not be read or merged is left out rather than failing the invoice PDF.
+func (s *invoiceService) appendAttachments(ctx context.Context, doc core.Document, attachments []domain.InvoiceAttachment) bool {
+	complete := true
+	for _, v := range attachments {
+		content, err := s.blobs.Get(ctx, v.BlobKey)
+		if err != nil {
+			logger.StdContextWarn(ctx, "failed to read attachment file", zap.Error(err), zap.Uint("attachment_id", v.ID))
+			complete = false
+			continue
+		}
+		if err = doc.Merge(content); err != nil {
+			logger.StdContextWarn(ctx, "failed to append attachment to pdf", zap.Error(err), zap.Uint("attachment_id", v.ID))
+			complete = false
+		}
+	}
+	return complete
+}
 
-	m.AddAutoRow(
-		text.NewCol(12, "INVOICE", props.Text{
-			Size:  30,
-			Style: fontstyle.Bold,
-		}),
-	)
+// priceItems converts requested items into invoice items with their tax snapshot
+// and calculates subtotal, tax and grand total of the invoice.
+func priceItems(ctx context.Context, taxRepo portRepository.TaxRateRepository, invoiceID int64, req *domain.InvoiceRequest, t time.Time) ([]domain.InvoiceItem, domain.InvoiceTotals, error) {
+	// Collect every tax rate referenced by the invoice or its items
+	var ids []uint
+	if req.TaxRateID != nil {
+		ids = append(ids, *req.TaxRateID)
+	}
+	for _, v := range req.Items {
+		if v.TaxRateID != nil {
+			ids = append(ids, *v.TaxRateID)
+		}
+	}
 
-	m.AddAutoRow(
-		text.NewCol(12, data.IssueDate, props.Text{
-			Size: 12,
-		}),
-	)
+	rates, err := taxRepo.GetByIDs(ctx, req.UserID, ids)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get tax rates", zap.Error(err))
+		return nil, domain.InvoiceTotals{}, err
+	}
+	rateByID := make(map[uint]domain.TaxRate, len(rates))
+	for _, v := range rates {
+		rateByID[v.ID] = v
+	}
 
-	m.AddAutoRow(text.NewCol(6, "Kepada"))
-	m.AddAutoRow(text.NewCol(6, data.Customer), text.NewCol(6, data.Issuer))
+	items := make([]domain.InvoiceItem, 0, len(req.Items))
+	for i, v := range req.Items {
+		item := domain.InvoiceItem{
+			ID:           uint(i + 1),
+			InvoiceID:    invoiceID,
+			AuthorID:     req.UserID,
+			Description:  v.Description,
+			Qty:          v.Qty,
+			Price:        v.Price,
+			DiscountType: v.DiscountType,
+			Discount:     v.Discount,
+			Timestamp:    domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+		}
 
-	m.AddAutoRow(text.NewCol(12, ""))
+		// A fixed discount can not exceed the line amount, negative lines are not allowed
+		if !v.DiscountType.Valid(v.Discount, v.Qty*v.Price) {
+			return nil, domain.InvoiceTotals{}, fmt.Errorf(domain.ErrInvalidDiscount)
+		}
 
-	// Add table header
-	m.AddAutoRow(
-		text.NewCol(2, "No", props.Text{Size: 11, Style: fontstyle.Bold, Align: align.Center}),
-		text.NewCol(3, "Item", props.Text{Size: 11, Style: fontstyle.Bold, Align: align.Center}),
-		text.NewCol(2, "Qty", props.Text{Size: 11, Style: fontstyle.Bold, Align: align.Center}),
-		text.NewCol(2, "Price", props.Text{Size: 11, Style: fontstyle.Bold, Align: align.Center}),
-		text.NewCol(3, "Total", props.Text{Size: 11, Style: fontstyle.Bold, Align: align.Center}),
-	)
+		// Item level tax overrides the invoice default
+		taxRateID := req.TaxRateID
+		if v.TaxRateID != nil {
+			taxRateID = v.TaxRateID
+		}
+		if taxRateID != nil {
+			rate, ok := rateByID[*taxRateID]
+			if !ok {
+				logger.StdContextWarn(ctx, "unknown or inactive tax rate", zap.Uint("tax_rate_id", *taxRateID))
+				return nil, domain.InvoiceTotals{}, fmt.Errorf(domain.ErrInvalidTaxRate)
+			}
+			item.TaxRateID = &rate.ID
+			item.TaxName = rate.Name
+			item.TaxRate = rate.Rate
+		}
 
-	// Add table rows for items
-	for i, item := range data.Items {
-		m.AddAutoRow(
-			text.NewCol(2, fmt.Sprintf("%d", i+1), props.Text{Align: align.Center}),
-			text.NewCol(3, item.Description),
-			text.NewCol(2, fmt.Sprintf("%d", item.Qty), props.Text{Align: align.Center}),
-			text.NewCol(2, fmt.Sprintf("%d", item.Price), props.Text{Align: align.Right}),
-			text.NewCol(3, fmt.Sprintf("%d", item.Total), props.Text{Align: align.Right}),
-		)
+		items = append(items, item)
+	}
+
+	totals := domain.CalculateInvoice(taxMode(req.TaxMode), req.DiscountType, req.Discount, items)
+	if !req.DiscountType.Valid(req.Discount, totals.Subtotal) {
+		return nil, domain.InvoiceTotals{}, fmt.Errorf(domain.ErrInvalidDiscount)
+	}
+	for i := range totals.Taxes {
+		totals.Taxes[i].InvoiceID = invoiceID
+		totals.Taxes[i].AuthorID = req.UserID
+	}
+
+	return items, totals, nil
+}
+
+// applyProfile links the requested business profile, or the default one of the user, to the invoice.
+// The profile name fills in an empty issuer and its payment terms fill in a missing due date.
+func (s *invoiceService) applyProfile(ctx context.Context, req *domain.InvoiceRequest, issueDate time.Time, data *domain.Invoice) error {
+	var profile *domain.BusinessProfile
+	var err error
+	if req.ProfileID != nil {
+		profile, err = s.profileRepo.GetByID(ctx, req.UserID, *req.ProfileID)
+	} else {
+		profile, err = s.profileRepo.GetDefault(ctx, req.UserID)
+	}
+	if err != nil {
+		logger.StdContextWarn(ctx, "failed to get invoice business profile", zap.Error(err))
+		return err
+	}
+
+	data.Issuer = req.Issuer
+	if profile != nil {
+		data.ProfileID = &profile.ID
+		if data.Issuer == "" {
+			data.Issuer = profile.Name
+		}
+	}
+	if data.Issuer == "" {
+		return fmt.Errorf(domain.ErrIssuerRequired)
+	}
+
+	if req.DueDate != "" {
+		data.DueDate, err = time.ParseInLocation("2006-01-02 15:04:05", req.DueDate, time.Local)
+		if err != nil {
+			logger.StdContextError(ctx, "failed to parse due date", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+
+	if profile == nil {
+		return fmt.Errorf(domain.ErrDueDateRequired)
+	}
+	data.DueDate = profile.DueDate(issueDate)
+	return nil
+}
+
+// applyCustomer snapshots the linked customer onto the invoice or keeps the free text customer.
+// An issued invoice keeps its snapshot while it stays linked to the same customer.
+func (s *invoiceService) applyCustomer(ctx context.Context, req *domain.InvoiceRequest, current *domain.Invoice, data *domain.Invoice) error {
+	if req.CustomerID == nil {
+		data.Customer = req.Customer
+		return nil
+	}
+
+	if current != nil && current.Status != domain.InvoiceStatusDraft &&
+		current.CustomerID != nil && *current.CustomerID == *req.CustomerID {
+		data.SnapshotCustomer(&domain.Customer{
+			ID:      *current.CustomerID,
+			Name:    current.Customer,
+			Company: current.CustomerCompany,
+			Email:   current.CustomerEmail,
+			Phone:   current.CustomerPhone,
+			Address: current.CustomerAddress,
+			TaxID:   current.CustomerTaxID,
+		})
+		return nil
+	}
+
+	customer, err := s.customerRepo.GetByID(ctx, req.UserID, *req.CustomerID)
+	if err != nil {
+		logger.StdContextWarn(ctx, "unknown invoice customer", zap.Error(err), zap.Uint("customer_id", *req.CustomerID))
+		return err
+	}
+	data.SnapshotCustomer(customer)
+	return nil
+}
+
+// convert sets the invoice currency and converts its total into the account base currency.
+// An existing invoice keeps its stored rate unless the currency changes or a rate is given.
+func (s *invoiceService) convert(ctx context.Context, req *domain.InvoiceRequest, current *domain.Invoice, issueDate time.Time, data *domain.Invoice) error {
+	code := req.Currency
+	if code == "" && current != nil {
+		code = current.Currency
+	}
+
+	user, err := s.userRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get user", zap.Error(err), zap.Uint("user_id", req.UserID))
+		return err
+	}
+	if code == "" {
+		code = user.BaseCurrency
+	}
+
+	currency, ok := domain.LookupCurrency(code)
+	if !ok {
+		return fmt.Errorf(domain.ErrInvalidCurrency)
+	}
+	data.Currency = currency.Code
+
+	if current != nil && current.Settled() > 0 && current.Currency != data.Currency {
+		return fmt.Errorf(domain.ErrInvoiceCurrencyLocked)
+	}
+
+	switch {
+	case req.ExchangeRate != "":
+		data.BaseCurrency = user.BaseCurrency
+		data.ExchangeRate, err = domain.ParseExchangeRate(req.ExchangeRate)
+		if err != nil {
+			return err
+		}
+	case current != nil && current.Currency == data.Currency:
+		data.BaseCurrency = current.BaseCurrency
+		data.ExchangeRate = current.ExchangeRate
+	case data.Currency == user.BaseCurrency:
+		data.BaseCurrency = user.BaseCurrency
+		data.ExchangeRate = domain.ExchangeRateScale
+	default:
+		rate, err := s.rates.GetRate(ctx, req.UserID, data.Currency, user.BaseCurrency, issueDate)
+		if err != nil {
+			logger.StdContextWarn(ctx, "no exchange rate for invoice currency", zap.Error(err), zap.String("currency", data.Currency), zap.String("base_currency", user.BaseCurrency))
+			return err
+		}
+		data.BaseCurrency = user.BaseCurrency
+		data.ExchangeRate = rate.Rate
+	}
+
+	data.BaseTotal = domain.ConvertAmount(data.Total, data.Currency, data.BaseCurrency, data.ExchangeRate)
+	return nil
+}
+
+// taxMode falls back to exclusive pricing when no mode is requested
+func taxMode(mode domain.TaxMode) domain.TaxMode {
+	if mode == "" {
+		return domain.TaxModeExclusive
+	}
+	return mode
+}
+
+func (s *invoiceService) generatePDF(data domain.InvoiceResponse, issuer *pdfIssuer, settings domain.PDFSettings) core.Maroto {
+	d := newPDFDocument(settings, s.cfg.Env == "development")
+
+	// Drafts have no number until they are sent
+	number := data.Number
+	if number == "" {
+		number = d.labels.Draft
+	}
+	var dueDate string
+	if !data.DueDate.IsZero() {
+		dueDate = d.labels.DueDate + ": " + d.locale.FormatLongDate(data.DueDate)
+	}
+	d.header(issuer, d.labels.Invoice, number, d.dateLine(d.labels.IssueDate, data.IssueDate), dueDate)
+	d.parties(issuer, data.Issuer, pdfParty{
+		Name:    data.Customer,
+		Company: data.CustomerCompany,
+		Address: data.CustomerAddress,
+		Email:   data.CustomerEmail,
+		Phone:   data.CustomerPhone,
+		TaxID:   data.CustomerTaxID,
+	})
+
+	d.space()
+
+	d.items(invoicePDFItems(data.Items), data.Currency)
+	d.totals(pdfTotals{
+		Subtotal:       data.Subtotal,
+		DiscountType:   data.DiscountType,
+		Discount:       data.Discount,
+		DiscountAmount: data.DiscountAmount,
+		Taxes:          data.Taxes,
+		TaxMode:        data.TaxMode,
+		Total:          data.Total,
+		Currency:       data.Currency,
+	})
+
+	// Foreign currency invoices also show the stored rate and the converted total
+	if data.BaseCurrency != "" && data.Currency != data.BaseCurrency {
+		d.totalRow(fmt.Sprintf("%s 1 %s", d.labels.Rate, data.Currency), fmt.Sprintf("%s %s", d.locale.FormatDecimal(data.ExchangeRate), data.BaseCurrency), false)
+		d.totalRow(fmt.Sprintf("%s (%s)", d.labels.Total, data.BaseCurrency), d.money(data.BaseTotal, data.BaseCurrency), false)
 	}
 
-	return m
+	d.footer(issuer, true)
+	return d.m
 }
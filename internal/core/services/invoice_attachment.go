@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type invoiceAttachmentService struct {
+	repo        portRepository.InvoiceAttachmentRepository
+	invoiceRepo portRepository.InvoiceRepository
+	blobs       portRepository.BlobStore
+	tx          portRepository.TxRepository
+}
+
+func NewInvoiceAttachmentService(
+	repo portRepository.InvoiceAttachmentRepository,
+	invoiceRepo portRepository.InvoiceRepository,
+	blobs portRepository.BlobStore,
+	tx portRepository.TxRepository,
+) portService.InvoiceAttachmentService {
+	return &invoiceAttachmentService{
+		repo:        repo,
+		invoiceRepo: invoiceRepo,
+		blobs:       blobs,
+		tx:          tx,
+	}
+}
+
+// Upload stores a pdf or image file with an invoice.
+// AppendToPDF is kept for pdf files only, they are added after the pages of the invoice PDF.
+func (s *invoiceAttachmentService) Upload(ctx context.Context, req *domain.InvoiceAttachmentRequest) (*domain.InvoiceAttachmentResponse, error) {
+	ext, ok := domain.AttachmentExtensions[req.ContentType]
+	if !ok || len(req.Content) == 0 || len(req.Content) > domain.MaxAttachmentSize {
+		return nil, fmt.Errorf(domain.ErrInvalidAttachment)
+	}
+
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// The invoice stays locked until the attachment is inserted, so uploads running side by side can not pass the limit
+	inv, err := s.invoiceRepo.LockByPublicID(ctx, tx, req.UserID, req.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.Count(ctx, tx, req.UserID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to count invoice attachments", zap.Error(err), zap.String("invoice_id", req.InvoiceID))
+		return nil, err
+	}
+	if count >= domain.MaxAttachments {
+		return nil, fmt.Errorf(domain.ErrTooManyAttachments)
+	}
+
+	data := &domain.InvoiceAttachment{
+		AuthorID:    req.UserID,
+		InvoiceID:   inv.ID,
+		Name:        domain.AttachmentName(req.Name, ext),
+		ContentType: req.ContentType,
+		Size:        len(req.Content),
+		BlobKey:     fmt.Sprintf("attachments/%d/%d/%s.%s", req.UserID, inv.ID, uuid.NewString(), ext),
+	}
+	data.AppendToPDF = req.AppendToPDF && data.IsPDF()
+
+	if err = s.blobs.Put(ctx, data.BlobKey, req.Content, data.ContentType); err != nil {
+		logger.StdContextError(ctx, "failed to store attachment file", zap.Error(err), zap.String("invoice_id", req.InvoiceID))
+		return nil, err
+	}
+
+	if err = s.repo.Create(ctx, tx, data); err != nil {
+		logger.StdContextError(ctx, "failed to create invoice attachment", zap.Error(err), zap.String("invoice_id", req.InvoiceID))
+		s.deleteBlob(ctx, data.BlobKey)
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		s.deleteBlob(ctx, data.BlobKey)
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "invoice attachment uploaded", zap.String("invoice_id", req.InvoiceID), zap.Uint("attachment_id", data.ID), zap.Int("size_bytes", data.Size))
+	res := data.Response()
+	return &res, nil
+}
+
+// Get lists the attachments of an invoice in the order they were uploaded
+func (s *invoiceAttachmentService) Get(ctx context.Context, invoiceID string, userID uint) ([]domain.InvoiceAttachmentResponse, error) {
+	inv, err := s.invoiceRepo.GetByPublicID(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.GetByInvoiceID(ctx, userID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get invoice attachments", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return nil, err
+	}
+
+	res := make([]domain.InvoiceAttachmentResponse, len(attachments))
+	for i, v := range attachments {
+		res[i] = v.Response()
+	}
+	return res, nil
+}
+
+func (s *invoiceAttachmentService) Download(ctx context.Context, invoiceID string, id, userID uint) (*domain.InvoiceAttachment, []byte, error) {
+	inv, err := s.invoiceRepo.GetByPublicID(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := s.repo.GetByID(ctx, userID, inv.ID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.blobs.Get(ctx, data.BlobKey)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to read attachment file", zap.Error(err), zap.Uint("attachment_id", id))
+		return nil, nil, err
+	}
+	return data, content, nil
+}
+
+// Delete removes an attachment, its file is removed once the row is gone
+func (s *invoiceAttachmentService) Delete(ctx context.Context, invoiceID string, id, userID uint) error {
+	inv, err := s.invoiceRepo.GetByPublicID(ctx, userID, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	data, err := s.repo.GetByID(ctx, userID, inv.ID, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.Delete(ctx, data); err != nil {
+		logger.StdContextError(ctx, "failed to delete invoice attachment", zap.Error(err), zap.Uint("attachment_id", id))
+		return err
+	}
+	s.deleteBlob(ctx, data.BlobKey)
+
+	logger.StdContextInfo(ctx, "invoice attachment deleted", zap.String("invoice_id", invoiceID), zap.Uint("attachment_id", id))
+	return nil
+}
+
+// deleteBlob removes a file no row refers to anymore, a failure only leaves the file behind
+func (s *invoiceAttachmentService) deleteBlob(ctx context.Context, key string) {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		logger.StdContextWarn(ctx, "failed to delete attachment file", zap.Error(err), zap.String("blob_key", key))
+	}
+}
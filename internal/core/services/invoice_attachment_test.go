@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+)
+
+// attachmentInvoiceRepository locks invoice 10 and records the transaction it was locked in
+type attachmentInvoiceRepository struct {
+	portRepository.InvoiceRepository
+	lockedTx portRepository.Transaction
+}
+
+func (r *attachmentInvoiceRepository) LockByPublicID(ctx context.Context, tx portRepository.Transaction, authorID uint, publicID string) (*domain.Invoice, error) {
+	r.lockedTx = tx
+	return &domain.Invoice{ID: 10, AuthorID: authorID, PublicID: publicID}, nil
+}
+
+// attachmentRepository holds count attachments and records the transactions of its calls
+type attachmentRepository struct {
+	portRepository.InvoiceAttachmentRepository
+	count    int64
+	countTx  portRepository.Transaction
+	createTx portRepository.Transaction
+	created  []domain.InvoiceAttachment
+}
+
+func (r *attachmentRepository) Count(ctx context.Context, tx portRepository.Transaction, authorID uint, invoiceID int64) (int64, error) {
+	r.countTx = tx
+	return r.count, nil
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.InvoiceAttachment) error {
+	r.createTx = tx
+	data.ID = uint(len(r.created) + 1)
+	r.created = append(r.created, *data)
+	return nil
+}
+
+// memoryBlobStore keeps blobs in a map
+type memoryBlobStore map[string][]byte
+
+func (s memoryBlobStore) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	s[key] = content
+	return nil
+}
+
+func (s memoryBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return s[key], nil
+}
+
+func (s memoryBlobStore) Delete(ctx context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func attachmentRequest() *domain.InvoiceAttachmentRequest {
+	return &domain.InvoiceAttachmentRequest{
+		InvoiceID:   "inv-1",
+		Name:        "receipt",
+		ContentType: "application/pdf",
+		Content:     []byte("%PDF-1.4"),
+		UserID:      1,
+	}
+}
+
+func TestInvoiceAttachmentUploadLocksInvoice(t *testing.T) {
+	invoices := &attachmentInvoiceRepository{}
+	attachments := &attachmentRepository{count: domain.MaxAttachments - 1}
+	blobs := memoryBlobStore{}
+	txs := &fakeTxRepository{}
+	s := NewInvoiceAttachmentService(attachments, invoices, blobs, txs)
+
+	if _, err := s.Upload(context.Background(), attachmentRequest()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tx := txs.only(t)
+	if !tx.committed {
+		t.Errorf("expected the upload to be committed")
+	}
+	if invoices.lockedTx != tx || attachments.countTx != tx || attachments.createTx != tx {
+		t.Errorf("expected the lock, count and insert to share the transaction")
+	}
+	if len(attachments.created) != 1 || len(blobs) != 1 {
+		t.Errorf("expected one attachment and its file, got %d and %d", len(attachments.created), len(blobs))
+	}
+}
+
+func TestInvoiceAttachmentUploadLimit(t *testing.T) {
+	attachments := &attachmentRepository{count: domain.MaxAttachments}
+	blobs := memoryBlobStore{}
+	txs := &fakeTxRepository{}
+	s := NewInvoiceAttachmentService(attachments, &attachmentInvoiceRepository{}, blobs, txs)
+
+	_, err := s.Upload(context.Background(), attachmentRequest())
+	if err == nil || err.Error() != domain.ErrTooManyAttachments {
+		t.Fatalf("expected %q, got %v", domain.ErrTooManyAttachments, err)
+	}
+	if tx := txs.only(t); tx.committed || !tx.rolledBack {
+		t.Errorf("expected the upload to be rolled back")
+	}
+	if len(attachments.created) != 0 || len(blobs) != 0 {
+		t.Errorf("expected nothing stored past the limit")
+	}
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/config"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// exportBatchSize is how many invoices are read at a time while exporting
+	exportBatchSize = 500
+	// exportRunLimit bounds how many background exports one scheduler pass runs
+	exportRunLimit = 5
+)
+
+type invoiceExportService struct {
+	timeout     time.Duration
+	ttl         time.Duration
+	prefix      string
+	streamLimit int
+	repo        portRepository.InvoiceExportRepository
+	invoiceRepo portRepository.InvoiceRepository
+	blobs       portRepository.BlobStore
+}
+
+func NewInvoiceExportService(
+	cfg *config.ExportConfig,
+	repo portRepository.InvoiceExportRepository,
+	invoiceRepo portRepository.InvoiceRepository,
+	blobs portRepository.BlobStore,
+) portService.InvoiceExportService {
+	return &invoiceExportService{
+		timeout:     cfg.Timeout,
+		ttl:         cfg.TTL,
+		prefix:      cfg.Prefix,
+		streamLimit: cfg.StreamLimit,
+		repo:        repo,
+		invoiceRepo: invoiceRepo,
+		blobs:       blobs,
+	}
+}
+
+// Prepare checks the filters of an export, one matching more invoices than can be streamed is queued instead
+func (s *invoiceExportService) Prepare(ctx context.Context, req *domain.InvoiceExportRequest) (*domain.InvoiceExportResponse, error) {
+	if err := req.Normalize(); err != nil {
+		return nil, err
+	}
+
+	count, err := s.invoiceRepo.Count(ctx, &req.InvoicePageReq)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to count exported invoices", zap.Error(err), zap.Uint("user_id", req.UserID))
+		return nil, err
+	}
+	if count <= int64(s.streamLimit) {
+		return nil, nil
+	}
+
+	logger.StdContextInfo(ctx, "invoice export too large to stream", zap.Uint("user_id", req.UserID), zap.Int64("invoices", count))
+	return s.Create(ctx, req)
+}
+
+// Write reads the invoices batch by batch and writes each row as soon as it is read
+func (s *invoiceExportService) Write(ctx context.Context, req *domain.InvoiceExportRequest, w io.Writer) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var count int
+	err := s.write(ctx, req, w, &count)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to export invoices", zap.Error(err), zap.Uint("user_id", req.UserID), zap.Int("rows", count))
+		return count, err
+	}
+
+	logger.StdContextInfo(ctx, "invoices exported", zap.Uint("user_id", req.UserID), zap.String("format", string(req.Format)), zap.Int("rows", count))
+	return count, nil
+}
+
+func (s *invoiceExportService) write(ctx context.Context, req *domain.InvoiceExportRequest, w io.Writer, count *int) error {
+	sheet, err := newSheetWriter(req.Format, req.Locale, w)
+	if err != nil {
+		return err
+	}
+	if err = sheet.WriteHeader(domain.ExportHeader(req.Rows)); err != nil {
+		return err
+	}
+
+	err = s.invoiceRepo.Export(ctx, &req.InvoicePageReq, exportBatchSize, func(invoices []domain.Invoice) error {
+		if req.Rows != domain.ExportRowsItem {
+			for i := range invoices {
+				if err := sheet.WriteRow(invoices[i].ExportRow()); err != nil {
+					return err
+				}
+				*count++
+			}
+			return nil
+		}
+
+		ids := make([]int64, len(invoices))
+		for i, v := range invoices {
+			ids[i] = v.ID
+		}
+		items, err := s.invoiceRepo.GetItems(ctx, req.UserID, ids)
+		if err != nil {
+			return err
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+		byInvoice := make(map[int64][]domain.InvoiceItem, len(invoices))
+		for _, v := range items {
+			byInvoice[v.InvoiceID] = append(byInvoice[v.InvoiceID], v)
+		}
+
+		for i := range invoices {
+			for j := range byInvoice[invoices[i].ID] {
+				if err := sheet.WriteRow(invoices[i].ExportItemRow(&byInvoice[invoices[i].ID][j])); err != nil {
+					return err
+				}
+				*count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return sheet.Close()
+}
+
+// Create queues an export for the scheduler, its filters are checked right away
+func (s *invoiceExportService) Create(ctx context.Context, req *domain.InvoiceExportRequest) (*domain.InvoiceExportResponse, error) {
+	if err := req.Normalize(); err != nil {
+		return nil, err
+	}
+
+	filters, err := json.Marshal(req.Filters())
+	if err != nil {
+		return nil, err
+	}
+
+	t := time.Now()
+	data := domain.InvoiceExport{
+		AuthorID:  req.UserID,
+		Format:    req.Format,
+		Rows:      req.Rows,
+		Locale:    req.Locale,
+		Filters:   string(filters),
+		Status:    domain.ExportStatusPending,
+		Timestamp: domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+	if err = s.repo.Create(ctx, &data); err != nil {
+		logger.StdContextError(ctx, "failed to create invoice export", zap.Error(err), zap.Uint("user_id", req.UserID))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "invoice export queued", zap.Uint("export_id", data.ID), zap.Uint("user_id", req.UserID))
+	res := data.Response()
+	return &res, nil
+}
+
+func (s *invoiceExportService) GetByID(ctx context.Context, id, userID uint) (*domain.InvoiceExportResponse, error) {
+	export, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := export.Response()
+	return &res, nil
+}
+
+func (s *invoiceExportService) Download(ctx context.Context, id, userID uint) (*domain.InvoiceExport, []byte, error) {
+	export, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case export.Status == domain.ExportStatusFailed:
+		return nil, nil, fmt.Errorf(domain.ErrExportFailed)
+	case export.Status != domain.ExportStatusDone:
+		return nil, nil, fmt.Errorf(domain.ErrExportNotReady)
+	case export.FilePath == "" || (export.ExpiresAt != nil && export.ExpiresAt.Before(time.Now())):
+		return nil, nil, fmt.Errorf(domain.ErrExportExpired)
+	}
+
+	content, err := s.blobs.Get(ctx, export.FilePath)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get export file", zap.Error(err), zap.Uint("export_id", id))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, fmt.Errorf(domain.ErrExportExpired)
+		}
+		return nil, nil, err
+	}
+	return export, content, nil
+}
+
+// RunPending claims exports one at a time, so several replicas can run it side by side
+func (s *invoiceExportService) RunPending(ctx context.Context, now time.Time) (int, error) {
+	s.removeExpired(ctx, now)
+
+	var ran int
+	for ran < exportRunLimit {
+		export, err := s.repo.Claim(ctx, now, now.Add(-s.timeout))
+		if err != nil {
+			logger.StdContextError(ctx, "failed to claim invoice export", zap.Error(err))
+			return ran, err
+		}
+		if export == nil {
+			break
+		}
+		s.run(ctx, export)
+		ran++
+	}
+	return ran, nil
+}
+
+// run writes the file of a background export to the blob store, so any replica can serve its download.
+// A failed export is kept with its error and not retried.
+func (s *invoiceExportService) run(ctx context.Context, export *domain.InvoiceExport) {
+	key := fmt.Sprintf("%sexport_%d.%s", s.prefix, export.ID, export.Format)
+	count, err := s.writeFile(ctx, export, key)
+
+	t := time.Now()
+	export.FinishedAt = &t
+	export.UpdatedAt = t
+	if err != nil {
+		export.Status = domain.ExportStatusFailed
+		export.Error = "export failed, try again"
+	} else {
+		expiresAt := t.Add(s.ttl)
+		export.Status = domain.ExportStatusDone
+		export.RowCount = count
+		export.FilePath = key
+		export.ExpiresAt = &expiresAt
+	}
+
+	if err = s.repo.Finish(ctx, export); err != nil {
+		logger.StdContextError(ctx, "failed to finish invoice export", zap.Error(err), zap.Uint("export_id", export.ID))
+	}
+}
+
+func (s *invoiceExportService) writeFile(ctx context.Context, export *domain.InvoiceExport, key string) (int, error) {
+	var filters domain.InvoiceExportFilters
+	if err := json.Unmarshal([]byte(export.Filters), &filters); err != nil {
+		logger.StdContextError(ctx, "failed to read invoice export filters", zap.Error(err), zap.Uint("export_id", export.ID))
+		return 0, err
+	}
+	req := filters.Request(export)
+	if err := req.Normalize(); err != nil {
+		return 0, err
+	}
+
+	// The file is only stored once complete
+	var buf bytes.Buffer
+	count, err := s.Write(ctx, &req, &buf)
+	if err != nil {
+		return 0, err
+	}
+	if err = s.blobs.Put(ctx, key, buf.Bytes(), export.Format.ContentType()); err != nil {
+		logger.StdContextError(ctx, "failed to store export file", zap.Error(err), zap.Uint("export_id", export.ID))
+		return 0, err
+	}
+	return count, nil
+}
+
+// removeExpired deletes the files of exports past their expiry, the export itself is kept as history
+func (s *invoiceExportService) removeExpired(ctx context.Context, now time.Time) {
+	exports, err := s.repo.GetExpired(ctx, now, exportBatchSize)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get expired invoice exports", zap.Error(err))
+		return
+	}
+
+	for _, v := range exports {
+		if err = s.blobs.Delete(ctx, v.FilePath); err != nil {
+			logger.StdContextWarn(ctx, "failed to remove expired export file", zap.Error(err), zap.Uint("export_id", v.ID))
+			continue
+		}
+		if err = s.repo.ClearFile(ctx, v.ID); err != nil {
+			logger.StdContextWarn(ctx, "failed to clear expired export file", zap.Error(err), zap.Uint("export_id", v.ID))
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+	"app/xonvera-core/internal/utils/validator"
+
+	"go.uber.org/zap"
+)
+
+type invoiceImportService struct {
+	invoiceRepo portRepository.InvoiceRepository
+	invoice     portService.InvoiceService
+	tx          portRepository.TxRepository
+}
+
+func NewInvoiceImportService(invoiceRepo portRepository.InvoiceRepository, invoice portService.InvoiceService, tx portRepository.TxRepository) portService.InvoiceImportService {
+	return &invoiceImportService{
+		invoiceRepo: invoiceRepo,
+		invoice:     invoice,
+		tx:          tx,
+	}
+}
+
+// Import checks every row of a CSV file and creates its invoices in one transaction.
+// Nothing is created while any invoice has errors or fails, references imported before are skipped.
+func (s *invoiceImportService) Import(ctx context.Context, req *domain.InvoiceImportRequest) (*domain.InvoiceImportResponse, error) {
+	if len(req.Content) == 0 || len(req.Content) > domain.MaxImportSize {
+		return nil, fmt.Errorf(domain.ErrInvalidImportFile)
+	}
+
+	im, err := parseImport(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &domain.InvoiceImportResponse{
+		DryRun:   req.DryRun,
+		Rows:     im.Rows,
+		Invoices: len(im.Groups),
+		Results:  make([]domain.InvoiceImportResult, 0, len(im.Groups)),
+		Errors:   []domain.InvoiceImportError{},
+	}
+
+	refs := make([]string, 0, len(im.Groups))
+	for i := range im.Groups {
+		validateImport(&im.Groups[i])
+		refs = append(refs, im.Groups[i].Reference)
+	}
+
+	existing, err := s.invoiceRepo.GetImportRefs(ctx, req.UserID, refs)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get imported references", zap.Error(err))
+		return nil, err
+	}
+	imported := make(map[string]bool, len(existing))
+	for _, v := range existing {
+		imported[v] = true
+	}
+
+	for i := range im.Groups {
+		g := &im.Groups[i]
+		switch {
+		case len(g.Errors) > 0:
+			res.Errors = append(res.Errors, g.Errors...)
+			res.Results = append(res.Results, g.Result(domain.ImportStatusInvalid, "", ""))
+		case imported[g.Reference]:
+			res.Duplicate++
+			res.Results = append(res.Results, g.Result(domain.ImportStatusDuplicate, "", ""))
+		default:
+			// Unknown tax rates, profiles, customers and currencies are reported by a dry run as well
+			g.Request.UserID = req.UserID
+			if err := s.invoice.Check(ctx, &g.Request); err != nil {
+				if !importClientError(err) {
+					return nil, err
+				}
+				g.AddErrors(g.Lines[0], []string{importFailure(err)})
+				res.Errors = append(res.Errors, g.Errors...)
+				res.Results = append(res.Results, g.Result(domain.ImportStatusInvalid, "", ""))
+				continue
+			}
+			res.Results = append(res.Results, g.Result(domain.ImportStatusReady, "", ""))
+		}
+	}
+
+	slices.SortFunc(res.Errors, func(a, b domain.InvoiceImportError) int { return a.Line - b.Line })
+	if req.DryRun || len(res.Errors) > 0 {
+		return res, nil
+	}
+
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	created := make([]string, len(im.Groups))
+	for i := range im.Groups {
+		if res.Results[i].Status != domain.ImportStatusReady {
+			continue
+		}
+
+		g := &im.Groups[i]
+		invoiceID, err := s.invoice.CreateTx(ctx, tx, &g.Request)
+		switch {
+		case err == nil:
+			created[i] = invoiceID
+		case err.Error() == domain.ErrInvoiceAlreadyImported:
+			// Imported by an upload running at the same time
+			res.Duplicate++
+			res.Results[i] = g.Result(domain.ImportStatusDuplicate, "", "")
+		default:
+			// The whole file is rolled back, the other invoices stay ready for the next upload
+			logger.StdContextWarn(ctx, "failed to create imported invoice", zap.Error(err), zap.String("import_ref", g.Reference))
+			g.AddErrors(g.Lines[0], []string{importFailure(err)})
+			res.Errors = append(res.Errors, g.Errors...)
+			res.Results[i] = g.Result(domain.ImportStatusFailed, "", importFailure(err))
+			return res, nil
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return nil, err
+	}
+
+	for i, invoiceID := range created {
+		if invoiceID != "" {
+			res.Created++
+			res.Results[i] = im.Groups[i].Result(domain.ImportStatusCreated, invoiceID, "")
+		}
+	}
+
+	logger.StdContextInfo(ctx, "invoices imported", zap.Int("created", res.Created), zap.Int("duplicate", res.Duplicate), zap.Int("invoices", res.Invoices))
+	return res, nil
+}
+
+// parseImport reads a CSV file separated by commas or semicolons, as written by spreadsheets in either locale
+func parseImport(content []byte) (*domain.InvoiceImport, error) {
+	content = bytes.TrimPrefix(content, []byte("\uFEFF"))
+
+	r := csv.NewReader(bytes.NewReader(content))
+	r.FieldsPerRecord = -1
+	r.Comma = ','
+	header, _, _ := bytes.Cut(content, []byte("\n"))
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		r.Comma = ';'
+	}
+
+	record, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf(domain.ErrInvalidImportFile)
+	}
+	im, err := domain.NewInvoiceImport(record)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		record, err = r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf(domain.ErrInvalidImportFile)
+		}
+		if im.Rows == domain.MaxImportRows {
+			return nil, fmt.Errorf(domain.ErrImportTooLarge)
+		}
+
+		line, _ := r.FieldPos(0)
+		im.Add(line, record)
+	}
+
+	if im.Rows == 0 {
+		return nil, fmt.Errorf(domain.ErrInvalidImportFile)
+	}
+	return im, nil
+}
+
+// validateImport checks an invoice with the rules of the invoice API, invoice errors are put on its first line
+func validateImport(g *domain.InvoiceImportGroup) {
+	head := g.Request
+	head.Items = nil
+	g.AddErrors(g.Lines[0], validator.Validation(&head, "id", "items"))
+
+	for i := range g.Request.Items {
+		g.AddErrors(g.Lines[i], validator.Validation(&g.Request.Items[i]))
+	}
+}
+
+// importClientError reports whether the error is a domain error caused by the request
+func importClientError(err error) bool {
+	code, _, ok := strings.Cut(err.Error(), ":")
+	n, convErr := strconv.Atoi(code)
+	return ok && convErr == nil && n < 500
+}
+
+// importFailure returns the message of a domain error, other errors are not shown to the user
+func importFailure(err error) string {
+	if importClientError(err) {
+		_, msg, _ := strings.Cut(err.Error(), ":")
+		return msg
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "import took too long, upload the file again"
+	}
+	return "internal server error"
+}
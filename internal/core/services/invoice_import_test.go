@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+)
+
+// importInvoiceRepository knows the references imported before
+type importInvoiceRepository struct {
+	portRepository.InvoiceRepository
+	refs []string
+}
+
+func (r *importInvoiceRepository) GetImportRefs(ctx context.Context, authorID uint, refs []string) ([]string, error) {
+	return r.refs, nil
+}
+
+// importInvoiceService fails the references of checkErrs on Check and of createErrs on CreateTx
+type importInvoiceService struct {
+	portService.InvoiceService
+	checkErrs  map[string]string
+	createErrs map[string]string
+	created    []string
+	txs        []portRepository.Transaction
+}
+
+func (s *importInvoiceService) Check(ctx context.Context, req *domain.InvoiceRequest) error {
+	if msg, ok := s.checkErrs[req.ImportRef]; ok {
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (s *importInvoiceService) CreateTx(ctx context.Context, tx portRepository.Transaction, req *domain.InvoiceRequest) (string, error) {
+	s.txs = append(s.txs, tx)
+	if msg, ok := s.createErrs[req.ImportRef]; ok {
+		return "", errors.New(msg)
+	}
+	s.created = append(s.created, req.ImportRef)
+	return "inv-" + req.ImportRef, nil
+}
+
+const importTestFile = "reference,issuer,customer,issue_date,due_date,description,qty,price\n" +
+	"A-1,Acme,Budi,2026-03-10,2026-03-24 00:00:00,Design,1,150000\n" +
+	"B-1,Acme,Andi,2026-03-11,2026-03-25 00:00:00,Hosting,2,50000\n" +
+	"C-1,Acme,Citra,2026-03-12,2026-03-26 00:00:00,Support,1,10000\n"
+
+func statuses(res *domain.InvoiceImportResponse) map[string]domain.ImportStatus {
+	out := make(map[string]domain.ImportStatus, len(res.Results))
+	for _, v := range res.Results {
+		out[v.Reference] = v.Status
+	}
+	return out
+}
+
+func TestInvoiceImportDryRunChecksLookups(t *testing.T) {
+	invoices := &importInvoiceService{checkErrs: map[string]string{"B-1": domain.ErrNotFoundTaxRate}}
+	txs := &fakeTxRepository{}
+	s := NewInvoiceImportService(&importInvoiceRepository{}, invoices, txs)
+
+	res, err := s.Import(context.Background(), &domain.InvoiceImportRequest{DryRun: true, Content: []byte(importTestFile), UserID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := statuses(res)
+	if got["A-1"] != domain.ImportStatusReady || got["B-1"] != domain.ImportStatusInvalid || got["C-1"] != domain.ImportStatusReady {
+		t.Errorf("expected B-1 invalid and the others ready, got %v", got)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Reference != "B-1" || res.Errors[0].Line != 3 {
+		t.Errorf("expected the tax rate error on line 3, got %+v", res.Errors)
+	}
+	if len(txs.txs) != 0 || len(invoices.created) != 0 {
+		t.Errorf("a dry run must not create anything")
+	}
+}
+
+func TestInvoiceImportRollsBackBatch(t *testing.T) {
+	invoices := &importInvoiceService{createErrs: map[string]string{"B-1": domain.ErrNotFoundCustomer}}
+	txs := &fakeTxRepository{}
+	s := NewInvoiceImportService(&importInvoiceRepository{}, invoices, txs)
+
+	res, err := s.Import(context.Background(), &domain.InvoiceImportRequest{Content: []byte(importTestFile), UserID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tx := txs.only(t)
+	if tx.committed || !tx.rolledBack {
+		t.Errorf("expected the batch to be rolled back, committed=%v rolled back=%v", tx.committed, tx.rolledBack)
+	}
+	for _, v := range invoices.txs {
+		if v != tx {
+			t.Errorf("expected every invoice to be created in the import transaction")
+		}
+	}
+
+	got := statuses(res)
+	if res.Created != 0 || got["A-1"] != domain.ImportStatusReady || got["B-1"] != domain.ImportStatusFailed {
+		t.Errorf("expected nothing created and B-1 failed, got %d created, %v", res.Created, got)
+	}
+	if len(res.Errors) == 0 {
+		t.Errorf("expected the failure in the errors so the upload is refused")
+	}
+}
+
+func TestInvoiceImportCommitsBatch(t *testing.T) {
+	invoices := &importInvoiceService{}
+	txs := &fakeTxRepository{}
+	s := NewInvoiceImportService(&importInvoiceRepository{refs: []string{"C-1"}}, invoices, txs)
+
+	res, err := s.Import(context.Background(), &domain.InvoiceImportRequest{Content: []byte(importTestFile), UserID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tx := txs.only(t); !tx.committed {
+		t.Errorf("expected the batch to be committed")
+	}
+	got := statuses(res)
+	if res.Created != 2 || res.Duplicate != 1 || got["A-1"] != domain.ImportStatusCreated || got["C-1"] != domain.ImportStatusDuplicate {
+		t.Errorf("expected A-1 and B-1 created and C-1 skipped, got %d created, %v", res.Created, got)
+	}
+	if res.Results[0].InvoiceID != "inv-A-1" {
+		t.Errorf("expected the invoice ID in the result, got %q", res.Results[0].InvoiceID)
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+type invoiceRevisionService struct {
+	repo        portRepository.InvoiceRevisionRepository
+	invoiceRepo portRepository.InvoiceRepository
+}
+
+func NewInvoiceRevisionService(repo portRepository.InvoiceRevisionRepository, invoiceRepo portRepository.InvoiceRepository) portService.InvoiceRevisionService {
+	return &invoiceRevisionService{
+		repo:        repo,
+		invoiceRepo: invoiceRepo,
+	}
+}
+
+// Get lists who changed an invoice and when, newest first
+func (s *invoiceRevisionService) Get(ctx context.Context, invoiceID string, userID uint) ([]domain.InvoiceRevisionResponse, error) {
+	inv, err := s.invoiceRepo.GetByPublicID(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.repo.GetByInvoiceID(ctx, userID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get invoice revisions", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return nil, err
+	}
+
+	res := make([]domain.InvoiceRevisionResponse, len(revisions))
+	for i, v := range revisions {
+		res[i] = v.Response()
+	}
+	return res, nil
+}
+
+// GetByRevision returns an invoice as it was in one of its revisions
+func (s *invoiceRevisionService) GetByRevision(ctx context.Context, invoiceID string, revision int, userID uint) (*domain.InvoiceRevisionResponse, error) {
+	inv, err := s.invoiceRepo.GetByPublicID(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetByRevision(ctx, userID, inv.ID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := data.Invoice()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to read invoice revision", zap.Error(err), zap.String("invoice_id", invoiceID), zap.Int("revision", revision))
+		return nil, err
+	}
+
+	res := data.Response()
+	res.Invoice = &snapshot
+	return &res, nil
+}
+
+// Diff lists the fields changed from one revision to another, the latest revision when To is not given
+func (s *invoiceRevisionService) Diff(ctx context.Context, invoiceID string, req *domain.InvoiceRevisionDiffRequest, userID uint) (*domain.InvoiceRevisionDiffResponse, error) {
+	inv, err := s.invoiceRepo.GetByPublicID(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := s.repo.GetByRevision(ctx, userID, inv.ID, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	var to *domain.InvoiceRevision
+	if req.To > 0 {
+		to, err = s.repo.GetByRevision(ctx, userID, inv.ID, req.To)
+	} else {
+		to, err = s.repo.GetLatest(ctx, userID, inv.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := domain.DiffRevisions(from, to)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to diff invoice revisions", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return nil, err
+	}
+
+	return &domain.InvoiceRevisionDiffResponse{
+		From:    from.Response(),
+		To:      to.Response(),
+		Changes: changes,
+	}, nil
+}
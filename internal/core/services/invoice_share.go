@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+type invoiceShareService struct {
+	repo        portRepository.InvoiceShareRepository
+	invoiceRepo portRepository.InvoiceRepository
+	invoice     portService.InvoiceService
+	token       portService.TokenService
+}
+
+func NewInvoiceShareService(
+	repo portRepository.InvoiceShareRepository,
+	invoiceRepo portRepository.InvoiceRepository,
+	invoice portService.InvoiceService,
+	token portService.TokenService,
+) portService.InvoiceShareService {
+	return &invoiceShareService{
+		repo:        repo,
+		invoiceRepo: invoiceRepo,
+		invoice:     invoice,
+		token:       token,
+	}
+}
+
+// Get lists the share links of an invoice, tokens are not shown again
+func (s *invoiceShareService) Get(ctx context.Context, invoiceID string, userID uint) ([]domain.InvoiceShareResponse, error) {
+	inv, err := s.invoiceRepo.GetByPublicID(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := s.repo.GetByInvoiceID(ctx, userID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get invoice shares", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return nil, err
+	}
+
+	now := time.Now()
+	res := make([]domain.InvoiceShareResponse, len(shares))
+	for i, v := range shares {
+		res[i] = v.Response(inv.PublicID, "", now)
+	}
+	return res, nil
+}
+
+// Create adds a share link to a sent invoice, the token is only returned here
+func (s *invoiceShareService) Create(ctx context.Context, req *domain.InvoiceShareRequest) (*domain.InvoiceShareResponse, error) {
+	inv, err := s.invoiceRepo.GetByPublicID(ctx, req.UserID, req.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv.Status == domain.InvoiceStatusDraft {
+		return nil, fmt.Errorf(domain.ErrInvoiceNotShareable)
+	}
+
+	t := time.Now()
+	expiresAt := s.token.ShareExpiry(t)
+	if req.ExpiresAt != "" {
+		expiresAt, err = time.ParseInLocation("2006-01-02 15:04:05", req.ExpiresAt, time.Local)
+		if err != nil {
+			logger.StdContextError(ctx, "failed to parse share expiry", zap.Error(err))
+			return nil, err
+		}
+		if !expiresAt.After(t) {
+			return nil, fmt.Errorf(domain.ErrInvalidShareExpiry)
+		}
+	}
+
+	data := domain.InvoiceShare{
+		AuthorID:  req.UserID,
+		InvoiceID: inv.ID,
+		ExpiresAt: expiresAt,
+		Timestamp: domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+	if err = s.repo.Create(ctx, &data); err != nil {
+		logger.StdContextError(ctx, "failed to create invoice share", zap.Error(err), zap.String("invoice_id", req.InvoiceID))
+		return nil, err
+	}
+
+	token, err := s.token.GenerateShareToken(data.ID, data.ExpiresAt)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to generate share token", zap.Error(err), zap.Uint("share_id", data.ID))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "invoice share created successfully", zap.Uint("share_id", data.ID), zap.String("invoice_id", req.InvoiceID))
+	res := data.Response(inv.PublicID, token, t)
+	return &res, nil
+}
+
+// Revoke disables a share link of an invoice right away
+func (s *invoiceShareService) Revoke(ctx context.Context, invoiceID string, shareID, userID uint) error {
+	inv, err := s.invoiceRepo.GetByPublicID(ctx, userID, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	share, err := s.repo.GetByID(ctx, userID, inv.ID, shareID)
+	if err != nil {
+		return err
+	}
+	if share.RevokedAt != nil {
+		return nil
+	}
+
+	t := time.Now()
+	share.RevokedAt = &t
+	share.UpdatedAt = t
+	if err = s.repo.Revoke(ctx, share); err != nil {
+		logger.StdContextError(ctx, "failed to revoke invoice share", zap.Error(err), zap.Uint("share_id", shareID))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "invoice share revoked", zap.Uint("share_id", shareID), zap.String("invoice_id", invoiceID))
+	return nil
+}
+
+// View returns the invoice behind a share token and records the view
+func (s *invoiceShareService) View(ctx context.Context, token string) (*domain.InvoiceResponse, error) {
+	share, invoiceID, err := s.open(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.invoice.GetByID(ctx, invoiceID, share.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordView(ctx, share)
+	return res, nil
+}
+
+// ViewPDF returns the PDF of the invoice behind a share token and records the view
+func (s *invoiceShareService) ViewPDF(ctx context.Context, token string) ([]byte, error) {
+	share, invoiceID, err := s.open(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.invoice.GetPDF(ctx, invoiceID, share.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordView(ctx, share)
+	return res, nil
+}
+
+// open checks a share token and returns its share with the public ID of the shared invoice.
+// A token that does not verify is reported as not found, an expired or revoked share as gone.
+func (s *invoiceShareService) open(ctx context.Context, token string) (*domain.InvoiceShare, string, error) {
+	shareID, err := s.token.ValidateShareToken(token)
+	if err != nil {
+		logger.StdContextWarn(ctx, "invalid share token", zap.Error(err))
+		return nil, "", fmt.Errorf(domain.ErrNotFoundShareLink)
+	}
+
+	share, err := s.repo.GetByTokenID(ctx, shareID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !share.Active(time.Now()) {
+		return nil, "", fmt.Errorf(domain.ErrShareLinkExpired)
+	}
+
+	// Deleted invoices are no longer shared, restoring one brings its links back
+	invoices, err := s.invoiceRepo.GetByIDs(ctx, share.AuthorID, []int64{share.InvoiceID})
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get shared invoice", zap.Error(err), zap.Uint("share_id", share.ID))
+		return nil, "", err
+	}
+	if len(invoices) == 0 || invoices[0].DeletedAt != nil {
+		return nil, "", fmt.Errorf(domain.ErrNotFoundInvoice)
+	}
+
+	return share, invoices[0].PublicID, nil
+}
+
+func (s *invoiceShareService) recordView(ctx context.Context, share *domain.InvoiceShare) {
+	t := time.Now()
+	if err := s.repo.RecordView(ctx, share.ID, t); err != nil {
+		logger.StdContextWarn(ctx, "failed to record invoice share view", zap.Error(err), zap.Uint("share_id", share.ID))
+		return
+	}
+	if share.FirstViewedAt == nil {
+		logger.StdContextInfo(ctx, "shared invoice viewed for the first time", zap.Uint("share_id", share.ID))
+	}
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+)
+
+// statusInvoiceRepository holds one invoice, status changes are written back to it
+type statusInvoiceRepository struct {
+	portRepository.InvoiceRepository
+	invoice domain.Invoice
+	updated bool
+}
+
+func (r *statusInvoiceRepository) LockByPublicID(ctx context.Context, tx portRepository.Transaction, authorID uint, publicID string) (*domain.Invoice, error) {
+	inv := r.invoice
+	return &inv, nil
+}
+
+func (r *statusInvoiceRepository) HasCreditNotes(ctx context.Context, tx portRepository.Transaction, authorID uint, invoiceID int64) (bool, error) {
+	return false, nil
+}
+
+func (r *statusInvoiceRepository) UpdateStatus(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	r.invoice = *data
+	r.updated = true
+	return nil
+}
+
+func (r *statusInvoiceRepository) GetItemsByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceItem, error) {
+	return nil, nil
+}
+
+func (r *statusInvoiceRepository) GetTaxesByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceTax, error) {
+	return nil, nil
+}
+
+// revisionRepository keeps the revisions written
+type revisionRepository struct {
+	portRepository.InvoiceRevisionRepository
+	revisions []domain.InvoiceRevision
+}
+
+func (r *revisionRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.InvoiceRevision) error {
+	r.revisions = append(r.revisions, *data)
+	return nil
+}
+
+// newStatusTest serves one invoice in status at version 1
+func newStatusTest(status domain.InvoiceStatus) (*statusInvoiceRepository, *revisionRepository, *fakeTxRepository, portService.InvoiceService) {
+	invoices := &statusInvoiceRepository{invoice: domain.Invoice{ID: 10, AuthorID: 1, PublicID: "inv-1", Status: status, Version: 1}}
+	revisions := &revisionRepository{}
+	txs := &fakeTxRepository{}
+	s := NewInvoiceService(nil, invoices, revisions, nil, nil, nil, nil, nil, nil, nil, nil, nil, txs, nil)
+	return invoices, revisions, txs, s
+}
+
+func TestInvoiceTransitionRefused(t *testing.T) {
+	cases := []struct {
+		name   string
+		status domain.InvoiceStatus
+		call   func(portService.InvoiceService) error
+	}{
+		{"send sent", domain.InvoiceStatusSent, func(s portService.InvoiceService) error { return s.Send(context.Background(), "inv-1", 1) }},
+		{"send paid", domain.InvoiceStatusPaid, func(s portService.InvoiceService) error { return s.Send(context.Background(), "inv-1", 1) }},
+		{"send void", domain.InvoiceStatusVoid, func(s portService.InvoiceService) error { return s.Send(context.Background(), "inv-1", 1) }},
+		{"void partially paid", domain.InvoiceStatusPartiallyPaid, func(s portService.InvoiceService) error { return s.Void(context.Background(), "inv-1", 1) }},
+		{"void void", domain.InvoiceStatusVoid, func(s portService.InvoiceService) error { return s.Void(context.Background(), "inv-1", 1) }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			invoices, revisions, txs, s := newStatusTest(c.status)
+
+			err := c.call(s)
+			if err == nil || err.Error() != domain.ErrInvalidInvoiceTransition || !strings.HasPrefix(err.Error(), "409:") {
+				t.Fatalf("expected %q, got %v", domain.ErrInvalidInvoiceTransition, err)
+			}
+			if invoices.updated || invoices.invoice.Version != 1 || invoices.invoice.Status != c.status {
+				t.Errorf("expected the invoice untouched at version 1, got %s at version %d", invoices.invoice.Status, invoices.invoice.Version)
+			}
+			if tx := txs.only(t); tx.committed || len(revisions.revisions) != 0 {
+				t.Errorf("expected nothing committed for a refused transition")
+			}
+		})
+	}
+}
+
+func TestInvoiceTransitionVoidsDraft(t *testing.T) {
+	invoices, revisions, txs, s := newStatusTest(domain.InvoiceStatusDraft)
+
+	if err := s.Void(context.Background(), "inv-1", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inv := invoices.invoice; inv.Status != domain.InvoiceStatusVoid || inv.Version != 2 {
+		t.Errorf("expected void at version 2, got %s at version %d", inv.Status, inv.Version)
+	}
+	if len(revisions.revisions) != 1 || revisions.revisions[0].Event != domain.RevisionEvent(domain.InvoiceStatusVoid) {
+		t.Errorf("expected a void revision, got %+v", revisions.revisions)
+	}
+	if tx := txs.only(t); !tx.committed {
+		t.Errorf("expected the transition to be committed")
+	}
+}
+
+// trashInvoiceRepository holds one invoice and finds it only among live or deleted invoices as the SQL lock does
+type trashInvoiceRepository struct {
+	portRepository.InvoiceRepository
+	invoice domain.Invoice
+	purged  bool
+}
+
+func (r *trashInvoiceRepository) LockByPublicID(ctx context.Context, tx portRepository.Transaction, authorID uint, publicID string) (*domain.Invoice, error) {
+	if r.purged || r.invoice.DeletedAt != nil {
+		return nil, errors.New(domain.ErrNotFoundInvoice)
+	}
+	inv := r.invoice
+	return &inv, nil
+}
+
+func (r *trashInvoiceRepository) LockDeletedByPublicID(ctx context.Context, tx portRepository.Transaction, authorID uint, publicID string) (*domain.Invoice, error) {
+	if r.purged || r.invoice.DeletedAt == nil {
+		return nil, errors.New(domain.ErrNotFoundInvoice)
+	}
+	inv := r.invoice
+	return &inv, nil
+}
+
+func (r *trashInvoiceRepository) SoftDelete(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	r.invoice.DeletedAt = data.DeletedAt
+	return nil
+}
+
+func (r *trashInvoiceRepository) Restore(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	r.invoice.DeletedAt = nil
+	return nil
+}
+
+func (r *trashInvoiceRepository) Purge(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	r.purged = true
+	return nil
+}
+
+// trashAttachmentRepository lists no attachments
+type trashAttachmentRepository struct {
+	portRepository.InvoiceAttachmentRepository
+}
+
+func (r *trashAttachmentRepository) GetByInvoiceID(ctx context.Context, authorID uint, invoiceID int64) ([]domain.InvoiceAttachment, error) {
+	return nil, nil
+}
+
+// emptyDocumentStore has no cached documents
+type emptyDocumentStore struct {
+	portRepository.DocumentStore
+}
+
+func (s emptyDocumentStore) List(ctx context.Context, prefix string) ([]domain.StoredDocument, error) {
+	return nil, nil
+}
+
+// newTrashTest serves one invoice in status, deleted when deleted is set
+func newTrashTest(status domain.InvoiceStatus, deleted bool) (*trashInvoiceRepository, *fakeTxRepository, portService.InvoiceService) {
+	invoices := &trashInvoiceRepository{invoice: domain.Invoice{ID: 10, AuthorID: 1, PublicID: "inv-1", Status: status}}
+	if deleted {
+		t := time.Now()
+		invoices.invoice.DeletedAt = &t
+	}
+	txs := &fakeTxRepository{}
+	s := NewInvoiceService(nil, invoices, nil, &trashAttachmentRepository{}, nil, emptyDocumentStore{}, nil, nil, nil, nil, nil, nil, txs, nil)
+	return invoices, txs, s
+}
+
+func TestInvoiceDeleteOnlyDraftOrVoid(t *testing.T) {
+	cases := map[domain.InvoiceStatus]bool{
+		domain.InvoiceStatusDraft:         true,
+		domain.InvoiceStatusSent:          false,
+		domain.InvoiceStatusPartiallyPaid: false,
+		domain.InvoiceStatusPaid:          false,
+		domain.InvoiceStatusOverdue:       false,
+		domain.InvoiceStatusVoid:          true,
+	}
+	for status, deletable := range cases {
+		invoices, txs, s := newTrashTest(status, false)
+
+		err := s.Delete(context.Background(), "inv-1", 1)
+		if deletable {
+			if err != nil || invoices.invoice.DeletedAt == nil || !txs.only(t).committed {
+				t.Errorf("%s: expected the invoice in the trash, got %v", status, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != domain.ErrInvoiceNotDeletable {
+			t.Errorf("%s: expected %q, got %v", status, domain.ErrInvoiceNotDeletable, err)
+		}
+		if invoices.invoice.DeletedAt != nil || txs.only(t).committed {
+			t.Errorf("%s: expected the invoice kept", status)
+		}
+	}
+}
+
+func TestInvoiceRestoreOnlyDeleted(t *testing.T) {
+	invoices, txs, s := newTrashTest(domain.InvoiceStatusDraft, false)
+	err := s.Restore(context.Background(), "inv-1", 1)
+	if err == nil || err.Error() != domain.ErrNotFoundInvoice {
+		t.Fatalf("expected %q for a live invoice, got %v", domain.ErrNotFoundInvoice, err)
+	}
+	if txs.only(t).committed {
+		t.Errorf("expected nothing committed for a live invoice")
+	}
+
+	invoices, txs, s = newTrashTest(domain.InvoiceStatusVoid, true)
+	if err = s.Restore(context.Background(), "inv-1", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if invoices.invoice.DeletedAt != nil || invoices.invoice.Status != domain.InvoiceStatusVoid || !txs.only(t).committed {
+		t.Errorf("expected the invoice back as void, got %+v", invoices.invoice)
+	}
+}
+
+func TestInvoicePurgeOnlyDeleted(t *testing.T) {
+	invoices, txs, s := newTrashTest(domain.InvoiceStatusDraft, false)
+	err := s.Purge(context.Background(), "inv-1", 1)
+	if err == nil || err.Error() != domain.ErrNotFoundInvoice {
+		t.Fatalf("expected %q for a live invoice, got %v", domain.ErrNotFoundInvoice, err)
+	}
+	if invoices.purged || txs.only(t).committed {
+		t.Errorf("expected a live invoice not to be purged")
+	}
+
+	invoices, txs, s = newTrashTest(domain.InvoiceStatusDraft, true)
+	if err = s.Purge(context.Background(), "inv-1", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !invoices.purged || !txs.only(t).committed {
+		t.Errorf("expected the deleted invoice to be purged")
+	}
+}
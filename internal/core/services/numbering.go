@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+// numberedDocuments lists the document types shown in the numbering settings
+var numberedDocuments = []domain.DocumentType{domain.DocumentInvoice, domain.DocumentCreditNote, domain.DocumentQuote}
+
+type numberingService struct {
+	repo portRepository.NumberingRepository
+}
+
+func NewNumberingService(repo portRepository.NumberingRepository) portService.NumberingService {
+	return &numberingService{repo: repo}
+}
+
+// Get lists the numbering scheme of every document type, defaults included
+func (s *numberingService) Get(ctx context.Context, userID uint) ([]domain.NumberingSchemeResponse, error) {
+	res := make([]domain.NumberingSchemeResponse, len(numberedDocuments))
+	for i, document := range numberedDocuments {
+		scheme, err := numberingScheme(ctx, s.repo, userID, document)
+		if err != nil {
+			return nil, err
+		}
+		if res[i], err = s.response(ctx, scheme); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Save replaces the numbering scheme of a document type.
+// Counters are kept, a new template continues the sequence of the current period.
+func (s *numberingService) Save(ctx context.Context, req *domain.NumberingSchemeRequest) (*domain.NumberingSchemeResponse, error) {
+	if _, ok := domain.DefaultNumberingScheme(req.UserID, req.Document); !ok {
+		return nil, fmt.Errorf(domain.ErrNotFoundNumberingScheme)
+	}
+
+	now := time.Now()
+	scheme := domain.NumberingScheme{
+		AuthorID: req.UserID,
+		Document: req.Document,
+		Template: req.Template,
+		Reset:    req.Reset,
+		Timestamp: domain.Timestamp{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+	if err := scheme.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveScheme(ctx, &scheme); err != nil {
+		logger.StdContextError(ctx, "failed to save numbering scheme", zap.Error(err), zap.String("document", string(req.Document)))
+		return nil, err
+	}
+
+	res, err := s.response(ctx, &scheme)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// response previews the number the next document dated today would get
+func (s *numberingService) response(ctx context.Context, scheme *domain.NumberingScheme) (domain.NumberingSchemeResponse, error) {
+	today := domain.CalendarDate(time.Now())
+	seq, err := s.repo.CurrentSequence(ctx, scheme.AuthorID, scheme.Document, scheme.Period(today))
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get document sequence", zap.Error(err), zap.String("document", string(scheme.Document)))
+		return domain.NumberingSchemeResponse{}, err
+	}
+	return scheme.Response(scheme.Format(today, seq+1)), nil
+}
+
+// numberingScheme returns the saved scheme of a document type, or the default one
+func numberingScheme(ctx context.Context, repo portRepository.NumberingRepository, authorID uint, document domain.DocumentType) (*domain.NumberingScheme, error) {
+	scheme, err := repo.GetScheme(ctx, authorID, document)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get numbering scheme", zap.Error(err), zap.String("document", string(document)))
+		return nil, err
+	}
+	if scheme != nil {
+		return scheme, nil
+	}
+
+	def, ok := domain.DefaultNumberingScheme(authorID, document)
+	if !ok {
+		return nil, fmt.Errorf(domain.ErrNotFoundNumberingScheme)
+	}
+	return &def, nil
+}
+
+// nextNumber takes the next number of a document dated date inside tx,
+// the sequence stays locked until tx ends so concurrent documents never share a number
+func nextNumber(ctx context.Context, repo portRepository.NumberingRepository, tx portRepository.Transaction, authorID uint, document domain.DocumentType, date time.Time) (string, error) {
+	scheme, err := numberingScheme(ctx, repo, authorID, document)
+	if err != nil {
+		return "", err
+	}
+
+	seq, err := repo.NextSequence(ctx, tx, authorID, document, scheme.Period(date))
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get next document sequence", zap.Error(err), zap.String("document", string(document)))
+		return "", err
+	}
+	return scheme.Format(date, seq), nil
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+type paymentService struct {
+	repo        portRepository.PaymentRepository
+	invoiceRepo portRepository.InvoiceRepository
+	tx          portRepository.TxRepository
+}
+
+func NewPaymentService(repo portRepository.PaymentRepository, invoiceRepo portRepository.InvoiceRepository, tx portRepository.TxRepository) portService.PaymentService {
+	return &paymentService{
+		repo:        repo,
+		invoiceRepo: invoiceRepo,
+		tx:          tx,
+	}
+}
+
+// Get lists every payment recorded against an invoice, including reversed ones
+func (s *paymentService) Get(ctx context.Context, invoiceID string, userID uint) ([]domain.PaymentResponse, error) {
+	inv, err := s.invoiceRepo.GetByPublicID(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.repo.GetByInvoiceID(ctx, userID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get invoice payments", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return nil, err
+	}
+
+	res := make([]domain.PaymentResponse, len(payments))
+	for i, v := range payments {
+		res[i] = v.Response(inv.PublicID)
+	}
+	return res, nil
+}
+
+// Create records a payment and rolls the invoice to partially paid or paid
+func (s *paymentService) Create(ctx context.Context, req *domain.PaymentRequest) (*domain.PaymentResponse, error) {
+	paidAt, err := time.ParseInLocation(time.DateOnly, req.PaidAt, time.Local)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to parse payment date", zap.Error(err))
+		return nil, err
+	}
+
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	inv, err := s.invoiceRepo.LockByPublicID(ctx, tx, req.UserID, req.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !inv.Status.AcceptsPayment() {
+		return nil, fmt.Errorf(domain.ErrPaymentNotAllowed)
+	}
+	if req.Amount > inv.Balance() {
+		return nil, fmt.Errorf(domain.ErrPaymentExceedsBalance)
+	}
+
+	t := time.Now()
+	payment := domain.Payment{
+		InvoiceID: inv.ID,
+		AuthorID:  req.UserID,
+		Amount:    req.Amount,
+		PaidAt:    paidAt,
+		Method:    req.Method,
+		Reference: req.Reference,
+		Note:      req.Note,
+		Timestamp: domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+
+	if err = s.repo.Create(ctx, tx, &payment); err != nil {
+		logger.StdContextError(ctx, "failed to create payment", zap.Error(err))
+		return nil, err
+	}
+
+	if err = s.settle(ctx, tx, inv, t); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "payment recorded successfully",
+		zap.String("invoice_id", inv.PublicID),
+		zap.Int64("payment_id", payment.ID),
+		zap.Int("amount", payment.Amount),
+		zap.String("status", string(inv.Status)),
+	)
+
+	res := payment.Response(inv.PublicID)
+	return &res, nil
+}
+
+// Reverse cancels a recorded payment and rolls the invoice status back
+func (s *paymentService) Reverse(ctx context.Context, req *domain.ReversePaymentRequest) error {
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	inv, err := s.invoiceRepo.LockByPublicID(ctx, tx, req.UserID, req.InvoiceID)
+	if err != nil {
+		return err
+	}
+
+	payment, err := s.repo.LockByID(ctx, tx, req.UserID, req.PaymentID)
+	if err != nil {
+		return err
+	}
+	if payment.InvoiceID != inv.ID {
+		return fmt.Errorf(domain.ErrNotFoundPayment)
+	}
+	if payment.IsReversed() {
+		return fmt.Errorf(domain.ErrPaymentAlreadyReversed)
+	}
+
+	t := time.Now()
+	payment.ReversedAt = &t
+	payment.ReversalReason = req.Reason
+	payment.UpdatedAt = t
+
+	if err = s.repo.Reverse(ctx, tx, payment); err != nil {
+		logger.StdContextError(ctx, "failed to reverse payment", zap.Error(err), zap.Int64("payment_id", payment.ID))
+		return err
+	}
+
+	if err = s.settle(ctx, tx, inv, t); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "payment reversed successfully",
+		zap.String("invoice_id", inv.PublicID),
+		zap.Int64("payment_id", payment.ID),
+		zap.String("status", string(inv.Status)),
+	)
+	return nil
+}
+
+// settle recalculates the amount paid from the ledger and rolls the invoice status
+func (s *paymentService) settle(ctx context.Context, tx portRepository.Transaction, inv *domain.Invoice, t time.Time) error {
+	paid, err := s.repo.SumByInvoiceID(ctx, tx, inv.AuthorID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to sum invoice payments", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return err
+	}
+
+	inv.AmountPaid = paid
+	inv.Status = inv.SettledStatus(t)
+	inv.Version++
+	inv.UpdatedAt = t
+
+	if err = s.invoiceRepo.UpdateSettlement(ctx, tx, inv); err != nil {
+		logger.StdContextError(ctx, "failed to update invoice settlement", zap.Error(err), zap.String("invoice_id", inv.PublicID))
+		return err
+	}
+	return nil
+}
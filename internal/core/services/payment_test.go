@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+)
+
+// paymentInvoiceRepository holds one invoice, settlements are written back to it
+type paymentInvoiceRepository struct {
+	portRepository.InvoiceRepository
+	invoice domain.Invoice
+}
+
+func (r *paymentInvoiceRepository) LockByPublicID(ctx context.Context, tx portRepository.Transaction, authorID uint, publicID string) (*domain.Invoice, error) {
+	inv := r.invoice
+	return &inv, nil
+}
+
+func (r *paymentInvoiceRepository) UpdateSettlement(ctx context.Context, tx portRepository.Transaction, data *domain.Invoice) error {
+	r.invoice = *data
+	return nil
+}
+
+// paymentRepository is an in-memory ledger of the payments of one invoice
+type paymentRepository struct {
+	portRepository.PaymentRepository
+	payments []domain.Payment
+}
+
+func (r *paymentRepository) LockByID(ctx context.Context, tx portRepository.Transaction, authorID uint, id int64) (*domain.Payment, error) {
+	payment := r.payments[id-1]
+	return &payment, nil
+}
+
+func (r *paymentRepository) SumByInvoiceID(ctx context.Context, tx portRepository.Transaction, authorID uint, invoiceID int64) (int, error) {
+	var sum int
+	for _, v := range r.payments {
+		if !v.IsReversed() {
+			sum += v.Amount
+		}
+	}
+	return sum, nil
+}
+
+func (r *paymentRepository) Create(ctx context.Context, tx portRepository.Transaction, data *domain.Payment) error {
+	data.ID = int64(len(r.payments) + 1)
+	r.payments = append(r.payments, *data)
+	return nil
+}
+
+func (r *paymentRepository) Reverse(ctx context.Context, tx portRepository.Transaction, data *domain.Payment) error {
+	r.payments[data.ID-1] = *data
+	return nil
+}
+
+// newPaymentTest serves an invoice of 1000 in status, due days from today
+func newPaymentTest(status domain.InvoiceStatus, days int) (*paymentInvoiceRepository, *paymentRepository, *fakeTxRepository, portService.PaymentService) {
+	invoices := &paymentInvoiceRepository{invoice: domain.Invoice{
+		ID:       10,
+		AuthorID: 1,
+		PublicID: "inv-1",
+		Status:   status,
+		Total:    1000,
+		DueDate:  time.Now().AddDate(0, 0, days),
+		Version:  1,
+	}}
+	payments := &paymentRepository{}
+	txs := &fakeTxRepository{}
+	return invoices, payments, txs, NewPaymentService(payments, invoices, txs)
+}
+
+func pay(amount int) *domain.PaymentRequest {
+	return &domain.PaymentRequest{
+		Amount:    amount,
+		PaidAt:    "2026-03-20",
+		Method:    domain.PaymentMethodBankTransfer,
+		InvoiceID: "inv-1",
+		UserID:    1,
+	}
+}
+
+func TestPaymentCreateSettlesInvoice(t *testing.T) {
+	invoices, _, txs, s := newPaymentTest(domain.InvoiceStatusSent, 14)
+
+	if _, err := s.Create(context.Background(), pay(400)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inv := invoices.invoice; inv.Status != domain.InvoiceStatusPartiallyPaid || inv.AmountPaid != 400 || inv.Version != 2 {
+		t.Errorf("expected partially paid 400 at version 2, got %s %d at version %d", inv.Status, inv.AmountPaid, inv.Version)
+	}
+
+	if _, err := s.Create(context.Background(), pay(600)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inv := invoices.invoice; inv.Status != domain.InvoiceStatusPaid || inv.AmountPaid != 1000 || inv.Version != 3 {
+		t.Errorf("expected paid 1000 at version 3, got %s %d at version %d", inv.Status, inv.AmountPaid, inv.Version)
+	}
+	for _, v := range txs.txs {
+		if !v.committed {
+			t.Errorf("expected every payment to be committed")
+		}
+	}
+}
+
+func TestPaymentCreateRefusesOverpayment(t *testing.T) {
+	invoices, payments, txs, s := newPaymentTest(domain.InvoiceStatusPartiallyPaid, 14)
+	invoices.invoice.AmountPaid = 400
+	payments.payments = []domain.Payment{{ID: 1, InvoiceID: 10, Amount: 400}}
+
+	_, err := s.Create(context.Background(), pay(601))
+	if err == nil || err.Error() != domain.ErrPaymentExceedsBalance {
+		t.Fatalf("expected %q, got %v", domain.ErrPaymentExceedsBalance, err)
+	}
+	if len(payments.payments) != 1 || invoices.invoice.Version != 1 {
+		t.Errorf("expected nothing recorded for an overpayment")
+	}
+	if tx := txs.only(t); tx.committed {
+		t.Errorf("expected the overpayment not to be committed")
+	}
+}
+
+func TestPaymentCreateRefusesStatus(t *testing.T) {
+	for _, status := range []domain.InvoiceStatus{domain.InvoiceStatusDraft, domain.InvoiceStatusVoid, domain.InvoiceStatusPaid} {
+		_, payments, _, s := newPaymentTest(status, 14)
+
+		_, err := s.Create(context.Background(), pay(100))
+		if err == nil || err.Error() != domain.ErrPaymentNotAllowed {
+			t.Errorf("%s: expected %q, got %v", status, domain.ErrPaymentNotAllowed, err)
+		}
+		if len(payments.payments) != 0 {
+			t.Errorf("%s: expected no payment recorded", status)
+		}
+	}
+}
+
+func TestPaymentReverseRollsStatusBack(t *testing.T) {
+	cases := []struct {
+		name     string
+		days     int
+		payments []int
+		status   domain.InvoiceStatus
+		paid     int
+	}{
+		{"partially paid", 14, []int{400, 600}, domain.InvoiceStatusPartiallyPaid, 400},
+		{"overdue", -1, []int{1000}, domain.InvoiceStatusOverdue, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			invoices, payments, txs, s := newPaymentTest(domain.InvoiceStatusSent, c.days)
+			for _, v := range c.payments {
+				if _, err := s.Create(context.Background(), pay(v)); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if invoices.invoice.Status != domain.InvoiceStatusPaid {
+				t.Fatalf("expected paid before the reversal, got %s", invoices.invoice.Status)
+			}
+
+			last := int64(len(c.payments))
+			err := s.Reverse(context.Background(), &domain.ReversePaymentRequest{Reason: "bounced", InvoiceID: "inv-1", PaymentID: last, UserID: 1})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			inv := invoices.invoice
+			if inv.Status != c.status || inv.AmountPaid != c.paid {
+				t.Errorf("expected %s with %d paid, got %s with %d", c.status, c.paid, inv.Status, inv.AmountPaid)
+			}
+			if !payments.payments[last-1].IsReversed() || !txs.txs[len(txs.txs)-1].committed {
+				t.Errorf("expected the reversal to be recorded and committed")
+			}
+
+			err = s.Reverse(context.Background(), &domain.ReversePaymentRequest{Reason: "again", InvoiceID: "inv-1", PaymentID: last, UserID: 1})
+			if err == nil || err.Error() != domain.ErrPaymentAlreadyReversed {
+				t.Errorf("expected %q, got %v", domain.ErrPaymentAlreadyReversed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,500 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"github.com/johnfercher/maroto/v2"
+	"github.com/johnfercher/maroto/v2/pkg/components/image"
+	"github.com/johnfercher/maroto/v2/pkg/components/line"
+	"github.com/johnfercher/maroto/v2/pkg/components/text"
+	cfgPdf "github.com/johnfercher/maroto/v2/pkg/config"
+	"github.com/johnfercher/maroto/v2/pkg/consts/align"
+	"github.com/johnfercher/maroto/v2/pkg/consts/extension"
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/consts/pagesize"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"github.com/johnfercher/maroto/v2/pkg/props"
+	"go.uber.org/zap"
+)
+
+// pdfLayoutVersion is part of the key of every cached PDF, bump it when a change to the templates
+// should render documents again instead of serving what was cached before
+const pdfLayoutVersion = 1
+
+// pdfContentType is what rendered documents are stored as
+const pdfContentType = "application/pdf"
+
+// pdfIssuer is the business profile printed on a document PDF
+type pdfIssuer struct {
+	profile  *domain.BusinessProfile
+	accounts []domain.BankAccount
+	logo     []byte
+}
+
+// content is what of the issuer goes into the key of a cached PDF
+func (i *pdfIssuer) content() any {
+	if i == nil {
+		return nil
+	}
+	return struct {
+		Profile  *domain.BusinessProfile
+		Accounts []domain.BankAccount
+		Logo     []byte
+	}{i.profile, i.accounts, i.logo}
+}
+
+// cachedPDF returns the rendering stored under key, a missing or unreadable one is rendered again
+func cachedPDF(ctx context.Context, store portRepository.DocumentStore, key string) ([]byte, bool) {
+	pdfBytes, err := store.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.StdContextWarn(ctx, "failed to read cached pdf", zap.Error(err), zap.String("document_key", key))
+		}
+		return nil, false
+	}
+	return pdfBytes, true
+}
+
+// storePDF keeps a rendering for the next download, a failure only costs rendering it again
+func storePDF(ctx context.Context, store portRepository.DocumentStore, key string, pdfBytes []byte) {
+	if err := store.Put(ctx, key, pdfBytes, pdfContentType); err != nil {
+		logger.StdContextWarn(ctx, "failed to store rendered pdf", zap.Error(err), zap.String("document_key", key))
+	}
+}
+
+// removeDocuments deletes every cached rendering of a document that is gone, what fails to delete
+// is left to the cleanup job
+func removeDocuments(ctx context.Context, store portRepository.DocumentStore, doc domain.DocumentType, id string) {
+	docs, err := store.List(ctx, domain.DocumentPrefix(doc, id))
+	if err != nil {
+		logger.StdContextWarn(ctx, "failed to list cached pdfs", zap.Error(err), zap.String("document_id", id))
+		return
+	}
+	for _, v := range docs {
+		if err = store.Delete(ctx, v.Key); err != nil {
+			logger.StdContextWarn(ctx, "failed to remove cached pdf", zap.Error(err), zap.String("document_key", v.Key))
+		}
+	}
+}
+
+// pdfParty is the customer snapshot printed opposite the issuer
+type pdfParty struct {
+	Name    string
+	Company string
+	Address string
+	Email   string
+	Phone   string
+	TaxID   string
+}
+
+// loadPDFIssuer loads a business profile with its bank accounts and its logo from the blob store.
+// Documents without a profile, or whose profile was deleted, print the issuer name only.
+func loadPDFIssuer(ctx context.Context, repo portRepository.BusinessProfileRepository, blobs portRepository.BlobStore, authorID uint, profileID *uint) (*pdfIssuer, error) {
+	if profileID == nil {
+		return nil, nil
+	}
+
+	profile, err := repo.GetByID(ctx, authorID, *profileID)
+	if err != nil {
+		if err.Error() == domain.ErrNotFoundBusinessProfile {
+			return nil, nil
+		}
+		logger.StdContextError(ctx, "failed to get business profile", zap.Error(err), zap.Uint("profile_id", *profileID))
+		return nil, err
+	}
+
+	accounts, err := repo.GetBankAccounts(ctx, []uint{profile.ID})
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get bank accounts", zap.Error(err), zap.Uint("profile_id", profile.ID))
+		return nil, err
+	}
+
+	issuer := &pdfIssuer{profile: profile, accounts: accounts}
+	if profile.LogoPath != "" {
+		// A missing logo file should not block the document, it is printed without it
+		issuer.logo, err = blobs.Get(ctx, profile.LogoPath)
+		if err != nil {
+			logger.StdContextWarn(ctx, "failed to read business profile logo", zap.Error(err), zap.Uint("profile_id", profile.ID))
+		}
+	}
+	return issuer, nil
+}
+
+// loadPDFSettings returns the template, language and brand color of the account a document belongs to
+func loadPDFSettings(ctx context.Context, repo portRepository.UserRepository, authorID uint) (domain.PDFSettings, error) {
+	user, err := repo.FindByID(ctx, authorID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get user pdf settings", zap.Error(err), zap.Uint("user_id", authorID))
+		return domain.PDFSettings{}, err
+	}
+	return user.PDFSettings(), nil
+}
+
+// pdfStyle is what a template changes about a document, every template prints the same sections in the same order
+type pdfStyle struct {
+	margin     float64
+	textSize   float64
+	smallSize  float64
+	titleSize  float64
+	headSize   float64
+	logoHeight float64
+	// banner prints the title and number in white on a band of the brand color
+	banner bool
+	// fillHead fills the item table header with the brand color
+	fillHead bool
+	// rule draws a line of the brand color below the item table header
+	rule bool
+	// stripes shades every other item row
+	stripes bool
+}
+
+var pdfStyles = map[domain.PDFTemplate]pdfStyle{
+	domain.PDFTemplateClassic: {margin: 10, textSize: 10, smallSize: 9, titleSize: 30, headSize: 11, logoHeight: 20},
+	domain.PDFTemplateModern:  {margin: 15, textSize: 10, smallSize: 9, titleSize: 22, headSize: 10, logoHeight: 18, banner: true, fillHead: true, stripes: true},
+	domain.PDFTemplateCompact: {margin: 8, textSize: 8, smallSize: 7, titleSize: 14, headSize: 8, logoHeight: 12, rule: true},
+}
+
+var (
+	pdfWhite  = &props.Color{Red: 255, Green: 255, Blue: 255}
+	pdfStripe = &props.Color{Red: 244, Green: 245, Blue: 247}
+)
+
+// pdfDocument renders a document with the template, language and brand color of its account
+type pdfDocument struct {
+	m      core.Maroto
+	style  pdfStyle
+	locale domain.Locale
+	labels domain.PDFLabels
+	brand  *props.Color
+}
+
+func newPDFDocument(settings domain.PDFSettings, debug bool) *pdfDocument {
+	style, ok := pdfStyles[settings.Template]
+	if !ok {
+		style = pdfStyles[domain.DefaultPDFTemplate]
+	}
+	red, green, blue := settings.RGB()
+
+	cfg := cfgPdf.NewBuilder().
+		WithPageSize(pagesize.A4).
+		WithLeftMargin(style.margin).
+		WithTopMargin(style.margin).
+		WithRightMargin(style.margin).
+		WithDefaultFont(&props.Font{Size: style.textSize}).
+		WithDebug(debug).
+		Build()
+
+	return &pdfDocument{
+		m:      maroto.New(cfg),
+		style:  style,
+		locale: settings.Locale,
+		labels: settings.Locale.PDFLabels(),
+		brand:  &props.Color{Red: red, Green: green, Blue: blue},
+	}
+}
+
+// header renders the logo, title and number of a document with its dates below them.
+// Classic puts the number next to the logo above a large title, the other templates put it next to the title.
+func (d *pdfDocument) header(issuer *pdfIssuer, title, number string, dates ...string) {
+	hasLogo := issuer != nil && len(issuer.logo) > 0
+	numberProps := props.Text{Size: d.style.headSize + 1, Style: fontstyle.Bold, Align: align.Right}
+	titleProps := props.Text{Size: d.style.titleSize, Style: fontstyle.Bold, Color: d.brand}
+
+	if d.style.banner || d.style.rule {
+		if hasLogo {
+			d.m.AddRow(d.style.logoHeight, image.NewFromBytesCol(3, issuer.logo, logoExtension(issuer.profile.LogoPath)))
+		}
+		numberProps.Top = d.style.titleSize / 5
+		if !d.style.banner {
+			d.m.AddAutoRow(text.NewCol(8, title, titleProps), text.NewCol(4, number, numberProps))
+		} else {
+			titleProps.Color, numberProps.Color = pdfWhite, pdfWhite
+			titleProps.Left, titleProps.Top, titleProps.Bottom = 3, 2, 2
+			numberProps.Right = 3
+			d.m.AddAutoRow(text.NewCol(8, title, titleProps), text.NewCol(4, number, numberProps)).
+				WithStyle(&props.Cell{BackgroundColor: d.brand})
+		}
+	} else {
+		if hasLogo {
+			d.m.AddRow(d.style.logoHeight,
+				image.NewFromBytesCol(3, issuer.logo, logoExtension(issuer.profile.LogoPath)),
+				text.NewCol(5, ""),
+				text.NewCol(4, number, numberProps),
+			)
+		} else {
+			d.m.AddAutoRow(text.NewCol(8, ""), text.NewCol(4, number, numberProps))
+		}
+		d.m.AddAutoRow(text.NewCol(12, title, titleProps))
+	}
+
+	for _, v := range dates {
+		if v != "" {
+			d.m.AddAutoRow(text.NewCol(12, v, props.Text{Size: d.style.textSize + 2}))
+		}
+	}
+}
+
+// line renders a line of text across the page, e.g. the quote validity or a note
+func (d *pdfDocument) line(value string, style fontstyle.Type) {
+	d.m.AddAutoRow(text.NewCol(12, value, props.Text{Size: d.style.smallSize + 1, Style: style}))
+}
+
+func (d *pdfDocument) space() {
+	d.m.AddAutoRow(text.NewCol(12, ""))
+}
+
+// parties renders the customer and the issuer side by side
+func (d *pdfDocument) parties(issuer *pdfIssuer, issuerName string, customer pdfParty) {
+	d.m.AddAutoRow(text.NewCol(6, d.labels.BillTo, props.Text{Style: fontstyle.Bold, Color: d.brand}))
+	d.m.AddAutoRow(text.NewCol(6, customer.Name), text.NewCol(6, issuerName))
+
+	// Snapshotted customer details next to the issuer address block, empty lines are skipped
+	customerLines := nonEmpty(
+		customer.Company,
+		customer.Address,
+		customer.Email,
+		customer.Phone,
+		d.taxIDLine(customer.TaxID),
+	)
+	var issuerLines []string
+	if issuer != nil {
+		legalName := issuer.profile.LegalName
+		if legalName == issuerName {
+			legalName = ""
+		}
+		issuerLines = nonEmpty(
+			legalName,
+			issuer.profile.Address,
+			issuer.profile.Email,
+			issuer.profile.Phone,
+			d.taxIDLine(issuer.profile.TaxID),
+		)
+	}
+	small := props.Text{Size: d.style.smallSize}
+	for i := 0; i < len(customerLines) || i < len(issuerLines); i++ {
+		var left, right string
+		if i < len(customerLines) {
+			left = customerLines[i]
+		}
+		if i < len(issuerLines) {
+			right = issuerLines[i]
+		}
+		d.m.AddAutoRow(text.NewCol(6, left, small), text.NewCol(6, right, small))
+	}
+}
+
+// footer renders the payment instructions, when asked for, and the footer text of the business profile
+func (d *pdfDocument) footer(issuer *pdfIssuer, instructions bool) {
+	if issuer == nil {
+		return
+	}
+
+	small := props.Text{Size: d.style.smallSize}
+	if instructions && len(issuer.accounts) > 0 {
+		d.space()
+		d.m.AddAutoRow(text.NewCol(12, d.labels.PaymentInstructions, props.Text{Style: fontstyle.Bold, Color: d.brand}))
+		for _, v := range issuer.accounts {
+			d.m.AddAutoRow(text.NewCol(12, fmt.Sprintf("%s %s %s %s", v.BankName, v.AccountNumber, d.labels.AccountHolder, v.AccountName), small))
+		}
+	}
+
+	if issuer.profile.FooterText != "" {
+		d.space()
+		d.m.AddAutoRow(text.NewCol(12, issuer.profile.FooterText, props.Text{Size: d.style.smallSize, Style: fontstyle.Italic}))
+	}
+}
+
+// pdfItem is a line of the item table, Amount is printed as given
+type pdfItem struct {
+	Description    string
+	Qty            int
+	Price          int
+	TaxRate        *int
+	Amount         int
+	DiscountType   domain.DiscountType
+	Discount       int
+	DiscountAmount int
+}
+
+// invoicePDFItems prints invoice and quote items before discounts in the pricing basis of the document
+func invoicePDFItems(items []domain.InvoiceItemResponse) []pdfItem {
+	res := make([]pdfItem, len(items))
+	for i, v := range items {
+		res[i] = pdfItem{
+			Description:    v.Description,
+			Qty:            v.Qty,
+			Price:          v.Price,
+			Amount:         v.Qty * v.Price,
+			DiscountType:   v.DiscountType,
+			Discount:       v.Discount,
+			DiscountAmount: v.DiscountAmount,
+		}
+		if v.TaxRateID != nil {
+			res[i].TaxRate = &v.TaxRate
+		}
+	}
+	return res
+}
+
+// items renders the item table, an item discount is printed as its own line right below the item
+func (d *pdfDocument) items(items []pdfItem, currency string) {
+	head := props.Text{Size: d.style.headSize, Style: fontstyle.Bold, Align: align.Center}
+	if d.style.fillHead {
+		head.Color, head.Top, head.Bottom = pdfWhite, 1, 1
+	}
+	row := d.m.AddAutoRow(
+		text.NewCol(1, d.labels.No, head),
+		text.NewCol(4, d.labels.Item, head),
+		text.NewCol(1, d.labels.Qty, head),
+		text.NewCol(2, d.labels.Price, head),
+		text.NewCol(1, d.labels.Tax, head),
+		text.NewCol(3, d.labels.Amount, head),
+	)
+	if d.style.fillHead {
+		row.WithStyle(&props.Cell{BackgroundColor: d.brand})
+	}
+	if d.style.rule {
+		d.m.AddRow(2, line.NewCol(12, props.Line{Color: d.brand, Thickness: 0.5}))
+	}
+
+	for i, item := range items {
+		taxLabel := "-"
+		if item.TaxRate != nil {
+			taxLabel = d.locale.FormatTaxRate(*item.TaxRate)
+		}
+
+		rows := []core.Row{d.m.AddAutoRow(
+			text.NewCol(1, strconv.Itoa(i+1), props.Text{Align: align.Center}),
+			text.NewCol(4, item.Description),
+			text.NewCol(1, d.locale.FormatNumber(item.Qty, 0), props.Text{Align: align.Center}),
+			text.NewCol(2, d.money(item.Price, currency), props.Text{Align: align.Right}),
+			text.NewCol(1, taxLabel, props.Text{Align: align.Center}),
+			text.NewCol(3, d.money(item.Amount, currency), props.Text{Align: align.Right}),
+		)}
+
+		if item.DiscountAmount > 0 {
+			rows = append(rows, d.m.AddAutoRow(
+				text.NewCol(1, ""),
+				text.NewCol(8, d.labels.DiscountLabel(item.DiscountType, item.Discount), props.Text{Style: fontstyle.Italic}),
+				text.NewCol(3, d.money(-item.DiscountAmount, currency), props.Text{Style: fontstyle.Italic, Align: align.Right}),
+			))
+		}
+
+		if d.style.stripes && i%2 == 1 {
+			for _, v := range rows {
+				v.WithStyle(&props.Cell{BackgroundColor: pdfStripe})
+			}
+		}
+	}
+
+	d.space()
+}
+
+// pdfTotals is the price breakdown printed below the item table
+type pdfTotals struct {
+	Subtotal       int
+	DiscountType   domain.DiscountType
+	Discount       int
+	DiscountAmount int
+	Taxes          []domain.InvoiceTaxResponse
+	TaxMode        domain.TaxMode
+	Total          int
+	Currency       string
+	// TotalLabel replaces the Total label, e.g. for the total of a credit note
+	TotalLabel string
+}
+
+// totals renders the subtotal, discount, tax breakdown and total of a document
+func (d *pdfDocument) totals(t pdfTotals) {
+	d.totalRow(d.labels.Subtotal, d.money(t.Subtotal, t.Currency), false)
+	if t.DiscountAmount > 0 {
+		d.totalRow(d.labels.DiscountLabel(t.DiscountType, t.Discount), d.money(-t.DiscountAmount, t.Currency), false)
+	}
+	for _, tax := range t.Taxes {
+		label := tax.Name + " " + d.locale.FormatTaxRate(tax.Rate)
+		if t.TaxMode == domain.TaxModeInclusive {
+			label = d.labels.Included + " " + label
+		}
+		d.totalRow(label, d.money(tax.TaxAmount, t.Currency), false)
+	}
+
+	label := t.TotalLabel
+	if label == "" {
+		label = d.labels.Total
+	}
+	d.totalRow(label, d.money(t.Total, t.Currency), true)
+}
+
+// totalRow renders a right aligned label and amount below the item table, the total is printed in the brand color
+func (d *pdfDocument) totalRow(label, amount string, total bool) {
+	p := props.Text{Align: align.Right}
+	if total {
+		p.Style, p.Color = fontstyle.Bold, d.brand
+	}
+	d.m.AddAutoRow(
+		text.NewCol(6, ""),
+		text.NewCol(3, label, p),
+		text.NewCol(3, amount, p),
+	)
+}
+
+func (d *pdfDocument) money(amount int, currency string) string {
+	return d.locale.FormatMoney(amount, currency)
+}
+
+// date prints a stored date (YYYY-MM-DD) with the month name of the locale, other values are printed as they are
+func (d *pdfDocument) date(value string) string {
+	if len(value) < len(time.DateOnly) {
+		return value
+	}
+	t, err := time.Parse(time.DateOnly, value[:len(time.DateOnly)])
+	if err != nil {
+		return value
+	}
+	return d.locale.FormatLongDate(t)
+}
+
+// dateLine labels a date, e.g. "Date: October 16, 2026", empty dates print nothing
+func (d *pdfDocument) dateLine(label, value string) string {
+	if value == "" {
+		return ""
+	}
+	return label + ": " + d.date(value)
+}
+
+// taxIDLine prints a tax ID (NPWP) with its label when present
+func (d *pdfDocument) taxIDLine(taxID string) string {
+	if taxID == "" {
+		return ""
+	}
+	return d.labels.TaxID + " " + taxID
+}
+
+// logoExtension picks the image type of a stored logo from its file name
+func logoExtension(path string) extension.Type {
+	if strings.HasSuffix(path, ".png") {
+		return extension.Png
+	}
+	return extension.Jpg
+}
+
+// nonEmpty drops empty lines from an address block
+func nonEmpty(lines ...string) []string {
+	res := make([]string, 0, len(lines))
+	for _, v := range lines {
+		if v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+type pdfSettingsService struct {
+	userRepo portRepository.UserRepository
+}
+
+func NewPDFSettingsService(userRepo portRepository.UserRepository) portService.PDFSettingsService {
+	return &pdfSettingsService{
+		userRepo: userRepo,
+	}
+}
+
+// Get returns the template, language and brand color documents of the account are rendered with
+func (s *pdfSettingsService) Get(ctx context.Context, userID uint) (*domain.PDFSettingsResponse, error) {
+	settings, err := loadPDFSettings(ctx, s.userRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := settings.Response()
+	return &res, nil
+}
+
+// Update changes how documents of the account are rendered, an empty brand color goes back to the default.
+// Cached PDFs are named after their settings, so documents are rendered again on their next download.
+func (s *pdfSettingsService) Update(ctx context.Context, req *domain.PDFSettingsRequest) (*domain.PDFSettingsResponse, error) {
+	user := domain.User{
+		PDFTemplate: string(req.Template),
+		PDFLocale:   string(req.Locale),
+		BrandColor:  strings.ToUpper(req.BrandColor),
+	}
+	if err := s.userRepo.UpdatePDFSettings(ctx, req.UserID, user.PDFTemplate, user.PDFLocale, user.BrandColor); err != nil {
+		logger.StdContextError(ctx, "failed to update pdf settings", zap.Error(err), zap.Uint("user_id", req.UserID))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "pdf settings updated successfully", zap.Uint("user_id", req.UserID), zap.String("template", user.PDFTemplate))
+	res := user.PDFSettings().Response()
+	return &res, nil
+}
@@ -0,0 +1,598 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/config"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"github.com/johnfercher/maroto/v2/pkg/consts/fontstyle"
+	"github.com/johnfercher/maroto/v2/pkg/core"
+	"go.uber.org/zap"
+)
+
+type quoteService struct {
+	cfg           *config.AppConfig
+	repo          portRepository.QuoteRepository
+	invoice       portService.InvoiceService
+	taxRepo       portRepository.TaxRateRepository
+	userRepo      portRepository.UserRepository
+	customerRepo  portRepository.CustomerRepository
+	profileRepo   portRepository.BusinessProfileRepository
+	numberingRepo portRepository.NumberingRepository
+	blobs         portRepository.BlobStore
+	documents     portRepository.DocumentStore
+	tx            portRepository.TxRepository
+}
+
+func NewQuoteService(
+	cfg *config.AppConfig,
+	repo portRepository.QuoteRepository,
+	invoice portService.InvoiceService,
+	taxRepo portRepository.TaxRateRepository,
+	userRepo portRepository.UserRepository,
+	customerRepo portRepository.CustomerRepository,
+	profileRepo portRepository.BusinessProfileRepository,
+	numberingRepo portRepository.NumberingRepository,
+	blobs portRepository.BlobStore,
+	documents portRepository.DocumentStore,
+	tx portRepository.TxRepository,
+) portService.QuoteService {
+	return &quoteService{
+		cfg:           cfg,
+		repo:          repo,
+		invoice:       invoice,
+		taxRepo:       taxRepo,
+		userRepo:      userRepo,
+		customerRepo:  customerRepo,
+		profileRepo:   profileRepo,
+		numberingRepo: numberingRepo,
+		blobs:         blobs,
+		documents:     documents,
+		tx:            tx,
+	}
+}
+
+func (s *quoteService) Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
+	res, err := s.repo.Get(ctx, req)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get quotes", zap.Error(err))
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *quoteService) GetByID(ctx context.Context, id, userID uint) (*domain.QuoteResponse, error) {
+	quote, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetItems(ctx, quote.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get quote items", zap.Error(err), zap.Uint("quote_id", id))
+		return nil, err
+	}
+
+	res := quote.Response(items, domain.CalendarDate(time.Now()))
+	return &res, nil
+}
+
+// Create adds a draft quote, its number is taken from the quote numbering scheme right away
+func (s *quoteService) Create(ctx context.Context, req *domain.QuoteRequest) (*domain.QuoteResponse, error) {
+	issueDate, err := parseQuoteDates(req)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	t := time.Now()
+	data := domain.Quote{
+		AuthorID:  req.UserID,
+		Status:    domain.QuoteStatusDraft,
+		Timestamp: domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+	lines, err := s.apply(ctx, req, &data, t)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Number, err = nextNumber(ctx, s.numberingRepo, tx, req.UserID, domain.DocumentQuote, issueDate)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := s.repo.NumberExists(ctx, tx, req.UserID, data.Number)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to check quote number", zap.Error(err))
+		return nil, err
+	}
+	if exists {
+		logger.StdContextWarn(ctx, "quote number already used", zap.String("number", data.Number))
+		return nil, fmt.Errorf(domain.ErrQuoteNumberExists)
+	}
+
+	if err = s.repo.Create(ctx, tx, &data); err != nil {
+		logger.StdContextError(ctx, "failed to create quote", zap.Error(err))
+		return nil, err
+	}
+
+	items := quoteItems(data.ID, lines)
+	if err = s.repo.CreateItems(ctx, tx, items); err != nil {
+		logger.StdContextError(ctx, "failed to create quote items", zap.Error(err))
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "quote created successfully", zap.Uint("quote_id", data.ID), zap.String("number", data.Number))
+	res := data.Response(items, domain.CalendarDate(t))
+	return &res, nil
+}
+
+// Update replaces a draft or sent quote, the number is kept
+func (s *quoteService) Update(ctx context.Context, req *domain.QuoteRequest) error {
+	if _, err := parseQuoteDates(req); err != nil {
+		return err
+	}
+
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	quote, err := s.repo.LockByID(ctx, tx, req.UserID, req.ID)
+	if err != nil {
+		return err
+	}
+	if !quote.Status.IsEditable() {
+		logger.StdContextWarn(ctx, "attempt to edit locked quote", zap.Uint("quote_id", req.ID), zap.String("status", string(quote.Status)))
+		return fmt.Errorf(domain.ErrQuoteLocked)
+	}
+
+	t := time.Now()
+	quote.UpdatedAt = t
+	lines, err := s.apply(ctx, req, quote, t)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.Update(ctx, tx, quote); err != nil {
+		logger.StdContextError(ctx, "failed to update quote", zap.Error(err), zap.Uint("quote_id", req.ID))
+		return err
+	}
+
+	if err = s.repo.DeleteItems(ctx, tx, quote.ID); err != nil {
+		logger.StdContextError(ctx, "failed to delete quote items", zap.Error(err), zap.Uint("quote_id", req.ID))
+		return err
+	}
+
+	if err = s.repo.CreateItems(ctx, tx, quoteItems(quote.ID, lines)); err != nil {
+		logger.StdContextError(ctx, "failed to create quote items", zap.Error(err), zap.Uint("quote_id", req.ID))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "quote updated successfully", zap.Uint("quote_id", req.ID))
+	return nil
+}
+
+// Delete removes a quote, a quote with invoiced lines is kept as the origin of those invoices
+func (s *quoteService) Delete(ctx context.Context, id, userID uint) error {
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	quote, err := s.repo.LockByID(ctx, tx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	items, err := s.repo.GetItems(ctx, quote.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get quote items", zap.Error(err), zap.Uint("quote_id", id))
+		return err
+	}
+	if domain.Converted(items) {
+		return fmt.Errorf(domain.ErrQuoteNotDeletable)
+	}
+
+	t := time.Now()
+	quote.DeletedAt = &t
+	quote.UpdatedAt = t
+
+	if err = s.repo.SoftDelete(ctx, tx, quote); err != nil {
+		logger.StdContextError(ctx, "failed to delete quote", zap.Error(err), zap.Uint("quote_id", id))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	removeDocuments(ctx, s.documents, domain.DocumentQuote, strconv.FormatUint(uint64(quote.ID), 10))
+	logger.StdContextInfo(ctx, "quote deleted successfully", zap.Uint("quote_id", id))
+	return nil
+}
+
+// Send marks a draft quote as sent to the customer
+func (s *quoteService) Send(ctx context.Context, id, userID uint) error {
+	return s.transition(ctx, id, userID, domain.QuoteStatusSent)
+}
+
+// Accept records that the customer accepted a sent quote before it expired
+func (s *quoteService) Accept(ctx context.Context, id, userID uint) error {
+	return s.transition(ctx, id, userID, domain.QuoteStatusAccepted)
+}
+
+// Decline records that the customer declined a sent quote
+func (s *quoteService) Decline(ctx context.Context, id, userID uint) error {
+	return s.transition(ctx, id, userID, domain.QuoteStatusDeclined)
+}
+
+func (s *quoteService) transition(ctx context.Context, id, userID uint, next domain.QuoteStatus) error {
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	quote, err := s.repo.LockByID(ctx, tx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	from := quote.Status
+	if !from.CanTransitionTo(next) {
+		logger.StdContextWarn(ctx, "invalid quote status transition",
+			zap.Uint("quote_id", id),
+			zap.String("from", string(from)),
+			zap.String("to", string(next)),
+		)
+		return fmt.Errorf(domain.ErrInvalidQuoteTransition)
+	}
+
+	// An expired quote can still be declined, but no longer sent or accepted
+	t := time.Now()
+	if next != domain.QuoteStatusDeclined && quote.Expired(domain.CalendarDate(t)) {
+		return fmt.Errorf(domain.ErrQuoteExpired)
+	}
+
+	quote.Status = next
+	quote.UpdatedAt = t
+	if err = s.repo.UpdateStatus(ctx, tx, quote); err != nil {
+		logger.StdContextError(ctx, "failed to update quote status", zap.Error(err), zap.Uint("quote_id", id))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "quote status changed",
+		zap.Uint("quote_id", id),
+		zap.String("from", string(from)),
+		zap.String("to", string(next)),
+	)
+	return nil
+}
+
+// Convert invoices lines of an accepted quote through the invoice service, all lines not invoiced yet
+// unless some are selected. The quote becomes converted once every line is invoiced.
+func (s *quoteService) Convert(ctx context.Context, req *domain.QuoteConvertRequest) (*domain.InvoiceCreateResponse, error) {
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// The lock keeps concurrent conversions from invoicing the same lines twice
+	quote, err := s.repo.LockByID(ctx, tx, req.UserID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status != domain.QuoteStatusAccepted {
+		return nil, fmt.Errorf(domain.ErrQuoteNotAccepted)
+	}
+
+	items, err := s.repo.GetItems(ctx, quote.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get quote items", zap.Error(err), zap.Uint("quote_id", req.ID))
+		return nil, err
+	}
+
+	selected, ok := domain.SelectLines(items, req.ItemIDs)
+	if !ok {
+		return nil, fmt.Errorf(domain.ErrInvalidQuoteItem)
+	}
+
+	t := time.Now()
+	issueDate := req.IssueDate
+	if issueDate == "" {
+		issueDate = t.Format(time.DateOnly)
+	}
+	invoiceReq := quote.InvoiceRequest(items, selected, issueDate, req.DueDate)
+
+	// The invoice is created in the transaction of the quote, it exists only once its lines are marked converted
+	invoiceID, err := s.invoice.CreateTx(ctx, tx, &invoiceReq)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(selected))
+	for i, v := range selected {
+		ids[i] = v.ID
+	}
+	if err = s.repo.MarkConverted(ctx, tx, quote.ID, ids, invoiceID); err != nil {
+		logger.StdContextError(ctx, "failed to mark quote items converted", zap.Error(err), zap.Uint("quote_id", req.ID))
+		return nil, err
+	}
+
+	if len(selected) == len(items)-convertedCount(items) {
+		quote.Status = domain.QuoteStatusConverted
+		quote.UpdatedAt = t
+		if err = s.repo.UpdateStatus(ctx, tx, quote); err != nil {
+			logger.StdContextError(ctx, "failed to update quote status", zap.Error(err), zap.Uint("quote_id", req.ID))
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "quote converted into invoice",
+		zap.Uint("quote_id", req.ID),
+		zap.String("invoice_id", invoiceID),
+		zap.Int("items", len(selected)),
+	)
+	return &domain.InvoiceCreateResponse{ID: invoiceID}, nil
+}
+
+func (s *quoteService) GetPDF(ctx context.Context, id, userID uint) ([]byte, error) {
+	quote, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := loadPDFSettings(ctx, s.userRepo, quote.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetItems(ctx, quote.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get quote items", zap.Error(err), zap.Uint("quote_id", quote.ID))
+		return nil, err
+	}
+
+	issuer, err := loadPDFIssuer(ctx, s.profileRepo, s.blobs, quote.AuthorID, quote.ProfileID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cached PDF is named after everything printed on it, a changed quote is never served stale
+	key, err := domain.DocumentKey(domain.DocumentQuote, strconv.FormatUint(uint64(quote.ID), 10), pdfLayoutVersion, settings, quote, items, issuer.content())
+	if err != nil {
+		logger.StdContextError(ctx, "failed to build pdf key", zap.Error(err), zap.Uint("quote_id", quote.ID))
+		return nil, err
+	}
+	if pdfBytes, ok := cachedPDF(ctx, s.documents, key); ok {
+		return pdfBytes, nil
+	}
+
+	m := s.generatePDF(quote, items, issuer, settings)
+	doc, err := m.Generate()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to generate pdf", zap.Error(err), zap.Uint("quote_id", quote.ID))
+		return nil, err
+	}
+
+	pdfBytes := doc.GetBytes()
+	storePDF(ctx, s.documents, key, pdfBytes)
+
+	logger.StdContextInfo(ctx, "pdf generated successfully", zap.Uint("quote_id", quote.ID), zap.Int("size_bytes", len(pdfBytes)))
+	return pdfBytes, nil
+}
+
+// apply prices the requested lines and copies the request onto the quote
+func (s *quoteService) apply(ctx context.Context, req *domain.QuoteRequest, data *domain.Quote, t time.Time) ([]domain.InvoiceItem, error) {
+	invoiceReq := req.InvoiceRequest()
+	lines, totals, err := priceItems(ctx, s.taxRepo, 0, &invoiceReq, t)
+	if err != nil {
+		return nil, err
+	}
+
+	data.IssueDate = req.IssueDate
+	data.ValidUntil = req.ValidUntil
+	data.Note = req.Note
+	data.TaxMode = taxMode(req.TaxMode)
+	data.TaxRateID = req.TaxRateID
+	data.DiscountType = req.DiscountType
+	data.Discount = req.Discount
+	data.SetTotals(totals)
+
+	if err = s.applyProfile(ctx, req, data); err != nil {
+		return nil, err
+	}
+
+	if req.CustomerID == nil {
+		// A free text customer has no details to snapshot
+		data.SnapshotCustomer(&domain.Customer{Name: req.Customer})
+		data.CustomerID = nil
+	} else {
+		customer, err := s.customerRepo.GetByID(ctx, req.UserID, *req.CustomerID)
+		if err != nil {
+			logger.StdContextWarn(ctx, "unknown quote customer", zap.Error(err), zap.Uint("customer_id", *req.CustomerID))
+			return nil, err
+		}
+		data.SnapshotCustomer(customer)
+	}
+
+	code := req.Currency
+	if code == "" {
+		user, err := s.userRepo.FindByID(ctx, req.UserID)
+		if err != nil {
+			logger.StdContextError(ctx, "failed to get user", zap.Error(err), zap.Uint("user_id", req.UserID))
+			return nil, err
+		}
+		code = user.BaseCurrency
+	}
+	currency, ok := domain.LookupCurrency(code)
+	if !ok {
+		return nil, fmt.Errorf(domain.ErrInvalidCurrency)
+	}
+	data.Currency = currency.Code
+
+	return lines, nil
+}
+
+// applyProfile links the requested business profile, or the default one of the user, to the quote.
+// The profile name fills in an empty issuer.
+func (s *quoteService) applyProfile(ctx context.Context, req *domain.QuoteRequest, data *domain.Quote) error {
+	var profile *domain.BusinessProfile
+	var err error
+	if req.ProfileID != nil {
+		profile, err = s.profileRepo.GetByID(ctx, req.UserID, *req.ProfileID)
+	} else {
+		profile, err = s.profileRepo.GetDefault(ctx, req.UserID)
+	}
+	if err != nil {
+		logger.StdContextWarn(ctx, "failed to get quote business profile", zap.Error(err))
+		return err
+	}
+
+	data.ProfileID = nil
+	data.Issuer = req.Issuer
+	if profile != nil {
+		data.ProfileID = &profile.ID
+		if data.Issuer == "" {
+			data.Issuer = profile.Name
+		}
+	}
+	if data.Issuer == "" {
+		return fmt.Errorf(domain.ErrIssuerRequired)
+	}
+	return nil
+}
+
+func (s *quoteService) generatePDF(quote *domain.Quote, items []domain.QuoteItem, issuer *pdfIssuer, settings domain.PDFSettings) core.Maroto {
+	d := newPDFDocument(settings, s.cfg.Env == "development")
+
+	d.header(issuer, d.labels.Quotation, quote.Number, d.dateLine(d.labels.IssueDate, quote.IssueDate))
+	d.line(d.dateLine(d.labels.ValidUntil, quote.ValidUntil), fontstyle.Bold)
+	d.space()
+
+	d.parties(issuer, quote.Issuer, pdfParty{
+		Name:    quote.Customer,
+		Company: quote.CustomerCompany,
+		Address: quote.CustomerAddress,
+		Email:   quote.CustomerEmail,
+		Phone:   quote.CustomerPhone,
+		TaxID:   quote.CustomerTaxID,
+	})
+
+	d.space()
+
+	lines, totals := quote.Lines(items)
+	itemResponses := make([]domain.InvoiceItemResponse, len(lines))
+	for i := range lines {
+		itemResponses[i] = lines[i].Response()
+	}
+	taxes := make([]domain.InvoiceTaxResponse, len(totals.Taxes))
+	for i := range totals.Taxes {
+		taxes[i] = totals.Taxes[i].Response()
+	}
+
+	d.items(invoicePDFItems(itemResponses), quote.Currency)
+	d.totals(pdfTotals{
+		Subtotal:       quote.Subtotal,
+		DiscountType:   quote.DiscountType,
+		Discount:       quote.Discount,
+		DiscountAmount: quote.DiscountAmount,
+		Taxes:          taxes,
+		TaxMode:        quote.TaxMode,
+		Total:          quote.Total,
+		Currency:       quote.Currency,
+	})
+
+	if quote.Note != "" {
+		d.space()
+		d.line(quote.Note, fontstyle.Italic)
+	}
+
+	d.footer(issuer, false)
+	return d.m
+}
+
+// parseQuoteDates checks that a quote is valid on the day it is issued at least
+func parseQuoteDates(req *domain.QuoteRequest) (time.Time, error) {
+	issueDate, err := time.ParseInLocation(time.DateOnly, req.IssueDate, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if req.ValidUntil < req.IssueDate {
+		return time.Time{}, fmt.Errorf(domain.ErrInvalidQuoteValidity)
+	}
+	return issueDate, nil
+}
+
+// quoteItems keeps the priced lines with their tax snapshot, numbered per quote
+func quoteItems(quoteID uint, lines []domain.InvoiceItem) []domain.QuoteItem {
+	items := make([]domain.QuoteItem, len(lines))
+	for i, v := range lines {
+		items[i] = domain.QuoteItem{
+			ID:           uint(i + 1),
+			QuoteID:      quoteID,
+			Description:  v.Description,
+			Qty:          v.Qty,
+			Price:        v.Price,
+			DiscountType: v.DiscountType,
+			Discount:     v.Discount,
+			TaxRateID:    v.TaxRateID,
+			TaxName:      v.TaxName,
+			TaxRate:      v.TaxRate,
+			Timestamp:    v.Timestamp,
+		}
+	}
+	return items
+}
+
+// convertedCount returns how many lines of a quote were invoiced already
+func convertedCount(items []domain.QuoteItem) int {
+	var n int
+	for _, v := range items {
+		if v.InvoiceID != "" {
+			n++
+		}
+	}
+	return n
+}
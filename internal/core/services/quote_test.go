@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+)
+
+// convertQuoteRepository holds one accepted quote with two lines, markErr fails MarkConverted
+type convertQuoteRepository struct {
+	portRepository.QuoteRepository
+	markErr  error
+	markTx   portRepository.Transaction
+	marked   []uint
+	statusTx portRepository.Transaction
+	status   domain.QuoteStatus
+}
+
+func (r *convertQuoteRepository) LockByID(ctx context.Context, tx portRepository.Transaction, authorID, id uint) (*domain.Quote, error) {
+	return &domain.Quote{ID: id, AuthorID: authorID, Status: domain.QuoteStatusAccepted}, nil
+}
+
+func (r *convertQuoteRepository) GetItems(ctx context.Context, quoteID uint) ([]domain.QuoteItem, error) {
+	return []domain.QuoteItem{
+		{ID: 1, QuoteID: quoteID, Description: "Design", Qty: 1, Price: 150000},
+		{ID: 2, QuoteID: quoteID, Description: "Hosting", Qty: 2, Price: 50000},
+	}, nil
+}
+
+func (r *convertQuoteRepository) MarkConverted(ctx context.Context, tx portRepository.Transaction, quoteID uint, itemIDs []uint, invoiceID string) error {
+	r.markTx = tx
+	if r.markErr != nil {
+		return r.markErr
+	}
+	r.marked = itemIDs
+	return nil
+}
+
+func (r *convertQuoteRepository) UpdateStatus(ctx context.Context, tx portRepository.Transaction, data *domain.Quote) error {
+	r.statusTx = tx
+	r.status = data.Status
+	return nil
+}
+
+// convertInvoiceService records the transaction the invoice was created in
+type convertInvoiceService struct {
+	portService.InvoiceService
+	tx portRepository.Transaction
+}
+
+func (s *convertInvoiceService) CreateTx(ctx context.Context, tx portRepository.Transaction, req *domain.InvoiceRequest) (string, error) {
+	s.tx = tx
+	return "inv-1", nil
+}
+
+func TestQuoteConvertCreatesInvoiceInTransaction(t *testing.T) {
+	repo := &convertQuoteRepository{}
+	invoices := &convertInvoiceService{}
+	txs := &fakeTxRepository{}
+	s := NewQuoteService(nil, repo, invoices, nil, nil, nil, nil, nil, nil, nil, txs)
+
+	res, err := s.Convert(context.Background(), &domain.QuoteConvertRequest{ID: 4, UserID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tx := txs.only(t)
+	if !tx.committed {
+		t.Errorf("expected the conversion to be committed")
+	}
+	if invoices.tx != tx || repo.markTx != tx || repo.statusTx != tx {
+		t.Errorf("expected the invoice and the quote to be written in the same transaction")
+	}
+	if res.ID != "inv-1" || len(repo.marked) != 2 || repo.status != domain.QuoteStatusConverted {
+		t.Errorf("expected both lines converted into inv-1, got %q %v %q", res.ID, repo.marked, repo.status)
+	}
+}
+
+func TestQuoteConvertRollsBackInvoice(t *testing.T) {
+	repo := &convertQuoteRepository{markErr: errors.New("database down")}
+	invoices := &convertInvoiceService{}
+	txs := &fakeTxRepository{}
+	s := NewQuoteService(nil, repo, invoices, nil, nil, nil, nil, nil, nil, nil, txs)
+
+	if _, err := s.Convert(context.Background(), &domain.QuoteConvertRequest{ID: 4, UserID: 1}); err == nil {
+		t.Fatalf("expected the conversion to fail")
+	}
+
+	tx := txs.only(t)
+	if tx.committed || !tx.rolledBack {
+		t.Errorf("expected the invoice to be rolled back with the quote")
+	}
+	if invoices.tx != tx {
+		t.Errorf("expected the invoice to be created in the quote transaction")
+	}
+}
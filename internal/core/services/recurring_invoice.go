@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+// recurringBatchSize limits how many due schedules one scheduler pass picks up
+const recurringBatchSize = 100
+
+// defaultPreviewRuns is the number of upcoming runs previewed when no count is requested
+const defaultPreviewRuns = 12
+
+type recurringInvoiceService struct {
+	repo    portRepository.RecurringInvoiceRepository
+	invoice portService.InvoiceService
+	tx      portRepository.TxRepository
+}
+
+func NewRecurringInvoiceService(
+	repo portRepository.RecurringInvoiceRepository,
+	invoice portService.InvoiceService,
+	tx portRepository.TxRepository,
+) portService.RecurringInvoiceService {
+	return &recurringInvoiceService{
+		repo:    repo,
+		invoice: invoice,
+		tx:      tx,
+	}
+}
+
+func (s *recurringInvoiceService) Get(ctx context.Context, req *domain.PaginationRequest) (*domain.PaginationResponse, error) {
+	res, err := s.repo.Get(ctx, req)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get recurring invoices", zap.Error(err))
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *recurringInvoiceService) GetByID(ctx context.Context, id, userID uint) (*domain.RecurringInvoiceResponse, error) {
+	recurring, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.GetItems(ctx, recurring.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get recurring invoice items", zap.Error(err), zap.Uint("recurring_id", id))
+		return nil, err
+	}
+
+	res := recurring.Response(items)
+	return &res, nil
+}
+
+// Create adds a recurring invoice, its first run is the first one on or after both the start date and today
+func (s *recurringInvoiceService) Create(ctx context.Context, req *domain.RecurringInvoiceRequest) (*domain.RecurringInvoiceResponse, error) {
+	if err := validateRecurring(req); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	t := time.Now()
+	data := domain.RecurringInvoice{
+		AuthorID:  req.UserID,
+		Status:    domain.RecurringStatusActive,
+		Timestamp: domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+	applyRecurring(req, &data)
+	data.Reschedule(domain.CalendarDate(t))
+
+	if err = s.repo.Create(ctx, tx, &data); err != nil {
+		logger.StdContextError(ctx, "failed to create recurring invoice", zap.Error(err))
+		return nil, err
+	}
+
+	items := recurringItems(data.ID, req.Items, t)
+	if err = s.repo.CreateItems(ctx, tx, items); err != nil {
+		logger.StdContextError(ctx, "failed to create recurring invoice items", zap.Error(err))
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "recurring invoice created successfully", zap.Uint("recurring_id", data.ID), zap.String("next_run_date", data.NextRunDate))
+	res := data.Response(items)
+	return &res, nil
+}
+
+// Update changes a recurring invoice, invoices already created are left untouched
+func (s *recurringInvoiceService) Update(ctx context.Context, req *domain.RecurringInvoiceRequest) error {
+	if err := validateRecurring(req); err != nil {
+		return err
+	}
+
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	// Locked like a scheduler run, so neither overwrites the schedule the other stored
+	recurring, err := s.repo.LockByID(ctx, tx, req.UserID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	t := time.Now()
+	applyRecurring(req, recurring)
+	recurring.UpdatedAt = t
+	recurring.Reschedule(domain.CalendarDate(t))
+
+	if err = s.repo.Update(ctx, tx, recurring); err != nil {
+		logger.StdContextError(ctx, "failed to update recurring invoice", zap.Error(err), zap.Uint("recurring_id", req.ID))
+		return err
+	}
+
+	if err = s.repo.DeleteItems(ctx, tx, recurring.ID); err != nil {
+		logger.StdContextError(ctx, "failed to delete recurring invoice items", zap.Error(err), zap.Uint("recurring_id", req.ID))
+		return err
+	}
+
+	if err = s.repo.CreateItems(ctx, tx, recurringItems(recurring.ID, req.Items, t)); err != nil {
+		logger.StdContextError(ctx, "failed to create recurring invoice items", zap.Error(err), zap.Uint("recurring_id", req.ID))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "recurring invoice updated successfully", zap.Uint("recurring_id", req.ID), zap.String("next_run_date", recurring.NextRunDate))
+	return nil
+}
+
+func (s *recurringInvoiceService) Delete(ctx context.Context, id, userID uint) error {
+	recurring, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	t := time.Now()
+	recurring.DeletedAt = &t
+	recurring.UpdatedAt = t
+
+	if err = s.repo.SoftDelete(ctx, recurring); err != nil {
+		logger.StdContextError(ctx, "failed to delete recurring invoice", zap.Error(err), zap.Uint("recurring_id", id))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "recurring invoice deleted successfully", zap.Uint("recurring_id", id))
+	return nil
+}
+
+// Pause stops an active schedule from creating invoices
+func (s *recurringInvoiceService) Pause(ctx context.Context, id, userID uint) error {
+	return s.setStatus(ctx, id, userID, domain.RecurringStatusActive, domain.RecurringStatusPaused)
+}
+
+// Resume restarts a paused schedule, runs missed while paused are skipped
+func (s *recurringInvoiceService) Resume(ctx context.Context, id, userID uint) error {
+	return s.setStatus(ctx, id, userID, domain.RecurringStatusPaused, domain.RecurringStatusActive)
+}
+
+func (s *recurringInvoiceService) setStatus(ctx context.Context, id, userID uint, from, to domain.RecurringStatus) error {
+	tx, err := s.tx.Begin()
+	if err != nil {
+		logger.StdContextError(ctx, "failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	recurring, err := s.repo.LockByID(ctx, tx, userID, id)
+	if err != nil {
+		return err
+	}
+	if recurring.Status != from {
+		return fmt.Errorf(domain.ErrInvalidRecurringStatus)
+	}
+
+	t := time.Now()
+	recurring.Status = to
+	recurring.UpdatedAt = t
+	if to == domain.RecurringStatusActive {
+		recurring.Reschedule(domain.CalendarDate(t))
+	}
+
+	if err = s.repo.UpdateSchedule(ctx, tx, recurring); err != nil {
+		logger.StdContextError(ctx, "failed to update recurring invoice status", zap.Error(err), zap.Uint("recurring_id", id))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		logger.StdContextError(ctx, "failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "recurring invoice status changed",
+		zap.Uint("recurring_id", id),
+		zap.String("from", string(from)),
+		zap.String("to", string(recurring.Status)),
+	)
+	return nil
+}
+
+// Preview lists the dates of the next runs, for a paused schedule as if it was resumed today
+func (s *recurringInvoiceService) Preview(ctx context.Context, id, userID uint, count int) ([]string, error) {
+	recurring, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if count <= 0 {
+		count = defaultPreviewRuns
+	}
+	if recurring.Status == domain.RecurringStatusPaused {
+		recurring.Reschedule(domain.CalendarDate(time.Now()))
+	}
+	return recurring.Preview(count), nil
+}
+
+// RunDue creates the invoices of every schedule due on the date of now and returns how many were created.
+// Schedules are locked while they run, so several replicas can run the scheduler side by side.
+func (s *recurringInvoiceService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	today := domain.CalendarDate(now)
+	ids, err := s.repo.GetDueIDs(ctx, today.Format(time.DateOnly), recurringBatchSize)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get due recurring invoices", zap.Error(err))
+		return 0, err
+	}
+
+	var created int
+	for _, id := range ids {
+		n, err := s.run(ctx, id, today)
+		created += n
+		if err != nil {
+			logger.StdContextError(ctx, "failed to run recurring invoice", zap.Error(err), zap.Uint("recurring_id", id))
+		}
+	}
+
+	if created > 0 {
+		logger.StdContextInfo(ctx, "recurring invoices created", zap.Int("count", created))
+	}
+	return created, nil
+}
+
+// run invoices the due runs of one schedule and stores how far it got.
+// A run whose invoice already exists is skipped, which makes retries after a crash safe.
+func (s *recurringInvoiceService) run(ctx context.Context, id uint, today time.Time) (int, error) {
+	tx, err := s.tx.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	recurring, err := s.repo.LockDue(ctx, tx, id, today.Format(time.DateOnly))
+	if err != nil || recurring == nil {
+		return 0, err
+	}
+
+	items, err := s.repo.GetItems(ctx, recurring.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	var created int
+	var runErr error
+	for i := 0; i < domain.MaxRecurringCatchUp && recurring.IsDue(today); i++ {
+		run, _ := time.Parse(time.DateOnly, recurring.NextRunDate)
+		req := recurring.InvoiceRequest(items, run)
+
+		invoiceID, err := s.invoice.Create(ctx, &req)
+		if err != nil && err.Error() != domain.ErrRecurringRunExists {
+			// The run stays due and is retried on the next pass
+			runErr = err
+			break
+		}
+		if err == nil {
+			created++
+			s.send(ctx, recurring, invoiceID)
+		}
+		recurring.Advance(run)
+	}
+
+	recurring.UpdatedAt = time.Now()
+	if err = s.repo.UpdateSchedule(ctx, tx, recurring); err != nil {
+		return created, err
+	}
+	if err = tx.Commit(); err != nil {
+		return created, err
+	}
+	return created, runErr
+}
+
+// send issues a created invoice when the schedule sends automatically, a failure leaves it as draft
+func (s *recurringInvoiceService) send(ctx context.Context, recurring *domain.RecurringInvoice, invoiceID string) {
+	if !recurring.AutoSend {
+		return
+	}
+	if err := s.invoice.Send(ctx, invoiceID, recurring.AuthorID); err != nil {
+		logger.StdContextWarn(ctx, "failed to send recurring invoice", zap.Error(err), zap.Uint("recurring_id", recurring.ID), zap.String("invoice_id", invoiceID))
+	}
+}
+
+func validateRecurring(req *domain.RecurringInvoiceRequest) error {
+	if req.EndDate != "" && req.EndDate < req.StartDate {
+		return fmt.Errorf(domain.ErrInvalidRecurringSchedule)
+	}
+	if req.Currency != "" {
+		if _, ok := domain.LookupCurrency(req.Currency); !ok {
+			return fmt.Errorf(domain.ErrInvalidCurrency)
+		}
+	}
+	return nil
+}
+
+// applyRecurring copies the requested template onto a recurring invoice
+func applyRecurring(req *domain.RecurringInvoiceRequest, data *domain.RecurringInvoice) {
+	data.Name = req.Name
+	data.ProfileID = req.ProfileID
+	data.Issuer = req.Issuer
+	data.CustomerID = req.CustomerID
+	data.Customer = req.Customer
+	data.Note = req.Note
+	data.Currency = req.Currency
+	data.TaxMode = req.TaxMode
+	data.TaxRateID = req.TaxRateID
+	data.DiscountType = req.DiscountType
+	data.Discount = req.Discount
+	data.Interval = req.Interval
+	data.DayOfMonth = req.DayOfMonth
+	data.StartDate = req.StartDate
+	data.EndDate = req.EndDate
+	data.DueDays = req.DueDays
+	data.AutoSend = req.AutoSend
+}
+
+func recurringItems(recurringID uint, req []domain.InvoiceItemRequest, t time.Time) []domain.RecurringInvoiceItem {
+	items := make([]domain.RecurringInvoiceItem, len(req))
+	for i, v := range req {
+		items[i] = domain.RecurringInvoiceItem{
+			RecurringID:  recurringID,
+			Description:  v.Description,
+			Qty:          v.Qty,
+			Price:        v.Price,
+			DiscountType: v.DiscountType,
+			Discount:     v.Discount,
+			TaxRateID:    v.TaxRateID,
+			Timestamp:    domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+		}
+	}
+	return items
+}
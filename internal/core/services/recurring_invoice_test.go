@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+)
+
+// recurringRepository locks one schedule and records the transactions of its calls
+type recurringRepository struct {
+	portRepository.RecurringInvoiceRepository
+	recurring *domain.RecurringInvoice
+	lockedTx  portRepository.Transaction
+	updateTx  portRepository.Transaction
+	updated   *domain.RecurringInvoice
+}
+
+func (r *recurringRepository) LockByID(ctx context.Context, tx portRepository.Transaction, authorID, id uint) (*domain.RecurringInvoice, error) {
+	r.lockedTx = tx
+	recurring := *r.recurring
+	return &recurring, nil
+}
+
+func (r *recurringRepository) UpdateSchedule(ctx context.Context, tx portRepository.Transaction, data *domain.RecurringInvoice) error {
+	r.updateTx = tx
+	r.updated = data
+	return nil
+}
+
+func TestRecurringInvoicePauseLocksSchedule(t *testing.T) {
+	repo := &recurringRepository{recurring: &domain.RecurringInvoice{ID: 3, AuthorID: 1, Status: domain.RecurringStatusActive, NextRunDate: "2026-11-01"}}
+	txs := &fakeTxRepository{}
+	s := NewRecurringInvoiceService(repo, nil, txs)
+
+	if err := s.Pause(context.Background(), 3, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tx := txs.only(t)
+	if !tx.committed {
+		t.Errorf("expected the status change to be committed")
+	}
+	if repo.lockedTx != tx || repo.updateTx != tx {
+		t.Errorf("expected the schedule to be read locked and written in the same transaction")
+	}
+	if repo.updated == nil || repo.updated.Status != domain.RecurringStatusPaused || repo.updated.NextRunDate != "2026-11-01" {
+		t.Errorf("expected the locked schedule to be paused, got %+v", repo.updated)
+	}
+}
+
+func TestRecurringInvoicePauseRefusesStatus(t *testing.T) {
+	repo := &recurringRepository{recurring: &domain.RecurringInvoice{ID: 3, AuthorID: 1, Status: domain.RecurringStatusEnded}}
+	txs := &fakeTxRepository{}
+	s := NewRecurringInvoiceService(repo, nil, txs)
+
+	err := s.Pause(context.Background(), 3, 1)
+	if err == nil || err.Error() != domain.ErrInvalidRecurringStatus {
+		t.Fatalf("expected %q, got %v", domain.ErrInvalidRecurringStatus, err)
+	}
+	if tx := txs.only(t); tx.committed || repo.updated != nil {
+		t.Errorf("expected nothing written for an ended schedule")
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/config"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+// reminderBatchSize limits how many invoices one pass reminds per offset
+const reminderBatchSize = 100
+
+type reminderService struct {
+	offsets     []int
+	repo        portRepository.ReminderRepository
+	invoiceRepo portRepository.InvoiceRepository
+	notifier    portRepository.Notifier
+}
+
+func NewReminderService(
+	cfg *config.ReminderConfig,
+	repo portRepository.ReminderRepository,
+	invoiceRepo portRepository.InvoiceRepository,
+	notifier portRepository.Notifier,
+) portService.ReminderService {
+	return &reminderService{
+		offsets:     cfg.Offsets,
+		repo:        repo,
+		invoiceRepo: invoiceRepo,
+		notifier:    notifier,
+	}
+}
+
+// Get lists the reminders of an invoice
+func (s *reminderService) Get(ctx context.Context, invoiceID string, userID uint) ([]domain.InvoiceReminderResponse, error) {
+	inv, err := s.invoiceRepo.GetByPublicID(ctx, userID, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminders, err := s.repo.GetByInvoiceID(ctx, userID, inv.ID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get invoice reminders", zap.Error(err), zap.String("invoice_id", invoiceID))
+		return nil, err
+	}
+
+	res := make([]domain.InvoiceReminderResponse, len(reminders))
+	for i, v := range reminders {
+		res[i] = v.Response()
+	}
+	return res, nil
+}
+
+// RunDue moves sent and partially paid invoices past their due date to overdue, then sends the reminders due today.
+// Reminders are claimed before they are sent, so several replicas can run it side by side.
+func (s *reminderService) RunDue(ctx context.Context, now time.Time) (int, error) {
+	marked, err := s.invoiceRepo.MarkOverdue(ctx, now)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to mark overdue invoices", zap.Error(err))
+		return 0, err
+	}
+	if marked > 0 {
+		logger.StdContextInfo(ctx, "invoices marked overdue", zap.Int64("count", marked))
+	}
+
+	today := domain.CalendarDate(now)
+	var sent int
+	for i, offset := range s.offsets {
+		from, to := domain.ReminderDueRange(s.offsets, i, today)
+		invoices, err := s.repo.GetDue(ctx, offset, from, to, reminderBatchSize)
+		if err != nil {
+			logger.StdContextError(ctx, "failed to get invoices due for a reminder", zap.Error(err), zap.Int("offset_days", offset))
+			return sent, err
+		}
+
+		for j := range invoices {
+			sent += s.remind(ctx, &invoices[j], offset, today)
+		}
+	}
+
+	if sent > 0 {
+		logger.StdContextInfo(ctx, "invoice reminders sent", zap.Int("count", sent))
+	}
+	return sent, nil
+}
+
+// remind sends the reminder of an invoice on every channel its customer can be reached on
+// and returns how many messages went out. Failed sends are recorded and not retried.
+func (s *reminderService) remind(ctx context.Context, inv *domain.Invoice, offset int, today time.Time) int {
+	notifications := domain.ReminderNotifications(inv, today)
+	if len(notifications) == 0 {
+		// Recorded anyway, so the invoice is not picked up again for this offset
+		s.claim(ctx, inv, offset, &domain.Notification{}, domain.ReminderStatusSkipped, "customer has no email or phone")
+		return 0
+	}
+
+	var sent int
+	for i := range notifications {
+		n := &notifications[i]
+		if !s.notifier.Supports(n.Channel) {
+			s.claim(ctx, inv, offset, n, domain.ReminderStatusSkipped, "channel not configured")
+			continue
+		}
+
+		reminder := s.claim(ctx, inv, offset, n, domain.ReminderStatusPending, "")
+		if reminder == nil {
+			continue
+		}
+
+		t := time.Now()
+		reminder.UpdatedAt = t
+		if err := s.notifier.Send(ctx, n); err != nil {
+			logger.StdContextWarn(ctx, "failed to send invoice reminder", zap.Error(err), zap.Uint("reminder_id", reminder.ID), zap.String("channel", string(n.Channel)))
+			reminder.Status = domain.ReminderStatusFailed
+			reminder.Error = err.Error()
+		} else {
+			reminder.Status = domain.ReminderStatusSent
+			reminder.SentAt = &t
+			sent++
+		}
+
+		if err := s.repo.Finish(ctx, reminder); err != nil {
+			logger.StdContextError(ctx, "failed to record invoice reminder", zap.Error(err), zap.Uint("reminder_id", reminder.ID))
+		}
+	}
+	return sent
+}
+
+// claim stores a reminder and returns it, or nil when another run already claimed it or storing failed
+func (s *reminderService) claim(ctx context.Context, inv *domain.Invoice, offset int, n *domain.Notification, status domain.ReminderStatus, reason string) *domain.InvoiceReminder {
+	t := time.Now()
+	reminder := domain.InvoiceReminder{
+		AuthorID:   inv.AuthorID,
+		InvoiceID:  inv.ID,
+		OffsetDays: offset,
+		Channel:    n.Channel,
+		Recipient:  n.Recipient,
+		Status:     status,
+		Error:      reason,
+		Timestamp:  domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+
+	ok, err := s.repo.Claim(ctx, &reminder)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to claim invoice reminder", zap.Error(err), zap.String("invoice_id", inv.PublicID), zap.Int("offset_days", offset))
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	return &reminder
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/core/domain"
+	portRepository "app/xonvera-core/internal/core/ports/repository"
+	portService "app/xonvera-core/internal/core/ports/service"
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+type taxRateService struct {
+	repo portRepository.TaxRateRepository
+}
+
+func NewTaxRateService(repo portRepository.TaxRateRepository) portService.TaxRateService {
+	return &taxRateService{repo: repo}
+}
+
+func (s *taxRateService) Get(ctx context.Context, userID uint) ([]domain.TaxRateResponse, error) {
+	rates, err := s.repo.GetByAuthorID(ctx, userID)
+	if err != nil {
+		logger.StdContextError(ctx, "failed to get tax rates", zap.Error(err))
+		return nil, err
+	}
+
+	res := make([]domain.TaxRateResponse, len(rates))
+	for i, v := range rates {
+		res[i] = v.Response()
+	}
+	return res, nil
+}
+
+func (s *taxRateService) Create(ctx context.Context, req *domain.TaxRateRequest) (*domain.TaxRateResponse, error) {
+	t := time.Now()
+	data := domain.TaxRate{
+		AuthorID:  req.UserID,
+		Name:      req.Name,
+		Rate:      req.Rate,
+		IsActive:  req.IsActive == nil || *req.IsActive,
+		Timestamp: domain.Timestamp{CreatedAt: t, UpdatedAt: t},
+	}
+
+	if err := s.repo.Create(ctx, &data); err != nil {
+		logger.StdContextError(ctx, "failed to create tax rate", zap.Error(err))
+		return nil, err
+	}
+
+	logger.StdContextInfo(ctx, "tax rate created successfully", zap.Uint("tax_rate_id", data.ID))
+	res := data.Response()
+	return &res, nil
+}
+
+// Update changes a tax rate. Invoices keep the rate they were issued with.
+func (s *taxRateService) Update(ctx context.Context, req *domain.TaxRateRequest) error {
+	rate, err := s.repo.GetByID(ctx, req.UserID, req.ID)
+	if err != nil {
+		return err
+	}
+
+	rate.Name = req.Name
+	rate.Rate = req.Rate
+	if req.IsActive != nil {
+		rate.IsActive = *req.IsActive
+	}
+	rate.UpdatedAt = time.Now()
+
+	if err = s.repo.Update(ctx, rate); err != nil {
+		logger.StdContextError(ctx, "failed to update tax rate", zap.Error(err), zap.Uint("tax_rate_id", req.ID))
+		return err
+	}
+
+	logger.StdContextInfo(ctx, "tax rate updated successfully", zap.Uint("tax_rate_id", req.ID))
+	return nil
+}
@@ -18,16 +18,19 @@ type tokenService struct {
 	secretKey       []byte
 	expireIn        time.Duration
 	refreshExpireIn time.Duration
+	shareExpireIn   time.Duration
 }
 
 const (
 	tokenTypeAccess  = "access"
 	tokenTypeRefresh = "refresh"
+	tokenTypeShare   = "share"
 )
 
 type TokenPayload struct {
 	UserID    uint      `json:"user_id"`
-	Type      string    `json:"type"` // "access" or "refresh"
+	ShareID   uint      `json:"share_id,omitempty"`
+	Type      string    `json:"type"` // "access", "refresh" or "share"
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
@@ -38,6 +41,7 @@ func NewTokenService(cfg *config.TokenConfig) portService.TokenService {
 		secretKey:       []byte(cfg.SecretKey),
 		expireIn:        cfg.Expired,
 		refreshExpireIn: cfg.RefreshExpired,
+		shareExpireIn:   cfg.ShareExpired,
 	}
 }
 
@@ -143,3 +147,49 @@ func (s *tokenService) ValidateRefreshToken(token string) (uint, error) {
 
 	return payload.UserID, nil
 }
+
+// ShareExpiry returns when a share link created at now expires by default
+func (s *tokenService) ShareExpiry(now time.Time) time.Time {
+	return now.Add(s.shareExpireIn)
+}
+
+// GenerateShareToken signs a share link, it carries the share ID so the link can be revoked
+func (s *tokenService) GenerateShareToken(shareID uint, expiresAt time.Time) (string, error) {
+	payload := TokenPayload{
+		ShareID:   shareID,
+		Type:      tokenTypeShare,
+		IssuedAt:  time.Now(),
+		ExpiredAt: expiresAt,
+	}
+
+	symmetricKey := make([]byte, 32)
+	copy(symmetricKey, s.secretKey)
+
+	token, err := s.paseto.Encrypt(symmetricKey, payload, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return token, nil
+}
+
+func (s *tokenService) ValidateShareToken(token string) (uint, error) {
+	var payload TokenPayload
+
+	symmetricKey := make([]byte, 32)
+	copy(symmetricKey, s.secretKey)
+
+	err := s.paseto.Decrypt(token, symmetricKey, &payload, nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid share token: %w", err)
+	}
+
+	if payload.Type != tokenTypeShare || payload.ShareID == 0 {
+		return 0, fmt.Errorf("invalid token type")
+	}
+
+	if time.Now().After(payload.ExpiredAt) {
+		return 0, fmt.Errorf("share token has expired")
+	}
+
+	return payload.ShareID, nil
+}
@@ -8,8 +8,11 @@ import (
 
 	"app/xonvera-core/internal/adapters/handler/http"
 	"app/xonvera-core/internal/adapters/middleware"
+	"app/xonvera-core/internal/adapters/notifier"
 	repositoriesRedis "app/xonvera-core/internal/adapters/repositories/redis"
 	repositoriesSql "app/xonvera-core/internal/adapters/repositories/sql"
+	"app/xonvera-core/internal/adapters/storage"
+	portService "app/xonvera-core/internal/core/ports/service"
 	"app/xonvera-core/internal/core/services"
 	"app/xonvera-core/internal/infrastructure/config"
 	"app/xonvera-core/internal/infrastructure/database"
@@ -30,6 +33,11 @@ var ProviderSet = wire.NewSet(
 	ProvideDBConfig,
 	ProvideTokenConfig,
 	ProvideRedisConfig,
+	ProvideNotifierConfig,
+	ProvideReminderConfig,
+	ProvideExportConfig,
+	ProvideStorageConfig,
+	ProvideDocumentConfig,
 	ProvideRequestTimeout,
 
 	// Database
@@ -41,10 +49,32 @@ var ProviderSet = wire.NewSet(
 	// Server
 	server.NewFiberApp,
 
+	// Notifications
+	notifier.NewNotifier,
+
+	// File storage
+	storage.NewBlobStore,
+	storage.NewDocumentStore,
+
 	// Repositories
 	repositoriesSql.NewUserRepository,
 	repositoriesSql.NewPackageRepository,
 	repositoriesSql.NewInvoiceRepository,
+	repositoriesSql.NewPaymentRepository,
+	repositoriesSql.NewTaxRateRepository,
+	repositoriesSql.NewExchangeRateRepository,
+	repositoriesSql.NewTableExchangeRateProvider,
+	repositoriesSql.NewCustomerRepository,
+	repositoriesSql.NewBusinessProfileRepository,
+	repositoriesSql.NewRecurringInvoiceRepository,
+	repositoriesSql.NewNumberingRepository,
+	repositoriesSql.NewCreditNoteRepository,
+	repositoriesSql.NewQuoteRepository,
+	repositoriesSql.NewInvoiceShareRepository,
+	repositoriesSql.NewReminderRepository,
+	repositoriesSql.NewInvoiceExportRepository,
+	repositoriesSql.NewInvoiceRevisionRepository,
+	repositoriesSql.NewInvoiceAttachmentRepository,
 	repositoriesSql.NewTxRepository,
 	repositoriesRedis.NewTokenRepository,
 
@@ -53,11 +83,44 @@ var ProviderSet = wire.NewSet(
 	services.NewAuthService,
 	services.NewPackageService,
 	services.NewInvoiceService,
+	services.NewPaymentService,
+	services.NewTaxRateService,
+	services.NewExchangeRateService,
+	services.NewCustomerService,
+	services.NewBusinessProfileService,
+	services.NewRecurringInvoiceService,
+	services.NewNumberingService,
+	services.NewCreditNoteService,
+	services.NewQuoteService,
+	services.NewInvoiceShareService,
+	services.NewReminderService,
+	services.NewInvoiceExportService,
+	services.NewInvoiceImportService,
+	services.NewInvoiceRevisionService,
+	services.NewInvoiceAttachmentService,
+	services.NewPDFSettingsService,
+	services.NewDocumentService,
 
 	// Handlers
 	http.NewAuthHandler,
 	http.NewPackageHandler,
 	http.NewInvoiceHandler,
+	http.NewPaymentHandler,
+	http.NewTaxRateHandler,
+	http.NewExchangeRateHandler,
+	http.NewCustomerHandler,
+	http.NewBusinessProfileHandler,
+	http.NewRecurringInvoiceHandler,
+	http.NewNumberingHandler,
+	http.NewCreditNoteHandler,
+	http.NewQuoteHandler,
+	http.NewInvoiceShareHandler,
+	http.NewReminderHandler,
+	http.NewInvoiceExportHandler,
+	http.NewInvoiceImportHandler,
+	http.NewInvoiceRevisionHandler,
+	http.NewInvoiceAttachmentHandler,
+	http.NewPDFSettingsHandler,
 
 	// Middleware
 	middleware.NewAuthMiddleware,
@@ -83,6 +146,31 @@ func ProvideRedisConfig(cfg *config.Config) *config.RedisConfig {
 	return &cfg.Redis
 }
 
+// ProvideNotifierConfig extracts NotifierConfig from Config
+func ProvideNotifierConfig(cfg *config.Config) *config.NotifierConfig {
+	return &cfg.Notifier
+}
+
+// ProvideReminderConfig extracts ReminderConfig from Config
+func ProvideReminderConfig(cfg *config.Config) *config.ReminderConfig {
+	return &cfg.Reminder
+}
+
+// ProvideExportConfig extracts ExportConfig from Config
+func ProvideExportConfig(cfg *config.Config) *config.ExportConfig {
+	return &cfg.Export
+}
+
+// ProvideStorageConfig extracts StorageConfig from Config
+func ProvideStorageConfig(cfg *config.Config) *config.StorageConfig {
+	return &cfg.Storage
+}
+
+// ProvideDocumentConfig extracts DocumentConfig from Config
+func ProvideDocumentConfig(cfg *config.Config) *config.DocumentConfig {
+	return &cfg.Document
+}
+
 // ProvideRequestTimeout extracts request timeout from Config
 func ProvideRequestTimeout(cfg *config.Config) time.Duration {
 	return cfg.App.RequestTimeout
@@ -90,14 +178,37 @@ func ProvideRequestTimeout(cfg *config.Config) time.Duration {
 
 // Application holds all the dependencies
 type Application struct {
-	Config         *config.Config
-	DB             *gorm.DB
-	Redis          *goredis.Client
-	FiberApp       *fiber.App
-	AuthHandler    *http.AuthHandler
-	PackageHandler *http.PackageHandler
-	InvoiceHandler *http.InvoiceHandler
-	AuthMiddleware *middleware.AuthMiddleware
+	Config                   *config.Config
+	DB                       *gorm.DB
+	Redis                    *goredis.Client
+	FiberApp                 *fiber.App
+	AuthHandler              *http.AuthHandler
+	PackageHandler           *http.PackageHandler
+	InvoiceHandler           *http.InvoiceHandler
+	PaymentHandler           *http.PaymentHandler
+	TaxRateHandler           *http.TaxRateHandler
+	ExchangeRateHandler      *http.ExchangeRateHandler
+	CustomerHandler          *http.CustomerHandler
+	BusinessProfileHandler   *http.BusinessProfileHandler
+	RecurringInvoiceHandler  *http.RecurringInvoiceHandler
+	NumberingHandler         *http.NumberingHandler
+	CreditNoteHandler        *http.CreditNoteHandler
+	QuoteHandler             *http.QuoteHandler
+	InvoiceShareHandler      *http.InvoiceShareHandler
+	ReminderHandler          *http.ReminderHandler
+	InvoiceExportHandler     *http.InvoiceExportHandler
+	InvoiceImportHandler     *http.InvoiceImportHandler
+	InvoiceRevisionHandler   *http.InvoiceRevisionHandler
+	InvoiceAttachmentHandler *http.InvoiceAttachmentHandler
+	PDFSettingsHandler       *http.PDFSettingsHandler
+	AuthMiddleware           *middleware.AuthMiddleware
+
+	// Services used outside HTTP handlers, e.g. by one-off commands and background jobs
+	CustomerService         portService.CustomerService
+	RecurringInvoiceService portService.RecurringInvoiceService
+	ReminderService         portService.ReminderService
+	InvoiceExportService    portService.InvoiceExportService
+	DocumentService         portService.DocumentService
 }
 
 // InitializeApplication creates a new Application with all dependencies wired
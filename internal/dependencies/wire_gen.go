@@ -9,8 +9,11 @@ package dependencies
 import (
 	"app/xonvera-core/internal/adapters/handler/http"
 	"app/xonvera-core/internal/adapters/middleware"
+	"app/xonvera-core/internal/adapters/notifier"
 	"app/xonvera-core/internal/adapters/repositories/redis"
 	"app/xonvera-core/internal/adapters/repositories/sql"
+	"app/xonvera-core/internal/adapters/storage"
+	"app/xonvera-core/internal/core/ports/service"
 	"app/xonvera-core/internal/core/services"
 	"app/xonvera-core/internal/infrastructure/config"
 	"app/xonvera-core/internal/infrastructure/database"
@@ -49,18 +52,101 @@ func InitializeApplication() (*Application, error) {
 	appConfig := ProvideAppConfig(configConfig)
 	invoiceRepository := repositoriesSql.NewInvoiceRepository(db)
 	txRepository := repositoriesSql.NewTxRepository(db)
-	invoiceService := services.NewInvoiceService(appConfig, invoiceRepository, txRepository)
+	paymentRepository := repositoriesSql.NewPaymentRepository(db)
+	paymentService := services.NewPaymentService(paymentRepository, invoiceRepository, txRepository)
+	taxRateRepository := repositoriesSql.NewTaxRateRepository(db)
+	customerRepository := repositoriesSql.NewCustomerRepository(db)
+	businessProfileRepository := repositoriesSql.NewBusinessProfileRepository(db)
+	numberingRepository := repositoriesSql.NewNumberingRepository(db)
+	exchangeRateProvider := repositoriesSql.NewTableExchangeRateProvider(db)
+	invoiceRevisionRepository := repositoriesSql.NewInvoiceRevisionRepository(db)
+	invoiceAttachmentRepository := repositoriesSql.NewInvoiceAttachmentRepository(db)
+	storageConfig := ProvideStorageConfig(configConfig)
+	blobStore, err := storage.NewBlobStore(storageConfig)
+	if err != nil {
+		return nil, err
+	}
+	documentConfig := ProvideDocumentConfig(configConfig)
+	documentStore, err := storage.NewDocumentStore(storageConfig, documentConfig)
+	if err != nil {
+		return nil, err
+	}
+	invoiceService := services.NewInvoiceService(appConfig, invoiceRepository, invoiceRevisionRepository, invoiceAttachmentRepository, blobStore, documentStore, taxRateRepository, userRepository, customerRepository, businessProfileRepository, numberingRepository, exchangeRateProvider, txRepository, paymentService)
 	invoiceHandler := http.NewInvoiceHandler(invoiceService, duration)
+	paymentHandler := http.NewPaymentHandler(paymentService, duration)
+	taxRateService := services.NewTaxRateService(taxRateRepository)
+	taxRateHandler := http.NewTaxRateHandler(taxRateService, duration)
+	exchangeRateRepository := repositoriesSql.NewExchangeRateRepository(db)
+	exchangeRateService := services.NewExchangeRateService(exchangeRateRepository, userRepository)
+	exchangeRateHandler := http.NewExchangeRateHandler(exchangeRateService, duration)
+	customerService := services.NewCustomerService(customerRepository, invoiceRepository, txRepository)
+	customerHandler := http.NewCustomerHandler(customerService, duration)
+	businessProfileService := services.NewBusinessProfileService(businessProfileRepository, blobStore, txRepository)
+	businessProfileHandler := http.NewBusinessProfileHandler(businessProfileService, duration)
+	recurringInvoiceRepository := repositoriesSql.NewRecurringInvoiceRepository(db)
+	recurringInvoiceService := services.NewRecurringInvoiceService(recurringInvoiceRepository, invoiceService, txRepository)
+	recurringInvoiceHandler := http.NewRecurringInvoiceHandler(recurringInvoiceService, duration)
+	numberingService := services.NewNumberingService(numberingRepository)
+	numberingHandler := http.NewNumberingHandler(numberingService, duration)
+	creditNoteRepository := repositoriesSql.NewCreditNoteRepository(db)
+	creditNoteService := services.NewCreditNoteService(appConfig, creditNoteRepository, invoiceRepository, userRepository, businessProfileRepository, numberingRepository, blobStore, documentStore, txRepository)
+	creditNoteHandler := http.NewCreditNoteHandler(creditNoteService, duration)
+	quoteRepository := repositoriesSql.NewQuoteRepository(db)
+	quoteService := services.NewQuoteService(appConfig, quoteRepository, invoiceService, taxRateRepository, userRepository, customerRepository, businessProfileRepository, numberingRepository, blobStore, documentStore, txRepository)
+	quoteHandler := http.NewQuoteHandler(quoteService, duration)
+	invoiceShareRepository := repositoriesSql.NewInvoiceShareRepository(db)
+	invoiceShareService := services.NewInvoiceShareService(invoiceShareRepository, invoiceRepository, invoiceService, tokenService)
+	invoiceShareHandler := http.NewInvoiceShareHandler(invoiceShareService, duration)
+	reminderConfig := ProvideReminderConfig(configConfig)
+	reminderRepository := repositoriesSql.NewReminderRepository(db)
+	notifierConfig := ProvideNotifierConfig(configConfig)
+	portRepositoryNotifier := notifier.NewNotifier(notifierConfig)
+	reminderService := services.NewReminderService(reminderConfig, reminderRepository, invoiceRepository, portRepositoryNotifier)
+	reminderHandler := http.NewReminderHandler(reminderService, duration)
+	exportConfig := ProvideExportConfig(configConfig)
+	invoiceExportRepository := repositoriesSql.NewInvoiceExportRepository(db)
+	invoiceExportService := services.NewInvoiceExportService(exportConfig, invoiceExportRepository, invoiceRepository, blobStore)
+	invoiceExportHandler := http.NewInvoiceExportHandler(invoiceExportService, duration)
+	invoiceImportService := services.NewInvoiceImportService(invoiceRepository, invoiceService, txRepository)
+	invoiceImportHandler := http.NewInvoiceImportHandler(invoiceImportService, duration)
+	invoiceRevisionService := services.NewInvoiceRevisionService(invoiceRevisionRepository, invoiceRepository)
+	invoiceRevisionHandler := http.NewInvoiceRevisionHandler(invoiceRevisionService, duration)
+	invoiceAttachmentService := services.NewInvoiceAttachmentService(invoiceAttachmentRepository, invoiceRepository, blobStore, txRepository)
+	invoiceAttachmentHandler := http.NewInvoiceAttachmentHandler(invoiceAttachmentService, duration)
+	pdfSettingsService := services.NewPDFSettingsService(userRepository)
+	pdfSettingsHandler := http.NewPDFSettingsHandler(pdfSettingsService, duration)
+	documentService := services.NewDocumentService(documentConfig, documentStore)
 	authMiddleware := middleware.NewAuthMiddleware(authService, duration)
 	application := &Application{
-		Config:         configConfig,
-		DB:             db,
-		Redis:          client,
-		FiberApp:       app,
-		AuthHandler:    authHandler,
-		PackageHandler: packageHandler,
-		InvoiceHandler: invoiceHandler,
-		AuthMiddleware: authMiddleware,
+		Config:                   configConfig,
+		DB:                       db,
+		Redis:                    client,
+		FiberApp:                 app,
+		AuthHandler:              authHandler,
+		PackageHandler:           packageHandler,
+		InvoiceHandler:           invoiceHandler,
+		PaymentHandler:           paymentHandler,
+		TaxRateHandler:           taxRateHandler,
+		ExchangeRateHandler:      exchangeRateHandler,
+		CustomerHandler:          customerHandler,
+		BusinessProfileHandler:   businessProfileHandler,
+		RecurringInvoiceHandler:  recurringInvoiceHandler,
+		NumberingHandler:         numberingHandler,
+		CreditNoteHandler:        creditNoteHandler,
+		QuoteHandler:             quoteHandler,
+		InvoiceShareHandler:      invoiceShareHandler,
+		ReminderHandler:          reminderHandler,
+		InvoiceExportHandler:     invoiceExportHandler,
+		InvoiceImportHandler:     invoiceImportHandler,
+		InvoiceRevisionHandler:   invoiceRevisionHandler,
+		InvoiceAttachmentHandler: invoiceAttachmentHandler,
+		PDFSettingsHandler:       pdfSettingsHandler,
+		CustomerService:          customerService,
+		RecurringInvoiceService:  recurringInvoiceService,
+		ReminderService:          reminderService,
+		InvoiceExportService:     invoiceExportService,
+		DocumentService:          documentService,
+		AuthMiddleware:           authMiddleware,
 	}
 	return application, nil
 }
@@ -72,7 +158,12 @@ var ProviderSet = wire.NewSet(config.LoadConfig, ProvideAppConfig,
 	ProvideDBConfig,
 	ProvideTokenConfig,
 	ProvideRedisConfig,
-	ProvideRequestTimeout, database.NewConnection, redis.NewRedisClient, server.NewFiberApp, repositoriesSql.NewUserRepository, repositoriesSql.NewPackageRepository, repositoriesSql.NewInvoiceRepository, repositoriesSql.NewTxRepository, repositoriesRedis.NewTokenRepository, services.NewTokenService, services.NewAuthService, services.NewPackageService, services.NewInvoiceService, http.NewAuthHandler, http.NewPackageHandler, http.NewInvoiceHandler, middleware.NewAuthMiddleware,
+	ProvideNotifierConfig,
+	ProvideReminderConfig,
+	ProvideExportConfig,
+	ProvideStorageConfig,
+	ProvideDocumentConfig,
+	ProvideRequestTimeout, database.NewConnection, redis.NewRedisClient, server.NewFiberApp, notifier.NewNotifier, storage.NewBlobStore, storage.NewDocumentStore, repositoriesSql.NewUserRepository, repositoriesSql.NewPackageRepository, repositoriesSql.NewInvoiceRepository, repositoriesSql.NewPaymentRepository, repositoriesSql.NewTaxRateRepository, repositoriesSql.NewExchangeRateRepository, repositoriesSql.NewTableExchangeRateProvider, repositoriesSql.NewCustomerRepository, repositoriesSql.NewBusinessProfileRepository, repositoriesSql.NewRecurringInvoiceRepository, repositoriesSql.NewNumberingRepository, repositoriesSql.NewCreditNoteRepository, repositoriesSql.NewQuoteRepository, repositoriesSql.NewInvoiceShareRepository, repositoriesSql.NewReminderRepository, repositoriesSql.NewInvoiceExportRepository, repositoriesSql.NewInvoiceRevisionRepository, repositoriesSql.NewInvoiceAttachmentRepository, repositoriesSql.NewTxRepository, repositoriesRedis.NewTokenRepository, services.NewTokenService, services.NewAuthService, services.NewPackageService, services.NewInvoiceService, services.NewPaymentService, services.NewTaxRateService, services.NewExchangeRateService, services.NewCustomerService, services.NewBusinessProfileService, services.NewRecurringInvoiceService, services.NewNumberingService, services.NewCreditNoteService, services.NewQuoteService, services.NewInvoiceShareService, services.NewReminderService, services.NewInvoiceExportService, services.NewInvoiceImportService, services.NewInvoiceRevisionService, services.NewInvoiceAttachmentService, services.NewPDFSettingsService, services.NewDocumentService, http.NewAuthHandler, http.NewPackageHandler, http.NewInvoiceHandler, http.NewPaymentHandler, http.NewTaxRateHandler, http.NewExchangeRateHandler, http.NewCustomerHandler, http.NewBusinessProfileHandler, http.NewRecurringInvoiceHandler, http.NewNumberingHandler, http.NewCreditNoteHandler, http.NewQuoteHandler, http.NewInvoiceShareHandler, http.NewReminderHandler, http.NewInvoiceExportHandler, http.NewInvoiceImportHandler, http.NewInvoiceRevisionHandler, http.NewInvoiceAttachmentHandler, http.NewPDFSettingsHandler, middleware.NewAuthMiddleware,
 )
 
 // ProvideAppConfig extracts App from Config
@@ -95,6 +186,31 @@ func ProvideRedisConfig(cfg *config.Config) *config.RedisConfig {
 	return &cfg.Redis
 }
 
+// ProvideNotifierConfig extracts NotifierConfig from Config
+func ProvideNotifierConfig(cfg *config.Config) *config.NotifierConfig {
+	return &cfg.Notifier
+}
+
+// ProvideReminderConfig extracts ReminderConfig from Config
+func ProvideReminderConfig(cfg *config.Config) *config.ReminderConfig {
+	return &cfg.Reminder
+}
+
+// ProvideExportConfig extracts ExportConfig from Config
+func ProvideExportConfig(cfg *config.Config) *config.ExportConfig {
+	return &cfg.Export
+}
+
+// ProvideStorageConfig extracts StorageConfig from Config
+func ProvideStorageConfig(cfg *config.Config) *config.StorageConfig {
+	return &cfg.Storage
+}
+
+// ProvideDocumentConfig extracts DocumentConfig from Config
+func ProvideDocumentConfig(cfg *config.Config) *config.DocumentConfig {
+	return &cfg.Document
+}
+
 // ProvideRequestTimeout extracts request timeout from Config
 func ProvideRequestTimeout(cfg *config.Config) time.Duration {
 	return cfg.App.RequestTimeout
@@ -102,12 +218,35 @@ func ProvideRequestTimeout(cfg *config.Config) time.Duration {
 
 // Application holds all the dependencies
 type Application struct {
-	Config         *config.Config
-	DB             *gorm.DB
-	Redis          *redis2.Client
-	FiberApp       *fiber.App
-	AuthHandler    *http.AuthHandler
-	PackageHandler *http.PackageHandler
-	InvoiceHandler *http.InvoiceHandler
-	AuthMiddleware *middleware.AuthMiddleware
+	Config                   *config.Config
+	DB                       *gorm.DB
+	Redis                    *redis2.Client
+	FiberApp                 *fiber.App
+	AuthHandler              *http.AuthHandler
+	PackageHandler           *http.PackageHandler
+	InvoiceHandler           *http.InvoiceHandler
+	PaymentHandler           *http.PaymentHandler
+	TaxRateHandler           *http.TaxRateHandler
+	ExchangeRateHandler      *http.ExchangeRateHandler
+	CustomerHandler          *http.CustomerHandler
+	BusinessProfileHandler   *http.BusinessProfileHandler
+	RecurringInvoiceHandler  *http.RecurringInvoiceHandler
+	NumberingHandler         *http.NumberingHandler
+	CreditNoteHandler        *http.CreditNoteHandler
+	QuoteHandler             *http.QuoteHandler
+	InvoiceShareHandler      *http.InvoiceShareHandler
+	ReminderHandler          *http.ReminderHandler
+	InvoiceExportHandler     *http.InvoiceExportHandler
+	InvoiceImportHandler     *http.InvoiceImportHandler
+	InvoiceRevisionHandler   *http.InvoiceRevisionHandler
+	InvoiceAttachmentHandler *http.InvoiceAttachmentHandler
+	PDFSettingsHandler       *http.PDFSettingsHandler
+	AuthMiddleware           *middleware.AuthMiddleware
+
+	// Services used outside HTTP handlers, e.g. by one-off commands and background jobs
+	CustomerService         portService.CustomerService
+	RecurringInvoiceService portService.RecurringInvoiceService
+	ReminderService         portService.ReminderService
+	InvoiceExportService    portService.InvoiceExportService
+	DocumentService         portService.DocumentService
 }
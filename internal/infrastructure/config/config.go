@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,11 +15,18 @@ import (
 
 type (
 	Config struct {
-		App        AppConfig        `mapstructure:",squash"`
-		Database   DatabaseConfig   `mapstructure:",squash"`
-		Token      TokenConfig      `mapstructure:",squash"`
-		Redis      RedisConfig      `mapstructure:",squash"`
-		Pagination PaginationConfig `mapstructure:",squash"`
+		App         AppConfig         `mapstructure:",squash"`
+		Database    DatabaseConfig    `mapstructure:",squash"`
+		Token       TokenConfig       `mapstructure:",squash"`
+		Redis       RedisConfig       `mapstructure:",squash"`
+		Pagination  PaginationConfig  `mapstructure:",squash"`
+		Scheduler   SchedulerConfig   `mapstructure:",squash"`
+		Notifier    NotifierConfig    `mapstructure:",squash"`
+		Reminder    ReminderConfig    `mapstructure:",squash"`
+		Export      ExportConfig      `mapstructure:",squash"`
+		Idempotency IdempotencyConfig `mapstructure:",squash"`
+		Storage     StorageConfig     `mapstructure:",squash"`
+		Document    DocumentConfig    `mapstructure:",squash"`
 	}
 
 	AppConfig struct {
@@ -47,6 +56,7 @@ type (
 		SecretKey      string `mapstructure:"TOKEN_SECRET_KEY"`
 		Expired        time.Duration
 		RefreshExpired time.Duration
+		ShareExpired   time.Duration
 	}
 
 	RedisConfig struct {
@@ -60,6 +70,68 @@ type (
 		DefaultLimit int `mapstructure:"PAGINATION_DEFAULT_LIMIT"`
 		MaxLimit     int `mapstructure:"PAGINATION_MAX_LIMIT"`
 	}
+
+	SchedulerConfig struct {
+		Enabled  bool `mapstructure:"SCHEDULER_ENABLED"`
+		Interval time.Duration
+	}
+
+	// NotifierConfig selects how customer notifications are delivered, "log" writes them
+	// to LogFile (or the application log) instead of sending them
+	NotifierConfig struct {
+		Driver          string `mapstructure:"NOTIFIER_DRIVER"`
+		LogFile         string `mapstructure:"NOTIFIER_LOG_FILE"`
+		SMTPHost        string `mapstructure:"SMTP_HOST"`
+		SMTPPort        string `mapstructure:"SMTP_PORT"`
+		SMTPUser        string `mapstructure:"SMTP_USER"`
+		SMTPPassword    string `mapstructure:"SMTP_PASSWORD"`
+		SMTPFrom        string `mapstructure:"SMTP_FROM"`
+		WhatsAppURL     string `mapstructure:"WHATSAPP_API_URL"`
+		WhatsAppPhoneID string `mapstructure:"WHATSAPP_PHONE_NUMBER_ID"`
+		WhatsAppToken   string `mapstructure:"WHATSAPP_TOKEN"`
+	}
+
+	// ReminderConfig lists the days relative to the due date payment reminders are sent on
+	ReminderConfig struct {
+		Offsets []int
+	}
+
+	// ExportConfig bounds invoice exports, Timeout applies to one export and TTL to how long
+	// background export files are kept below Prefix on the storage driver.
+	// A download of more than StreamLimit invoices is queued as a background export instead.
+	ExportConfig struct {
+		Timeout     time.Duration
+		TTL         time.Duration
+		Prefix      string `mapstructure:"EXPORT_PREFIX"`
+		StreamLimit int    `mapstructure:"EXPORT_STREAM_LIMIT"`
+	}
+
+	// IdempotencyConfig sets how long the response of a request sent with an Idempotency-Key is replayed
+	IdempotencyConfig struct {
+		TTL time.Duration
+	}
+
+	// StorageConfig selects where uploaded files are kept, "local" writes them under LocalDir
+	// and "s3" puts them in a bucket of any S3 compatible service
+	StorageConfig struct {
+		Driver      string `mapstructure:"STORAGE_DRIVER"`
+		LocalDir    string `mapstructure:"STORAGE_LOCAL_DIR"`
+		S3Endpoint  string `mapstructure:"S3_ENDPOINT"`
+		S3Region    string `mapstructure:"S3_REGION"`
+		S3Bucket    string `mapstructure:"S3_BUCKET"`
+		S3AccessKey string `mapstructure:"S3_ACCESS_KEY"`
+		S3SecretKey string `mapstructure:"S3_SECRET_KEY"`
+		S3PathStyle bool   `mapstructure:"S3_PATH_STYLE"`
+	}
+
+	// DocumentConfig sets where rendered PDFs are cached on the storage driver and when the cleanup job
+	// removes them, the newest rendering of a document is kept for Retention and then rendered again on demand
+	DocumentConfig struct {
+		LocalDir        string `mapstructure:"DOCUMENT_LOCAL_DIR"`
+		S3Prefix        string `mapstructure:"DOCUMENT_S3_PREFIX"`
+		Retention       time.Duration
+		CleanupInterval time.Duration
+	}
 )
 
 func LoadConfig() *Config {
@@ -89,6 +161,9 @@ func LoadConfig() *Config {
 	// Parse allowed origins from comma-separated env value
 	parseAllowedOrigins(&cfg)
 
+	// Parse reminder offsets from comma-separated env value
+	parseReminderOffsets(&cfg)
+
 	return &cfg
 }
 
@@ -113,6 +188,34 @@ func parseAllowedOrigins(cfg *Config) {
 	}
 }
 
+// parseReminderOffsets parses REMINDER_OFFSETS, e.g. "-3,0,7", into sorted unique days
+func parseReminderOffsets(cfg *Config) {
+	raw := viper.GetString("REMINDER_OFFSETS")
+	if raw == "" {
+		return
+	}
+
+	seen := make(map[int]bool)
+	offsets := make([]int, 0)
+	for _, part := range strings.Split(raw, ",") {
+		trimmed := strings.TrimSpace(part)
+		if trimmed == "" {
+			continue
+		}
+		offset, err := strconv.Atoi(trimmed)
+		if err != nil {
+			logger.Fatal("Failed to parse reminder offsets", zap.String("value", raw), zap.Error(err))
+		}
+		if !seen[offset] {
+			seen[offset] = true
+			offsets = append(offsets, offset)
+		}
+	}
+
+	sort.Ints(offsets)
+	cfg.Reminder.Offsets = offsets
+}
+
 // parseDurationConfigs parses all duration-based environment variables
 func parseDurationConfigs(cfg *Config) {
 	durationConfigs := []struct {
@@ -145,6 +248,41 @@ func parseDurationConfigs(cfg *Config) {
 			target:    &cfg.Token.RefreshExpired,
 			fieldName: "TOKEN_REFRESH_EXPIRE",
 		},
+		{
+			envKey:    "TOKEN_SHARE_EXPIRE",
+			target:    &cfg.Token.ShareExpired,
+			fieldName: "TOKEN_SHARE_EXPIRE",
+		},
+		{
+			envKey:    "SCHEDULER_INTERVAL",
+			target:    &cfg.Scheduler.Interval,
+			fieldName: "SCHEDULER_INTERVAL",
+		},
+		{
+			envKey:    "EXPORT_TIMEOUT",
+			target:    &cfg.Export.Timeout,
+			fieldName: "EXPORT_TIMEOUT",
+		},
+		{
+			envKey:    "EXPORT_TTL",
+			target:    &cfg.Export.TTL,
+			fieldName: "EXPORT_TTL",
+		},
+		{
+			envKey:    "IDEMPOTENCY_TTL",
+			target:    &cfg.Idempotency.TTL,
+			fieldName: "IDEMPOTENCY_TTL",
+		},
+		{
+			envKey:    "DOCUMENT_RETENTION",
+			target:    &cfg.Document.Retention,
+			fieldName: "DOCUMENT_RETENTION",
+		},
+		{
+			envKey:    "DOCUMENT_CLEANUP_INTERVAL",
+			target:    &cfg.Document.CleanupInterval,
+			fieldName: "DOCUMENT_CLEANUP_INTERVAL",
+		},
 	}
 
 	for _, dc := range durationConfigs {
@@ -206,10 +344,42 @@ func setDefaults() {
 	viper.SetDefault("TOKEN_SECRET_KEY", "your-super-secret-key-min-32-chars!!")
 	viper.SetDefault("TOKEN_EXPIRE", "24h")
 	viper.SetDefault("TOKEN_REFRESH_EXPIRE", "168h")
+	viper.SetDefault("TOKEN_SHARE_EXPIRE", "720h")
 
 	// Redis defaults
 	viper.SetDefault("REDIS_HOST", "localhost")
 	viper.SetDefault("REDIS_PORT", "6379")
 	viper.SetDefault("REDIS_PASSWORD", "")
 	viper.SetDefault("REDIS_DB", 0) // 7 days
+
+	// Scheduler defaults
+	viper.SetDefault("SCHEDULER_ENABLED", true)
+	viper.SetDefault("SCHEDULER_INTERVAL", "1m")
+
+	// Notification defaults
+	viper.SetDefault("NOTIFIER_DRIVER", "log")
+	viper.SetDefault("SMTP_PORT", "587")
+	viper.SetDefault("WHATSAPP_API_URL", "https://graph.facebook.com/v20.0")
+	viper.SetDefault("REMINDER_OFFSETS", "-3,0,7")
+
+	// Export defaults
+	viper.SetDefault("EXPORT_TIMEOUT", "10m")
+	viper.SetDefault("EXPORT_TTL", "168h")
+	viper.SetDefault("EXPORT_PREFIX", "exports/")
+	viper.SetDefault("EXPORT_STREAM_LIMIT", 5000)
+
+	// Idempotency defaults
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+
+	// Storage defaults
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_LOCAL_DIR", "assets/blobs")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_PATH_STYLE", false)
+
+	// Document defaults
+	viper.SetDefault("DOCUMENT_LOCAL_DIR", "assets/pdf")
+	viper.SetDefault("DOCUMENT_S3_PREFIX", "documents/")
+	viper.SetDefault("DOCUMENT_RETENTION", "720h")
+	viper.SetDefault("DOCUMENT_CLEANUP_INTERVAL", "1h")
 }
@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"app/xonvera-core/internal/infrastructure/logger"
+
+	"go.uber.org/zap"
+)
+
+// Job is a unit of background work, it is called once per tick
+type Job func(ctx context.Context) error
+
+// Start runs job right away and then on every interval until ctx is cancelled.
+// A tick is skipped while the previous run is still busy.
+func Start(ctx context.Context, name string, interval time.Duration, job Job) {
+	if interval <= 0 {
+		logger.Warn("Background job not started, interval must be positive", zap.String("job", name), zap.Duration("interval", interval))
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		logger.Info("Background job started", zap.String("job", name), zap.Duration("interval", interval))
+		for {
+			if err := job(ctx); err != nil && ctx.Err() == nil {
+				logger.Error("Background job failed", zap.String("job", name), zap.Error(err))
+			}
+
+			select {
+			case <-ctx.Done():
+				logger.Info("Background job stopped", zap.String("job", name))
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"bytes"
 	"time"
 
 	"app/xonvera-core/internal/adapters/middleware"
@@ -11,6 +12,7 @@ import (
 	fiberlogger "github.com/gofiber/fiber/v3/middleware/logger"
 	"github.com/gofiber/fiber/v3/middleware/recover"
 	"github.com/redis/go-redis/v9"
+	"github.com/valyala/fasthttp"
 	"gorm.io/gorm"
 )
 
@@ -20,12 +22,13 @@ func NewFiberApp(cfg *config.Config, redisClient *redis.Client, db *gorm.DB) *fi
 
 	app := fiber.New(fiber.Config{
 		AppName:       cfg.App.Name,
-		BodyLimit:     4 * 1024 * 1024, // 4MB max body size
+		BodyLimit:     11 * 1024 * 1024, // 11MB max body size, invoice attachments are up to 10MB
 		ReadTimeout:   10 * time.Second,
 		WriteTimeout:  10 * time.Second,
 		StrictRouting: true,
 		CaseSensitive: true,
 	})
+	app.Server().HeaderReceived = exportRequestConfig(cfg.Export.Timeout)
 
 	// Global middleware
 	app.Use(middleware.RequestID())
@@ -38,7 +41,8 @@ func NewFiberApp(cfg *config.Config, redisClient *redis.Client, db *gorm.DB) *fi
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.App.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match", "Idempotency-Key"},
+		ExposeHeaders:    []string{"ETag", "Idempotent-Replayed"},
 		AllowCredentials: true,
 	}))
 
@@ -48,6 +52,23 @@ func NewFiberApp(cfg *config.Config, redisClient *redis.Client, db *gorm.DB) *fi
 	return app
 }
 
+// exportRequestConfig gives the invoice export downloads the write timeout of an export,
+// every other request keeps the write timeout of the app
+func exportRequestConfig(timeout time.Duration) func(*fasthttp.RequestHeader) fasthttp.RequestConfig {
+	return func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		if !header.IsGet() {
+			return fasthttp.RequestConfig{}
+		}
+
+		path, _, _ := bytes.Cut(header.RequestURI(), []byte("?"))
+		if bytes.Equal(path, []byte("/invoice/export")) ||
+			(bytes.HasPrefix(path, []byte("/invoice/exports/")) && bytes.HasSuffix(path, []byte("/download"))) {
+			return fasthttp.RequestConfig{WriteTimeout: timeout}
+		}
+		return fasthttp.RequestConfig{}
+	}
+}
+
 // createHealthCheckHandler returns a health check handler
 func createHealthCheckHandler(startTime time.Time, db *gorm.DB, redisClient *redis.Client, cfg *config.Config) fiber.Handler {
 	return func(c fiber.Ctx) error {
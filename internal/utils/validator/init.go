@@ -8,13 +8,13 @@ const (
 )
 
 var (
-	defaultLimit uint8
-	maxLimit     uint8
+	defaultLimit uint32
+	maxLimit     uint32
 )
 
 func SetPaginationDefaults(defaultLim, maxLim int) {
-	defaultLimit = uint8(defaultLim)
-	maxLimit = uint8(maxLim)
+	defaultLimit = uint32(defaultLim)
+	maxLimit = uint32(maxLim)
 }
 
 func Init(v *validator.Validate) {
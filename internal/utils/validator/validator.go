@@ -30,8 +30,11 @@ func HandlerBindingError(c fiber.Ctx, obj any, shouldType string, skips ...strin
 		return []string{fmt.Sprintf("payload: %s", err.Error())}
 	}
 
-	// Handle PaginationRequest offset calculation
-	if paginationReq, ok := obj.(*domain.PaginationRequest); ok {
+	// Handle PaginationRequest offset calculation, also when embedded in a filtered list request
+	if paginator, ok := obj.(interface {
+		Pagination() *domain.PaginationRequest
+	}); ok {
+		paginationReq := paginator.Pagination()
 		if paginationReq.Page < 1 {
 			paginationReq.Page = 1
 		}
@@ -41,7 +44,7 @@ func HandlerBindingError(c fiber.Ctx, obj any, shouldType string, skips ...strin
 			paginationReq.Limit = maxLimit
 		}
 
-		paginationReq.Offset = uint64((paginationReq.Page - 1) * paginationReq.Limit)
+		paginationReq.Offset = uint64(paginationReq.Page-1) * uint64(paginationReq.Limit)
 	}
 
 	return Validation(obj, skips...)
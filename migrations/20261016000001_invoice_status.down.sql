@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS app.idx_invoices_author_status;
+ALTER TABLE app.invoices DROP CONSTRAINT IF EXISTS chk_invoices_status;
+ALTER TABLE app.invoices ALTER COLUMN status SET DEFAULT 'unpaid';
+UPDATE app.invoices SET status = 'unpaid' WHERE status IN ('draft', 'sent', 'partially_paid', 'overdue');
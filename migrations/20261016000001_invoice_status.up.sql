@@ -0,0 +1,8 @@
+-- Legacy "unpaid" invoices were already handed to customers
+UPDATE app.invoices SET status = 'sent' WHERE status = 'unpaid';
+
+ALTER TABLE app.invoices ALTER COLUMN status SET DEFAULT 'draft';
+ALTER TABLE app.invoices ADD CONSTRAINT chk_invoices_status
+    CHECK (status IN ('draft', 'sent', 'partially_paid', 'paid', 'overdue', 'void'));
+
+CREATE INDEX idx_invoices_author_status ON app.invoices(author_id, status);
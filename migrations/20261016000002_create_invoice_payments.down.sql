@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS app.invoice_payments;
+ALTER TABLE app.invoices DROP COLUMN IF EXISTS amount_paid;
+ALTER TABLE app.invoices DROP COLUMN IF EXISTS total;
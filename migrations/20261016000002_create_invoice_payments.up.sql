@@ -0,0 +1,28 @@
+ALTER TABLE app.invoices ADD COLUMN total BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE app.invoices ADD COLUMN amount_paid BIGINT NOT NULL DEFAULT 0;
+
+UPDATE app.invoices i
+SET total = COALESCE((SELECT SUM(it.total) FROM app.invoice_items it WHERE it.invoice_id = i.id), 0);
+
+-- Invoices already marked paid before the ledger existed are treated as settled
+UPDATE app.invoices SET amount_paid = total WHERE status = 'paid';
+
+CREATE TABLE IF NOT EXISTS app.invoice_payments (
+    id BIGSERIAL PRIMARY KEY,
+    invoice_id BIGINT NOT NULL,
+    author_id INT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    paid_at DATE NOT NULL,
+    method VARCHAR(50) NOT NULL CHECK (method IN ('cash', 'bank_transfer', 'card', 'e_wallet', 'other')),
+    reference TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    reversed_at TIMESTAMP WITH TIME ZONE,
+    reversal_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (author_id, invoice_id) REFERENCES app.invoices(author_id, id)
+);
+
+CREATE INDEX idx_invoice_payments_invoice ON app.invoice_payments(invoice_id);
+CREATE INDEX idx_invoice_payments_author_paid_at ON app.invoice_payments(author_id, paid_at);
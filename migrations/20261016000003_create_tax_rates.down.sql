@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS app.invoice_taxes;
+
+ALTER TABLE app.invoice_items
+    DROP COLUMN IF EXISTS tax_amount,
+    DROP COLUMN IF EXISTS subtotal,
+    DROP COLUMN IF EXISTS tax_rate,
+    DROP COLUMN IF EXISTS tax_name,
+    DROP COLUMN IF EXISTS tax_rate_id;
+
+ALTER TABLE app.invoices
+    DROP COLUMN IF EXISTS tax_total,
+    DROP COLUMN IF EXISTS subtotal,
+    DROP COLUMN IF EXISTS tax_rate_id,
+    DROP COLUMN IF EXISTS tax_mode;
+
+DROP TABLE IF EXISTS app.tax_rates;
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS app.tax_rates (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    rate INT NOT NULL CHECK (rate >= 0 AND rate <= 10000), -- basis points, 1100 = 11%
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_tax_rates_author ON app.tax_rates(author_id);
+
+ALTER TABLE app.invoices
+    ADD COLUMN tax_mode VARCHAR(20) NOT NULL DEFAULT 'exclusive' CHECK (tax_mode IN ('exclusive', 'inclusive')),
+    ADD COLUMN tax_rate_id INT,
+    ADD COLUMN subtotal BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN tax_total BIGINT NOT NULL DEFAULT 0;
+
+UPDATE app.invoices SET subtotal = total;
+
+-- Tax name and rate are snapshotted so later rate changes never alter issued invoices
+ALTER TABLE app.invoice_items
+    ADD COLUMN tax_rate_id INT,
+    ADD COLUMN tax_name VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN tax_rate INT NOT NULL DEFAULT 0,
+    ADD COLUMN subtotal BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN tax_amount BIGINT NOT NULL DEFAULT 0;
+
+UPDATE app.invoice_items SET subtotal = total;
+
+CREATE TABLE IF NOT EXISTS app.invoice_taxes (
+    invoice_id BIGINT NOT NULL,
+    id SMALLINT NOT NULL,
+    tax_rate_id INT,
+    name VARCHAR(100) NOT NULL,
+    rate INT NOT NULL,
+    taxable_amount BIGINT NOT NULL,
+    tax_amount BIGINT NOT NULL,
+    PRIMARY KEY (invoice_id, id)
+);
@@ -0,0 +1,11 @@
+ALTER TABLE app.invoice_items
+    DROP COLUMN IF EXISTS invoice_discount,
+    DROP COLUMN IF EXISTS discount_amount,
+    DROP COLUMN IF EXISTS discount,
+    DROP COLUMN IF EXISTS discount_type;
+
+ALTER TABLE app.invoices
+    DROP COLUMN IF EXISTS discount_total,
+    DROP COLUMN IF EXISTS discount_amount,
+    DROP COLUMN IF EXISTS discount,
+    DROP COLUMN IF EXISTS discount_type;
@@ -0,0 +1,12 @@
+ALTER TABLE app.invoices
+    ADD COLUMN discount_type VARCHAR(20) NOT NULL DEFAULT '' CHECK (discount_type IN ('', 'percentage', 'amount')),
+    ADD COLUMN discount BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN discount_amount BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN discount_total BIGINT NOT NULL DEFAULT 0;
+
+-- invoice_discount is the share of the invoice level discount allocated to the item before tax
+ALTER TABLE app.invoice_items
+    ADD COLUMN discount_type VARCHAR(20) NOT NULL DEFAULT '' CHECK (discount_type IN ('', 'percentage', 'amount')),
+    ADD COLUMN discount BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN discount_amount BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN invoice_discount BIGINT NOT NULL DEFAULT 0;
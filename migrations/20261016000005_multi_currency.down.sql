@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS app.exchange_rates;
+
+DROP INDEX IF EXISTS app.idx_invoices_author_base_currency;
+
+ALTER TABLE app.invoices
+    DROP COLUMN IF EXISTS base_total,
+    DROP COLUMN IF EXISTS base_currency,
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE auth.users
+    DROP COLUMN IF EXISTS base_currency;
@@ -0,0 +1,27 @@
+ALTER TABLE auth.users
+    ADD COLUMN base_currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
+
+-- Amounts are stored in minor units of the invoice currency, IDR has no minor unit.
+-- exchange_rate is the price of one major unit of currency in base_currency scaled by 1e8.
+ALTER TABLE app.invoices
+    ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    ADD COLUMN exchange_rate BIGINT NOT NULL DEFAULT 100000000 CHECK (exchange_rate > 0),
+    ADD COLUMN base_currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    ADD COLUMN base_total BIGINT NOT NULL DEFAULT 0;
+
+UPDATE app.invoices SET base_total = total;
+
+CREATE INDEX idx_invoices_author_base_currency ON app.invoices(author_id, base_currency);
+
+CREATE TABLE IF NOT EXISTS app.exchange_rates (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL,
+    rate BIGINT NOT NULL CHECK (rate > 0),
+    effective_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (author_id, currency, base_currency, effective_date)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS app.idx_invoices_author_created_at;
+DROP INDEX IF EXISTS app.idx_invoices_author_deleted_at;
+CREATE INDEX idx_invoices_deleted_at ON app.invoices(deleted_at);
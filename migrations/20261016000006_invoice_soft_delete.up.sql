@@ -0,0 +1,12 @@
+-- Rows created before deleted_at was nullable in the application carry a zero timestamp
+UPDATE auth.users SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+UPDATE app.packages SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+UPDATE app.invoices SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+UPDATE app.invoice_items SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+UPDATE app.invoice_payments SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+UPDATE app.tax_rates SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+UPDATE app.exchange_rates SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+
+DROP INDEX IF EXISTS app.idx_invoices_deleted_at;
+CREATE INDEX idx_invoices_author_deleted_at ON app.invoices(author_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_invoices_author_created_at ON app.invoices(author_id, created_at DESC) WHERE deleted_at IS NULL;
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS app.idx_invoices_author_customer;
+
+ALTER TABLE app.invoices
+    DROP COLUMN IF EXISTS customer_tax_id,
+    DROP COLUMN IF EXISTS customer_address,
+    DROP COLUMN IF EXISTS customer_phone,
+    DROP COLUMN IF EXISTS customer_email,
+    DROP COLUMN IF EXISTS customer_company,
+    DROP COLUMN IF EXISTS customer_id;
+
+DROP TABLE IF EXISTS app.customers;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS app.customers (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    company VARCHAR(200) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    tax_id VARCHAR(30) NOT NULL DEFAULT '', -- NPWP
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_customers_author_name ON app.customers(author_id, name) WHERE deleted_at IS NULL;
+
+-- customer keeps the snapshotted name, the other columns snapshot the rest of the customer
+ALTER TABLE app.invoices
+    ADD COLUMN customer_id INT,
+    ADD COLUMN customer_company VARCHAR(200) NOT NULL DEFAULT '',
+    ADD COLUMN customer_email VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN customer_phone VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN customer_address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN customer_tax_id VARCHAR(30) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_invoices_author_customer ON app.invoices(author_id, customer_id);
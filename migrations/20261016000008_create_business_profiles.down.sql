@@ -0,0 +1,4 @@
+ALTER TABLE app.invoices DROP COLUMN IF EXISTS profile_id;
+
+DROP TABLE IF EXISTS app.business_bank_accounts;
+DROP TABLE IF EXISTS app.business_profiles;
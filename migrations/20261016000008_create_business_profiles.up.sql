@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS app.business_profiles (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    legal_name VARCHAR(200) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    tax_id VARCHAR(30) NOT NULL DEFAULT '', -- NPWP
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    logo_path VARCHAR(255) NOT NULL DEFAULT '',
+    payment_term_days INT NOT NULL DEFAULT 0,
+    footer_text TEXT NOT NULL DEFAULT '',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- at most one default profile per user
+CREATE UNIQUE INDEX idx_business_profiles_author_default ON app.business_profiles(author_id) WHERE is_default AND deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS app.business_bank_accounts (
+    id SERIAL PRIMARY KEY,
+    profile_id INT NOT NULL REFERENCES app.business_profiles(id) ON DELETE CASCADE,
+    bank_name VARCHAR(100) NOT NULL,
+    account_name VARCHAR(200) NOT NULL,
+    account_number VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_business_bank_accounts_profile ON app.business_bank_accounts(profile_id);
+
+ALTER TABLE app.invoices ADD COLUMN profile_id INT;
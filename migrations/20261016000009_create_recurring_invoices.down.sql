@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS app.idx_invoices_recurring_run;
+
+ALTER TABLE app.invoices
+    DROP COLUMN IF EXISTS recurring_run_date,
+    DROP COLUMN IF EXISTS recurring_id;
+
+DROP TABLE IF EXISTS app.recurring_invoice_items;
+DROP TABLE IF EXISTS app.recurring_invoices;
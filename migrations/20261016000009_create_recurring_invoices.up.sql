@@ -0,0 +1,54 @@
+-- dates are stored as YYYY-MM-DD like invoices.issue_date, next_run_date is empty once a schedule ended
+CREATE TABLE IF NOT EXISTS app.recurring_invoices (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    profile_id INT,
+    issuer TEXT NOT NULL DEFAULT '',
+    customer_id INT,
+    customer TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    tax_mode VARCHAR(20) NOT NULL DEFAULT '',
+    tax_rate_id INT,
+    discount_type VARCHAR(20) NOT NULL DEFAULT '',
+    discount INTEGER NOT NULL DEFAULT 0,
+    "interval" VARCHAR(10) NOT NULL,
+    day_of_month SMALLINT NOT NULL DEFAULT 0,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL DEFAULT '',
+    due_days INT,
+    auto_send BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    next_run_date TEXT NOT NULL DEFAULT '',
+    last_run_date TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_recurring_invoices_due ON app.recurring_invoices(next_run_date) WHERE status = 'active' AND deleted_at IS NULL;
+CREATE INDEX idx_recurring_invoices_author ON app.recurring_invoices(author_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS app.recurring_invoice_items (
+    id SERIAL PRIMARY KEY,
+    recurring_id INT NOT NULL REFERENCES app.recurring_invoices(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    qty INTEGER NOT NULL DEFAULT 1,
+    price INTEGER NOT NULL,
+    discount_type VARCHAR(20) NOT NULL DEFAULT '',
+    discount INTEGER NOT NULL DEFAULT 0,
+    tax_rate_id INT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_recurring_invoice_items_recurring ON app.recurring_invoice_items(recurring_id);
+
+-- one invoice per schedule run, so a restarted scheduler can never bill a run twice
+ALTER TABLE app.invoices
+    ADD COLUMN recurring_id INT,
+    ADD COLUMN recurring_run_date TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_invoices_recurring_run ON app.invoices(recurring_id, recurring_run_date) WHERE recurring_id IS NOT NULL;
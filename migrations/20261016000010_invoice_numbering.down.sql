@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS app.idx_invoices_author_number;
+
+ALTER TABLE app.invoices
+    DROP COLUMN IF EXISTS number;
+
+DROP TABLE IF EXISTS app.document_sequences;
+DROP TABLE IF EXISTS app.numbering_schemes;
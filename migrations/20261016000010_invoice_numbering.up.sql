@@ -0,0 +1,31 @@
+-- one numbering template per author and document type, accounts without one use the default of the application
+CREATE TABLE IF NOT EXISTS app.numbering_schemes (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    document VARCHAR(20) NOT NULL,
+    template VARCHAR(100) NOT NULL,
+    reset VARCHAR(10) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (author_id, document)
+);
+
+-- counters are taken inside the issuing transaction, the row lock keeps numbers gapless
+-- period is YYYY, YYYY-MM or empty depending on the reset of the scheme
+CREATE TABLE IF NOT EXISTS app.document_sequences (
+    author_id INT NOT NULL,
+    document VARCHAR(20) NOT NULL,
+    period VARCHAR(7) NOT NULL DEFAULT '',
+    counter BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (author_id, document, period)
+);
+
+ALTER TABLE app.invoices
+    ADD COLUMN number TEXT NOT NULL DEFAULT '';
+
+-- invoices issued before numbering schemes keep the ID printed on their PDF as number
+UPDATE app.invoices SET number = id::text WHERE status <> 'draft';
+
+CREATE UNIQUE INDEX idx_invoices_author_number ON app.invoices(author_id, number) WHERE number <> '';
@@ -0,0 +1,15 @@
+ALTER TABLE app.invoice_taxes
+    DROP CONSTRAINT IF EXISTS invoice_taxes_invoice_fkey,
+    DROP CONSTRAINT IF EXISTS invoice_taxes_pkey,
+    ADD PRIMARY KEY (invoice_id, id),
+    DROP COLUMN IF EXISTS author_id;
+
+ALTER TABLE app.invoice_items
+    DROP CONSTRAINT IF EXISTS invoice_items_invoice_fkey,
+    DROP CONSTRAINT IF EXISTS invoice_items_pkey,
+    ADD PRIMARY KEY (invoice_id, id),
+    DROP COLUMN IF EXISTS author_id;
+
+DROP INDEX IF EXISTS app.idx_invoices_public_id;
+
+ALTER TABLE app.invoices DROP COLUMN IF EXISTS public_id;
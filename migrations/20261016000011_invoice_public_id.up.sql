@@ -0,0 +1,41 @@
+-- invoice IDs are only unique per author, URLs use an opaque public ID instead
+ALTER TABLE app.invoices ADD COLUMN public_id UUID;
+
+UPDATE app.invoices SET public_id = gen_random_uuid() WHERE public_id IS NULL;
+
+ALTER TABLE app.invoices
+    ALTER COLUMN public_id SET NOT NULL,
+    ALTER COLUMN public_id SET DEFAULT gen_random_uuid();
+
+CREATE UNIQUE INDEX idx_invoices_public_id ON app.invoices(public_id);
+
+-- items and taxes are keyed by author like their invoice, so equal invoice IDs of two authors never mix.
+-- An invoice ID could only be created once with items before, so the backfill below is unambiguous.
+ALTER TABLE app.invoice_items ADD COLUMN author_id INT;
+ALTER TABLE app.invoice_taxes ADD COLUMN author_id INT;
+
+UPDATE app.invoice_items it
+SET author_id = i.author_id
+FROM app.invoices i
+WHERE i.id = it.invoice_id AND it.author_id IS NULL;
+
+UPDATE app.invoice_taxes t
+SET author_id = i.author_id
+FROM app.invoices i
+WHERE i.id = t.invoice_id AND t.author_id IS NULL;
+
+-- rows left without an invoice can not be attributed to anyone
+DELETE FROM app.invoice_items WHERE author_id IS NULL;
+DELETE FROM app.invoice_taxes WHERE author_id IS NULL;
+
+ALTER TABLE app.invoice_items
+    ALTER COLUMN author_id SET NOT NULL,
+    DROP CONSTRAINT invoice_items_pkey,
+    ADD PRIMARY KEY (author_id, invoice_id, id),
+    ADD CONSTRAINT invoice_items_invoice_fkey FOREIGN KEY (author_id, invoice_id) REFERENCES app.invoices(author_id, id);
+
+ALTER TABLE app.invoice_taxes
+    ALTER COLUMN author_id SET NOT NULL,
+    DROP CONSTRAINT invoice_taxes_pkey,
+    ADD PRIMARY KEY (author_id, invoice_id, id),
+    ADD CONSTRAINT invoice_taxes_invoice_fkey FOREIGN KEY (author_id, invoice_id) REFERENCES app.invoices(author_id, id);
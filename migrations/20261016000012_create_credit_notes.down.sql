@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS app.credit_allocations;
+DROP TABLE IF EXISTS app.credit_note_items;
+DROP TABLE IF EXISTS app.credit_notes;
+ALTER TABLE app.invoices DROP COLUMN IF EXISTS amount_credited;
@@ -0,0 +1,74 @@
+-- credit applied to an invoice from any credit note, amount_paid stays the money actually received
+ALTER TABLE app.invoices ADD COLUMN amount_credited BIGINT NOT NULL DEFAULT 0;
+
+-- customer and issuer are copied from the credited invoice, like the invoice snapshots them when sent
+CREATE TABLE IF NOT EXISTS app.credit_notes (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    number TEXT NOT NULL,
+    invoice_id BIGINT NOT NULL,
+    profile_id INT,
+    issuer TEXT NOT NULL DEFAULT '',
+    customer_id INT,
+    customer TEXT NOT NULL DEFAULT '',
+    customer_company TEXT NOT NULL DEFAULT '',
+    customer_email TEXT NOT NULL DEFAULT '',
+    customer_phone TEXT NOT NULL DEFAULT '',
+    customer_address TEXT NOT NULL DEFAULT '',
+    customer_tax_id TEXT NOT NULL DEFAULT '',
+    issue_date TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    tax_mode VARCHAR(20) NOT NULL DEFAULT '',
+    subtotal BIGINT NOT NULL DEFAULT 0,
+    tax_total BIGINT NOT NULL DEFAULT 0,
+    total BIGINT NOT NULL DEFAULT 0,
+    amount_applied BIGINT NOT NULL DEFAULT 0,
+    amount_refunded BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (author_id, invoice_id) REFERENCES app.invoices(author_id, id),
+    CHECK (amount_applied + amount_refunded <= total)
+);
+
+CREATE UNIQUE INDEX idx_credit_notes_author_number ON app.credit_notes(author_id, number);
+CREATE INDEX idx_credit_notes_invoice ON app.credit_notes(author_id, invoice_id);
+
+-- invoice_item_id points at the credited line, invoices with credit notes can no longer be edited
+CREATE TABLE IF NOT EXISTS app.credit_note_items (
+    id SERIAL PRIMARY KEY,
+    credit_note_id INT NOT NULL REFERENCES app.credit_notes(id) ON DELETE CASCADE,
+    invoice_item_id INT NOT NULL,
+    description TEXT NOT NULL,
+    qty INTEGER NOT NULL CHECK (qty > 0),
+    price INTEGER NOT NULL,
+    tax_rate_id INT,
+    tax_name VARCHAR(100) NOT NULL DEFAULT '',
+    tax_rate INTEGER NOT NULL DEFAULT 0,
+    subtotal BIGINT NOT NULL DEFAULT 0,
+    tax_amount BIGINT NOT NULL DEFAULT 0,
+    total BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_credit_note_items_credit_note ON app.credit_note_items(credit_note_id);
+
+-- invoice_id is NULL for credit refunded to the customer
+CREATE TABLE IF NOT EXISTS app.credit_allocations (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    credit_note_id INT NOT NULL REFERENCES app.credit_notes(id) ON DELETE CASCADE,
+    invoice_id BIGINT,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (author_id, invoice_id) REFERENCES app.invoices(author_id, id)
+);
+
+CREATE INDEX idx_credit_allocations_credit_note ON app.credit_allocations(credit_note_id);
+CREATE INDEX idx_credit_allocations_invoice ON app.credit_allocations(author_id, invoice_id) WHERE invoice_id IS NOT NULL;
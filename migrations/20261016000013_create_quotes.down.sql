@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS app.quote_items;
+DROP TABLE IF EXISTS app.quotes;
@@ -0,0 +1,55 @@
+-- quotes share the pricing of invoices, the number is taken from the quote numbering scheme on create
+CREATE TABLE IF NOT EXISTS app.quotes (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    number TEXT NOT NULL,
+    profile_id INT,
+    issuer TEXT NOT NULL DEFAULT '',
+    customer_id INT,
+    customer TEXT NOT NULL DEFAULT '',
+    customer_company TEXT NOT NULL DEFAULT '',
+    customer_email TEXT NOT NULL DEFAULT '',
+    customer_phone TEXT NOT NULL DEFAULT '',
+    customer_address TEXT NOT NULL DEFAULT '',
+    customer_tax_id TEXT NOT NULL DEFAULT '',
+    issue_date TEXT NOT NULL,
+    valid_until TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    tax_mode VARCHAR(20) NOT NULL DEFAULT 'exclusive',
+    tax_rate_id INT,
+    discount_type VARCHAR(20) NOT NULL DEFAULT '',
+    discount INTEGER NOT NULL DEFAULT 0,
+    subtotal BIGINT NOT NULL DEFAULT 0,
+    discount_amount BIGINT NOT NULL DEFAULT 0,
+    discount_total BIGINT NOT NULL DEFAULT 0,
+    tax_total BIGINT NOT NULL DEFAULT 0,
+    total BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CHECK (valid_until >= issue_date)
+);
+
+CREATE UNIQUE INDEX idx_quotes_author_number ON app.quotes(author_id, number);
+CREATE INDEX idx_quotes_author ON app.quotes(author_id) WHERE deleted_at IS NULL;
+
+-- lines are numbered per quote, invoice_id holds the public ID of the invoice a line was converted into
+CREATE TABLE IF NOT EXISTS app.quote_items (
+    id INT NOT NULL,
+    quote_id INT NOT NULL REFERENCES app.quotes(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    qty INTEGER NOT NULL DEFAULT 1,
+    price INTEGER NOT NULL,
+    discount_type VARCHAR(20) NOT NULL DEFAULT '',
+    discount INTEGER NOT NULL DEFAULT 0,
+    tax_rate_id INT,
+    tax_name VARCHAR(100) NOT NULL DEFAULT '',
+    tax_rate INTEGER NOT NULL DEFAULT 0,
+    invoice_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (quote_id, id)
+);
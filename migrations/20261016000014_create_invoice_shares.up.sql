@@ -0,0 +1,17 @@
+-- share links are looked up by the ID inside their signed token, revoked_at disables a link before it expires
+CREATE TABLE IF NOT EXISTS app.invoice_shares (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    invoice_id BIGINT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    first_viewed_at TIMESTAMP WITH TIME ZONE,
+    last_viewed_at TIMESTAMP WITH TIME ZONE,
+    view_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (author_id, invoice_id) REFERENCES app.invoices(author_id, id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_invoice_shares_invoice ON app.invoice_shares(author_id, invoice_id);
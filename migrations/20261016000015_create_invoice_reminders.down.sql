@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS app.idx_invoices_open_due_date;
+DROP TABLE IF EXISTS app.invoice_reminders;
@@ -0,0 +1,21 @@
+-- one row per reminder and channel, stored before the message goes out so restarts never send it twice
+CREATE TABLE IF NOT EXISTS app.invoice_reminders (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    invoice_id BIGINT NOT NULL,
+    offset_days INT NOT NULL,
+    channel VARCHAR(20) NOT NULL DEFAULT '',
+    recipient TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    error TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    FOREIGN KEY (author_id, invoice_id) REFERENCES app.invoices(author_id, id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_invoice_reminders_unique ON app.invoice_reminders(author_id, invoice_id, offset_days, channel);
+
+-- the overdue job and reminder lookups scan open invoices by due date
+CREATE INDEX IF NOT EXISTS idx_invoices_open_due_date ON app.invoices(due_date) WHERE deleted_at IS NULL AND status IN ('sent', 'partially_paid', 'overdue');
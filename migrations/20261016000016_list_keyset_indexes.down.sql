@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS app.idx_credit_notes_author_created_at;
+
+DROP INDEX IF EXISTS app.idx_quotes_author;
+CREATE INDEX idx_quotes_author ON app.quotes(author_id) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS app.idx_recurring_invoices_author;
+CREATE INDEX idx_recurring_invoices_author ON app.recurring_invoices(author_id) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS app.idx_customers_author_name;
+CREATE INDEX idx_customers_author_name ON app.customers(author_id, name) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS app.idx_invoices_author_due_date;
+DROP INDEX IF EXISTS app.idx_invoices_author_deleted_at;
+DROP INDEX IF EXISTS app.idx_invoices_author_created_at;
+CREATE INDEX idx_invoices_author_deleted_at ON app.invoices(author_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_invoices_author_created_at ON app.invoices(author_id, created_at DESC) WHERE deleted_at IS NULL;
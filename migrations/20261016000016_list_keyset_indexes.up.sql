@@ -0,0 +1,17 @@
+-- cursor pages seek on the sort keys of a list, id breaks ties so every row has one position
+DROP INDEX IF EXISTS app.idx_invoices_author_created_at;
+DROP INDEX IF EXISTS app.idx_invoices_author_deleted_at;
+CREATE INDEX idx_invoices_author_created_at ON app.invoices(author_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_invoices_author_deleted_at ON app.invoices(author_id, deleted_at DESC, id DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_invoices_author_due_date ON app.invoices(author_id, (COALESCE(due_date, '0001-01-01 00:00:00+00')), id) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS app.idx_customers_author_name;
+CREATE INDEX idx_customers_author_name ON app.customers(author_id, name, id) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS app.idx_recurring_invoices_author;
+CREATE INDEX idx_recurring_invoices_author ON app.recurring_invoices(author_id, name, id) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS app.idx_quotes_author;
+CREATE INDEX idx_quotes_author ON app.quotes(author_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+CREATE INDEX idx_credit_notes_author_created_at ON app.credit_notes(author_id, created_at DESC, id DESC);
@@ -0,0 +1,23 @@
+-- background invoice exports, the scheduler claims pending rows and writes the file
+CREATE TABLE IF NOT EXISTS app.invoice_exports (
+    id SERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    rows VARCHAR(20) NOT NULL DEFAULT 'invoice',
+    locale VARCHAR(10) NOT NULL DEFAULT 'id-ID',
+    filters TEXT NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    row_count INT NOT NULL DEFAULT 0,
+    file_path TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_invoice_exports_author ON app.invoice_exports(author_id, created_at DESC);
+CREATE INDEX idx_invoice_exports_queue ON app.invoice_exports(status, id) WHERE status IN ('pending', 'running');
+CREATE INDEX idx_invoice_exports_expires ON app.invoice_exports(expires_at) WHERE file_path <> '';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS app.idx_invoices_import_ref;
+
+ALTER TABLE app.invoices
+    DROP COLUMN IF EXISTS import_ref;
@@ -0,0 +1,5 @@
+-- one invoice per import reference, so uploading the same file twice never duplicates invoices
+ALTER TABLE app.invoices
+    ADD COLUMN import_ref TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_invoices_import_ref ON app.invoices(author_id, import_ref) WHERE import_ref <> '';
@@ -0,0 +1,14 @@
+-- immutable snapshots of an invoice after each change, rows are only ever inserted
+CREATE TABLE IF NOT EXISTS app.invoice_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    invoice_id BIGINT NOT NULL,
+    revision INT NOT NULL,
+    event VARCHAR(20) NOT NULL,
+    actor_id INT NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (author_id, invoice_id) REFERENCES app.invoices(author_id, id)
+);
+
+CREATE UNIQUE INDEX idx_invoice_revisions_revision ON app.invoice_revisions(author_id, invoice_id, revision);
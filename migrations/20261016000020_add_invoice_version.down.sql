@@ -0,0 +1,2 @@
+ALTER TABLE app.invoices
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,3 @@
+-- counts the changes of an invoice, updates sent with a stale version are refused
+ALTER TABLE app.invoices
+    ADD COLUMN version INT NOT NULL DEFAULT 1;
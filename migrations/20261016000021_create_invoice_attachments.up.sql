@@ -0,0 +1,15 @@
+-- supporting documents of invoices, the files are kept in the blob store under blob_key
+CREATE TABLE IF NOT EXISTS app.invoice_attachments (
+    id BIGSERIAL PRIMARY KEY,
+    author_id INT NOT NULL,
+    invoice_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size INT NOT NULL,
+    blob_key VARCHAR(255) NOT NULL,
+    append_to_pdf BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (author_id, invoice_id) REFERENCES app.invoices(author_id, id)
+);
+
+CREATE INDEX idx_invoice_attachments_invoice ON app.invoice_attachments(author_id, invoice_id);
@@ -0,0 +1,4 @@
+ALTER TABLE auth.users
+    DROP COLUMN IF EXISTS brand_color,
+    DROP COLUMN IF EXISTS pdf_locale,
+    DROP COLUMN IF EXISTS pdf_template;
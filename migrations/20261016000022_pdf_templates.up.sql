@@ -0,0 +1,5 @@
+-- Empty settings render with the classic template in Indonesian with the default brand color.
+ALTER TABLE auth.users
+    ADD COLUMN pdf_template VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN pdf_locale VARCHAR(10) NOT NULL DEFAULT '',
+    ADD COLUMN brand_color VARCHAR(7) NOT NULL DEFAULT '';